@@ -0,0 +1,53 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic escribe data en un archivo temporal del mismo directorio,
+// lo sincroniza a disco y lo renombra sobre path, de modo que una caída a
+// mitad de la escritura nunca deja el archivo destino truncado. Si se indica
+// owner, el archivo se asigna a ese uid/gid antes de renombrarlo
+func writeFileAtomic(path string, data []byte, perm os.FileMode, owner ...fileOwner) (err error) {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	for _, o := range owner {
+		if err = os.Chown(tmp.Name(), o.uid, o.gid); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// sincronizar el directorio para persistir el rename (no disponible en todos los sistemas)
+	if d, errDir := os.Open(dir); errDir == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
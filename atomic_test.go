@@ -0,0 +1,41 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestAtomicWritesLeaveNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	web := *compose.NewService("web").SetImage("nginx")
+	config, _ := compose.NewCompose("3.8", web)
+	if err := config.SaveIfDifferent(filepath.Join(dir, "docker-compose.yml")); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if err := compose.AddEnvToFile("KEY", "value", filepath.Join(dir, ".env"), filepath.Join(dir, ".gitignore")); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Error leyendo directorio: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 3 {
+		t.Errorf("Archivos inesperados en el directorio: %q", names)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "docker-compose.yml"))
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Permisos incorrectos: %v %v", info, err)
+	}
+}
@@ -0,0 +1,63 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// backupHelperImage es la imagen del contenedor desechable usado para respaldos
+const backupHelperImage = "alpine:3"
+
+// volumeName devuelve el nombre real del volumen en docker, que compose
+// prefija con el nombre del proyecto
+func (c *composeConfig) volumeName(name string) string {
+	return c.project() + "_" + name
+}
+
+// BackupVolume guarda el contenido del volumen con nombre en un tar.gz
+// usando un contenedor desechable, sin necesidad de scripts propios
+func (c *composeConfig) BackupVolume(ctx context.Context, name, tarPath string) error {
+	dir, file, err := splitAbs(tarPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = runDocker(ctx, "run", "--rm",
+		"-v", c.volumeName(name)+":/volume:ro",
+		"-v", dir+":/backup",
+		backupHelperImage,
+		"tar", "czf", "/backup/"+file, "-C", "/volume", ".")
+	if err != nil {
+		return fmt.Errorf("error backing up volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// RestoreVolume reemplaza el contenido del volumen con nombre por el de un
+// tar.gz creado con BackupVolume. Los servicios que lo usan deberían estar detenidos
+func (c *composeConfig) RestoreVolume(ctx context.Context, name, tarPath string) error {
+	dir, file, err := splitAbs(tarPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = runDocker(ctx, "run", "--rm",
+		"-v", c.volumeName(name)+":/volume",
+		"-v", dir+":/backup:ro",
+		backupHelperImage,
+		"sh", "-c", "find /volume -mindepth 1 -delete && tar xzf /backup/"+shellQuote(file)+" -C /volume")
+	if err != nil {
+		return fmt.Errorf("error restoring volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// splitAbs separa una ruta en directorio absoluto y nombre de archivo
+func splitAbs(path string) (string, string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Dir(abs), filepath.Base(abs), nil
+}
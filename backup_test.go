@@ -0,0 +1,41 @@
+package compose_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestBackupAndRestoreVolume(t *testing.T) {
+	log := fakeDocker(t, "")
+
+	dir := filepath.Join(t.TempDir(), "My-Stack")
+	db := *compose.NewService("db").SetImage("postgres:16")
+	config, _ := compose.NewCompose("3.8", db)
+	if err := config.SaveIfDifferent(filepath.Join(t.TempDir(), "docker-compose.yml")); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	tarPath := filepath.Join(dir, "pgdata.tar.gz")
+	if err := config.BackupVolume(context.Background(), "pgdata", tarPath); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if err := config.RestoreVolume(context.Background(), "pgdata", tarPath); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	calls := dockerCalls(t, log)
+	if len(calls) != 2 {
+		t.Fatalf("Llamadas inesperadas: %q", calls)
+	}
+
+	if !strings.Contains(calls[0], "_pgdata:/volume:ro -v "+dir+":/backup alpine:3 tar czf /backup/pgdata.tar.gz") {
+		t.Errorf("Respaldo incorrecto: %s", calls[0])
+	}
+	if !strings.Contains(calls[1], "tar xzf /backup/'pgdata.tar.gz' -C /volume") {
+		t.Errorf("Restauración incorrecta: %s", calls[1])
+	}
+}
@@ -0,0 +1,153 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// windowsDrivePattern captura la letra de unidad de rutas como C:\data o C:/data
+var windowsDrivePattern = regexp.MustCompile(`^([A-Za-z]):[\\/]`)
+
+// errBindSource aclara que una ruta del host debe ser explícita
+var errBindSource = fmt.Errorf("%w bind mount source (must be absolute or start with ./ or ~)", ErrInvalidValue)
+
+// WindowsPathStyle indica cómo se escriben las rutas de Windows en los bind mounts
+type WindowsPathStyle int
+
+const (
+	// WindowsPathPOSIX convierte C:\data en /c/data, la forma que entienden
+	// Docker Desktop y WSL (por defecto)
+	WindowsPathPOSIX WindowsPathStyle = iota
+	// WindowsPathNative conserva C:\data con separadores \, para contenedores Windows
+	WindowsPathNative
+)
+
+// BindMountOptions controla cómo se escriben los orígenes de los bind mounts
+type BindMountOptions struct {
+	// ExpandHome reemplaza ~ por el directorio del usuario al generar, en
+	// lugar de dejarlo para docker compose
+	ExpandHome bool
+	// ExpandEnv resuelve ${VAR} con el .env y el entorno al generar
+	ExpandEnv bool
+	// WindowsPaths elige la forma de las rutas con letra de unidad
+	WindowsPaths WindowsPathStyle
+}
+
+// SetBindMountOptions establece cómo se normalizan los orígenes de los bind mounts
+func (c *composeConfig) SetBindMountOptions(opts BindMountOptions) *composeConfig {
+	c.bindMounts = opts
+	return c
+}
+
+// isHostPath indica si el origen de un volumen es una ruta del host y no un
+// volumen con nombre: absoluta, relativa con ., con ~ o de Windows (C:\ o \\server)
+func isHostPath(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") ||
+		strings.HasPrefix(source, `\\`) || windowsDrivePattern.MatchString(source)
+}
+
+// validVolumeSource devuelve el error de un origen que no es ruta explícita
+// del host, variable ni nombre de volumen válido
+func validVolumeSource(source string) error {
+	if !namedVolume(source) || volumeNamePattern.MatchString(source) {
+		return nil
+	}
+	if strings.ContainsAny(source, `/\`) {
+		return errBindSource
+	}
+	return errVolumeName
+}
+
+// bindSource aplica las BindMountOptions y el estilo de rutas al origen de un volumen. Los
+// volúmenes con nombre y anónimos se devuelven sin cambios
+func (c composeConfig) bindSource(source string, style WindowsPathStyle) (string, error) {
+	if c.bindMounts.ExpandEnv && strings.Contains(source, "$") {
+		expanded, err := c.interpolate(source)
+		if err != nil {
+			return "", err
+		}
+		source = expanded
+	}
+	if !isHostPath(source) {
+		return source, nil
+	}
+	if c.bindMounts.ExpandHome {
+		expanded, err := expandHome(source)
+		if err != nil {
+			return "", err
+		}
+		source = expanded
+	}
+	return normalizeWindowsPath(source, style), nil
+}
+
+// expandHome reemplaza el ~ inicial por el directorio del usuario
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/' && rest[0] != '\\') {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return home + rest, nil
+}
+
+// normalizeWindowsPath escribe las rutas de Windows con el estilo pedido:
+// C:\data pasa a /c/data y \\server\share a //server/share en WindowsPathPOSIX,
+// o conserva la unidad con separadores \ en WindowsPathNative
+func normalizeWindowsPath(path string, style WindowsPathStyle) string {
+	drive := windowsDrivePattern.FindStringSubmatch(path)
+	if drive == nil && !strings.Contains(path, `\`) {
+		return path
+	}
+	if style == WindowsPathNative {
+		if drive == nil {
+			return path
+		}
+		return strings.ToUpper(drive[1]) + `:\` + strings.ReplaceAll(path[3:], "/", `\`)
+	}
+	path = strings.ReplaceAll(path, `\`, "/")
+	if drive != nil {
+		return "/" + strings.ToLower(drive[1]) + "/" + path[3:]
+	}
+	return path
+}
+
+// validateBindMounts revisa que los orígenes de los bind mounts se puedan
+// resolver y, con checkExists, que existan en disco. Las rutas relativas se
+// resuelven desde el directorio del archivo compose
+func (c *composeConfig) validateBindMounts(checkExists bool) []error {
+	var errs []error
+	for _, s := range c.services {
+		for _, vol := range s.volumes {
+			source, err := c.bindSource(vol.Source, c.pathStyle(s))
+			if err != nil {
+				errs = append(errs, &ValidationError{Service: s.name, Field: "volumes", Value: vol.Source, Err: err})
+				continue
+			}
+			if !checkExists || !isHostPath(vol.Source) {
+				continue
+			}
+			if source, err = c.interpolate(source); err == nil {
+				source, err = expandHome(source)
+			}
+			if err != nil {
+				errs = append(errs, &ValidationError{Service: s.name, Field: "volumes", Value: vol.Source, Err: err})
+				continue
+			}
+			if !filepath.IsAbs(source) && !strings.HasPrefix(source, "/") {
+				source = filepath.Join(filepath.Dir(c.composeFile()), source)
+			}
+			if _, err := os.Stat(source); errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, &ValidationError{Service: s.name, Field: "volumes", Value: vol.Source, Err: ErrBindSourceNotFound})
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,130 @@
+package compose_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+// serviceVolumes devuelve los volúmenes del servicio name en el YAML generado
+func serviceVolumes(t *testing.T, data []byte, name string) []string {
+	t.Helper()
+	var result struct {
+		Services map[string]struct {
+			Volumes []string `yaml:"volumes"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+	return result.Services[name].Volumes
+}
+
+func TestBindMountWindowsPaths(t *testing.T) {
+	t.Run("POSIX por defecto", func(t *testing.T) {
+		app := *compose.NewService("app").SetImage("app:1.0").
+			AddVolume(compose.Volume{Source: `C:\Users\dev\src`, Target: "/src"}).
+			AddVolume(compose.Volume{Source: `.\config`, Target: "/config"}).
+			AddVolume(compose.Volume{Source: "./data", Target: "/data"})
+		config, _ := compose.NewCompose("3.8", app)
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		got := strings.Join(serviceVolumes(t, data, "app"), ",")
+		if got != "/c/Users/dev/src:/src,./config:/config,./data:/data" {
+			t.Errorf("Volúmenes incorrectos: %s", got)
+		}
+		if strings.Contains(string(data), "\nvolumes:") {
+			t.Errorf("Las rutas de Windows no son volúmenes con nombre:\n%s", data)
+		}
+	})
+
+	t.Run("Nativo", func(t *testing.T) {
+		app := *compose.NewService("app").SetImage("app:1.0").
+			AddVolume(compose.Volume{Source: "c:/Users/dev/src", Target: `C:\src`})
+		config, _ := compose.NewCompose("3.8", app)
+		config.SetBindMountOptions(compose.BindMountOptions{WindowsPaths: compose.WindowsPathNative})
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		got := strings.Join(serviceVolumes(t, data, "app"), ",")
+		if got != `C:\Users\dev\src:C:\src` {
+			t.Errorf("Volúmenes incorrectos: %s", got)
+		}
+	})
+}
+
+func TestBindMountExpand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MEDIA_DIR", "/srv/media")
+
+	app := *compose.NewService("app").SetImage("app:1.0").
+		AddVolume(compose.Volume{Source: "~/.ssh", Target: "/root/.ssh"}).
+		AddVolume(compose.Volume{Source: "${MEDIA_DIR}/photos", Target: "/photos"})
+	config, _ := compose.NewCompose("3.8", app)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got := strings.Join(serviceVolumes(t, data, "app"), ","); got != "~/.ssh:/root/.ssh,${MEDIA_DIR}/photos:/photos" {
+		t.Errorf("Sin opciones los orígenes no deben cambiar: %s", got)
+	}
+
+	config.SetBindMountOptions(compose.BindMountOptions{ExpandHome: true, ExpandEnv: true})
+	data, err = config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	want := home + "/.ssh:/root/.ssh,/srv/media/photos:/photos"
+	if got := strings.Join(serviceVolumes(t, data, "app"), ","); got != want {
+		t.Errorf("Se esperaba %s, se obtuvo %s", want, got)
+	}
+}
+
+func TestBindMountValidation(t *testing.T) {
+	app := *compose.NewService("app").SetImage("app:1.0").
+		AddVolume(compose.Volume{Source: "data/db", Target: "/db"})
+	if err := app.Err(); !errors.Is(err, compose.ErrInvalidValue) || !strings.Contains(err.Error(), "must be absolute or start with ./") {
+		t.Errorf("Se esperaba el error de ruta relativa sin ./, se obtuvo %v", err)
+	}
+}
+
+func TestBindMountCheckExists(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	os.Mkdir("html", 0755)
+	web := *compose.NewService("web").SetImage("nginx").
+		AddVolume(compose.Volume{Source: "./html", Target: "/usr/share/nginx/html"}).
+		AddVolume(compose.Volume{Source: "./certs", Target: "/etc/nginx/certs"}).
+		AddVolume(compose.Volume{Source: "cache", Target: "/var/cache/nginx"})
+	config, _ := compose.NewCompose("3.8", web)
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Sin CheckBindMounts no se revisa el disco: %v", err)
+	}
+
+	err := config.Validate(compose.CheckBindMounts)
+	if !errors.Is(err, compose.ErrBindSourceNotFound) {
+		t.Fatalf("Se esperaba ErrBindSourceNotFound, se obtuvo %v", err)
+	}
+	if !strings.Contains(err.Error(), `"./certs"`) || strings.Contains(err.Error(), "html") {
+		t.Errorf("Solo ./certs debería faltar: %v", err)
+	}
+
+	os.Mkdir(filepath.Join(".", "certs"), 0755)
+	if err := config.Validate(compose.CheckBindMounts); err != nil {
+		t.Errorf("Error inesperado: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package compose
+
+import "strings"
+
+// Clone devuelve una copia independiente del servicio con otro nombre, para
+// usarlo como plantilla (por ejemplo worker-1..worker-N) sin compartir mapas ni
+// slices. El container_name y los sidecars se renombran si seguían al nombre
+func (s *service) Clone(newName string) *service {
+	out := cloneService(*s)
+	out.name = newName
+	if s.containerName == s.name {
+		out.containerName = newName
+	}
+
+	oldPrefix, newPrefix := s.name+"-", newName+"-"
+	out.sidecars = nil
+	for _, sidecar := range s.sidecars {
+		c := cloneService(*sidecar)
+		if rest, ok := strings.CutPrefix(c.name, oldPrefix); ok {
+			c.name = newPrefix + rest
+		}
+		if rest, ok := strings.CutPrefix(c.containerName, oldPrefix); ok {
+			c.containerName = newPrefix + rest
+		}
+		c.networkMode = "service:" + newName
+		for i, dep := range c.serviceDependencies {
+			if dep == s.name {
+				c.serviceDependencies[i] = newName
+			}
+		}
+		out.sidecars = append(out.sidecars, &c)
+	}
+	return &out
+}
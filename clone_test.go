@@ -0,0 +1,44 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestClone(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	template := compose.NewService("worker").
+		SetImage("myworker").
+		AddEnvironment("QUEUE", "default").
+		AddPort("", "9000").
+		AddSidecar(compose.NewService("envoy").SetImage("envoyproxy/envoy"))
+
+	w1 := template.Clone("worker-1").AddEnvironment("QUEUE", "high")
+	w2 := template.Clone("worker-2")
+	w2.AddVolume(compose.Volume{Source: "./w2", Target: "/data"})
+
+	config, _ := compose.NewCompose("3.8", *w1, *w2)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if got := serviceOrder(t, data); got != "worker-1,worker-1-envoy,worker-2,worker-2-envoy" {
+		t.Errorf("Servicios incorrectos: %s", got)
+	}
+	for _, want := range []string{`container_name: "worker-1"`, `network_mode: "service:worker-2"`, `"QUEUE": "high"`, `"QUEUE": "default"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Falta %s en:\n%s", want, data)
+		}
+	}
+
+	// la plantilla no cambia
+	original, _ := compose.NewCompose("3.8", *template)
+	data, _ = original.Bytes()
+	if strings.Contains(string(data), "high") || strings.Contains(string(data), "./w2") {
+		t.Errorf("Clone compartió datos con la plantilla:\n%s", data)
+	}
+}
@@ -0,0 +1,45 @@
+package compose
+
+// CommandOption configura los argumentos devueltos por CommandArgs
+type CommandOption func(*commandOptions)
+
+type commandOptions struct {
+	profiles []string
+	args     []string
+}
+
+// WithProfiles activa los perfiles indicados con --profile
+func WithProfiles(profiles ...string) CommandOption {
+	return func(o *commandOptions) {
+		o.profiles = append(o.profiles, profiles...)
+	}
+}
+
+// WithArgs añade argumentos tras el subcomando, por ejemplo WithArgs("-d", "--wait")
+func WithArgs(args ...string) CommandOption {
+	return func(o *commandOptions) {
+		o.args = append(o.args, args...)
+	}
+}
+
+// CommandArgs devuelve el argv completo de "docker compose <subcommand>" con
+// los mismos archivos -f, --env-file y -p que usan Up, Logs y el resto de
+// comandos, para quien gestiona la ejecución del proceso por su cuenta:
+//
+//	argv := config.CommandArgs("up", compose.WithProfiles("debug"), compose.WithArgs("-d"))
+//	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+func (c *composeConfig) CommandArgs(subcommand string, opts ...CommandOption) []string {
+	o := commandOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var args []string
+	for _, profile := range o.profiles {
+		args = append(args, "--profile", profile)
+	}
+	args = append(args, subcommand)
+	args = append(args, o.args...)
+
+	return append([]string{"docker"}, c.composeArgs(args...)...)
+}
@@ -0,0 +1,32 @@
+package compose_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestCommandArgs(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	config, _ := compose.NewCompose("3.8", db)
+
+	t.Run("Solo el subcomando", func(t *testing.T) {
+		got := config.CommandArgs("ps")
+		want := []string{"docker", "compose", "-f", "docker-compose.yml", "ps"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Argumentos incorrectos: %q", got)
+		}
+	})
+
+	t.Run("Proyecto, perfiles y argumentos", func(t *testing.T) {
+		config.SetProjectName("shop")
+
+		got := config.CommandArgs("up", compose.WithProfiles("debug", "tools"), compose.WithArgs("-d", "db"))
+		want := []string{"docker", "compose", "-f", "docker-compose.yml", "-p", "shop",
+			"--profile", "debug", "--profile", "tools", "up", "-d", "db"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Argumentos incorrectos:\nEsperado: %q\nObtenido: %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package compose
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PreserveComments hace que Save conserve los comentarios escritos a mano en
+// el archivo existente: se trasladan a las mismas claves del YAML generado, cuyos
+// valores prevalecen. Implica aceptar las ediciones a mano (como OverwriteEdits),
+// ya que comentar el archivo es justamente editarlo
+func PreserveComments() SaveOption {
+	return func(o *saveOptions) {
+		o.preserveComments = true
+	}
+}
+
+// mergeComments copia en generated (con cabecera) los comentarios de current.
+// Devuelve generated sin cambios si current no tiene comentarios propios
+func (c *composeConfig) mergeComments(generated, current []byte) ([]byte, error) {
+	var src yaml.Node
+	if err := yaml.Unmarshal(stripGeneratedHeader(current), &src); err != nil || !hasComments(&src) {
+		// un archivo que no se puede leer no tiene comentarios que rescatar
+		return generated, nil
+	}
+
+	var dst yaml.Node
+	if err := yaml.Unmarshal(stripGeneratedHeader(generated), &dst); err != nil {
+		return nil, err
+	}
+	copyComments(&dst, &src)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&dst); err != nil {
+		return nil, err
+	}
+	return c.withHeader(c.lintYAML(buf.Bytes())), nil
+}
+
+// stripGeneratedHeader quita las líneas de cabecera de archivo generado, estén
+// donde estén, para que no se dupliquen como comentarios
+func stripGeneratedHeader(data []byte) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if strings.TrimRight(line, "\r\n") == strings.TrimSuffix(generatedHeader, "\n") || strings.HasPrefix(line, checksumPrefix) {
+			continue
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, ""))
+}
+
+// hasComments indica si algún nodo del árbol tiene comentarios
+func hasComments(n *yaml.Node) bool {
+	if n.HeadComment != "" || n.LineComment != "" || n.FootComment != "" {
+		return true
+	}
+	for _, child := range n.Content {
+		if hasComments(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyComments recorre ambos árboles en paralelo y copia los comentarios de src
+// a los nodos equivalentes de dst: las claves de mapas se emparejan por nombre,
+// los elementos escalares de listas por valor y el resto por posición
+func copyComments(dst, src *yaml.Node) {
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+
+	if dst.Kind != src.Kind {
+		return
+	}
+
+	switch dst.Kind {
+	case yaml.DocumentNode:
+		if len(dst.Content) > 0 && len(src.Content) > 0 {
+			copyComments(dst.Content[0], src.Content[0])
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			for j := 0; j+1 < len(src.Content); j += 2 {
+				if dst.Content[i].Value == src.Content[j].Value {
+					copyComments(dst.Content[i], src.Content[j])
+					copyComments(dst.Content[i+1], src.Content[j+1])
+					break
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range dst.Content {
+			if match := matchSequenceItem(item, i, src.Content); match != nil {
+				copyComments(item, match)
+			}
+		}
+	}
+}
+
+// matchSequenceItem busca en items el equivalente de item, que está en la posición i
+func matchSequenceItem(item *yaml.Node, i int, items []*yaml.Node) *yaml.Node {
+	if item.Kind == yaml.ScalarNode {
+		for _, candidate := range items {
+			if candidate.Kind == yaml.ScalarNode && candidate.Value == item.Value {
+				return candidate
+			}
+		}
+		return nil
+	}
+	if i < len(items) {
+		return items[i]
+	}
+	return nil
+}
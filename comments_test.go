@@ -0,0 +1,65 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestPreserveComments(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	db := *compose.NewService("db").SetImage("postgres:16").AddPort("5432", "5432").
+		AddEnvironment("POSTGRES_DB", "app")
+	api := *compose.NewService("api").SetImage("api:1.0").SetCommand("serve", "--verbose")
+
+	config, _ := compose.NewCompose("3.8", db, api)
+	if _, err := config.Save(context.Background(), compose.SaveTo(path)); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	// comentar el archivo a mano
+	data := string(readFile(t, path))
+	data = strings.Replace(data, "services:\n", "# servicios de la tienda\nservices:\n", 1)
+	data = strings.Replace(data, `    image: "postgres:16"`, `    image: "postgres:16" # actualizar con cuidado`, 1)
+	data = strings.Replace(data, `      - "serve"`, "      # subcomando principal\n      - \"serve\"", 1)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// sin PreserveComments el archivo comentado cuenta como editado
+	config.AddService(*compose.NewService("cache").SetImage("redis:7"))
+	if _, err := config.Save(context.Background(), compose.SaveTo(path)); err == nil {
+		t.Fatal("Se esperaba ErrManuallyEdited sin PreserveComments")
+	}
+
+	result, err := config.Save(context.Background(), compose.SaveTo(path), compose.PreserveComments())
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if result.Status != compose.SaveUpdated {
+		t.Errorf("Estado inesperado: %v", result.Status)
+	}
+
+	data = string(readFile(t, path))
+	for _, want := range []string{"# servicios de la tienda", "# actualizar con cuidado", "# subcomando principal", "cache:", "redis:7"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Falta %q en el archivo:\n%s", want, data)
+		}
+	}
+	if strings.Count(data, "# generated by") != 1 {
+		t.Errorf("La cabecera no debe duplicarse:\n%s", data)
+	}
+
+	// volver a guardar sin cambios no reescribe el archivo
+	result, err = config.Save(context.Background(), compose.SaveTo(path), compose.PreserveComments())
+	if err != nil || result.Status != compose.SaveUnchanged {
+		t.Errorf("Se esperaba SaveUnchanged, se obtuvo %v (%v)", result.Status, err)
+	}
+}
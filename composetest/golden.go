@@ -0,0 +1,165 @@
+package composetest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// updateEnv permite actualizar los archivos golden sin tocar los flags del
+// paquete de tests
+const updateEnv = "COMPOSETEST_UPDATE"
+
+// updateGolden indica si se pidió reescribir los archivos golden con
+// COMPOSETEST_UPDATE=1 o con el flag -update que defina el paquete de tests.
+// composetest no registra el flag para no chocar con el del paquete que lo importa
+func updateGolden() bool {
+	if os.Getenv(updateEnv) == "1" {
+		return true
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// YAMLConfig es lo que AssertYAMLEqual necesita de una configuración; la
+// cumple el valor devuelto por compose.NewCompose
+type YAMLConfig interface {
+	Bytes() ([]byte, error)
+}
+
+// AssertYAMLEqual compara el YAML generado por config con el archivo golden
+// en path, por ejemplo "testdata/expected.yml". La comparación es semántica:
+// ignora comentarios (incluida la cabecera con la suma), formato y orden de las
+// claves, y cada diferencia se informa con su ruta, como
+// "services.web.image". Con COMPOSETEST_UPDATE=1, o "go test -update" si el
+// paquete de tests define ese flag, el archivo se reescribe con el YAML
+// generado en lugar de compararse
+func AssertYAMLEqual(t testing.TB, config YAMLConfig, path string) {
+	t.Helper()
+
+	got, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("composetest: generating YAML: %v", err)
+	}
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("composetest: updating golden file: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("composetest: updating golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("composetest: reading golden file (run with COMPOSETEST_UPDATE=1 to create it): %v", err)
+	}
+
+	var wantValue, gotValue any
+	if err := yaml.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("composetest: parsing golden file %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("composetest: parsing generated YAML: %v", err)
+	}
+
+	if diffs := diffYAML("", wantValue, gotValue); len(diffs) > 0 {
+		t.Errorf("composetest: generated YAML differs from %s (run with COMPOSETEST_UPDATE=1 to accept it):\n  %s",
+			path, strings.Join(diffs, "\n  "))
+	}
+}
+
+// diffYAML recorre ambos valores y devuelve una línea por diferencia
+func diffYAML(path string, want, got any) []string {
+	wantMap, wantIsMap := want.(map[string]any)
+	gotMap, gotIsMap := got.(map[string]any)
+	if wantIsMap && gotIsMap {
+		var diffs []string
+		for _, key := range unionKeys(wantMap, gotMap) {
+			w, inWant := wantMap[key]
+			g, inGot := gotMap[key]
+			child := joinPath(path, key)
+			switch {
+			case !inGot:
+				diffs = append(diffs, fmt.Sprintf("%s: missing, want %s", child, formatValue(w)))
+			case !inWant:
+				diffs = append(diffs, fmt.Sprintf("%s: unexpected %s", child, formatValue(g)))
+			default:
+				diffs = append(diffs, diffYAML(child, w, g)...)
+			}
+		}
+		return diffs
+	}
+
+	wantList, wantIsList := want.([]any)
+	gotList, gotIsList := got.([]any)
+	if wantIsList && gotIsList && len(wantList) == len(gotList) {
+		var diffs []string
+		for i := range wantList {
+			diffs = append(diffs, diffYAML(fmt.Sprintf("%s[%d]", path, i), wantList[i], gotList[i])...)
+		}
+		return diffs
+	}
+
+	if reflect.DeepEqual(want, got) {
+		return nil
+	}
+	if path == "" {
+		path = "(root)"
+	}
+	return []string{fmt.Sprintf("%s: want %s, got %s", path, formatValue(want), formatValue(got))}
+}
+
+// unionKeys devuelve las claves de ambos mapas ordenadas
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath añade key a la ruta separada por puntos
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// formatValue muestra un valor en YAML de flujo, en una sola línea
+func formatValue(v any) string {
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	setFlowStyle(node)
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// setFlowStyle fuerza el estilo de flujo en todo el árbol
+func setFlowStyle(n *yaml.Node) {
+	n.Style |= yaml.FlowStyle
+	for _, child := range n.Content {
+		setFlowStyle(child)
+	}
+}
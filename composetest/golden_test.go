@@ -0,0 +1,100 @@
+package composetest_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"github.com/cdvelop/compose/composetest"
+)
+
+// update es el flag habitual de los tests golden; composetest no debe
+// registrarlo por su cuenta para que este paquete pueda definirlo
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// recorder captura los fallos de una aserción sin terminar el test real
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func TestAssertYAMLEqual(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "testdata", "expected.yml")
+
+	web := *compose.NewService("web").SetImage("nginx:1.27").AddPort("8080", "80")
+	config, _ := compose.NewCompose("3.8", web)
+
+	t.Run("Update crea el archivo", func(t *testing.T) {
+		t.Setenv("COMPOSETEST_UPDATE", "1")
+		composetest.AssertYAMLEqual(t, config, golden)
+
+		if _, err := os.Stat(golden); err != nil {
+			t.Fatalf("Se esperaba el archivo golden: %v", err)
+		}
+	})
+
+	t.Run("Igual ignorando formato, orden y comentarios", func(t *testing.T) {
+		equivalent := "# escrito a mano\nservices:\n  web: {ports: [\"8080:80\"], container_name: web, image: nginx:1.27}\nversion: \"3.8\"\n"
+		os.WriteFile(golden, []byte(equivalent), 0644)
+
+		r := &recorder{TB: t}
+		composetest.AssertYAMLEqual(r, config, golden)
+		if len(r.errors) > 0 || r.fatal != "" {
+			t.Errorf("No se esperaban fallos: %q %q", r.errors, r.fatal)
+		}
+	})
+
+	t.Run("Diferencias con su ruta", func(t *testing.T) {
+		changed := "version: \"3.8\"\nservices:\n  web:\n    image: nginx:1.25\n    container_name: web\n    ports: [\"8080:80\"]\n    restart: always\n"
+		os.WriteFile(golden, []byte(changed), 0644)
+
+		r := &recorder{TB: t}
+		composetest.AssertYAMLEqual(r, config, golden)
+		if len(r.errors) != 1 {
+			t.Fatalf("Se esperaba un fallo, se obtuvo %q", r.errors)
+		}
+		for _, want := range []string{"services.web.image: want nginx:1.25, got nginx:1.27", "services.web.restart: missing, want always"} {
+			if !strings.Contains(r.errors[0], want) {
+				t.Errorf("Falta %q en el fallo:\n%s", want, r.errors[0])
+			}
+		}
+	})
+
+	t.Run("Flag -update del paquete de tests", func(t *testing.T) {
+		os.Remove(golden)
+		flag.Set("update", "true")
+		defer flag.Set("update", "false")
+
+		composetest.AssertYAMLEqual(t, config, golden)
+		if !*update {
+			t.Fatal("Se esperaba el flag -update activo")
+		}
+		if _, err := os.Stat(golden); err != nil {
+			t.Errorf("Se esperaba el archivo golden: %v", err)
+		}
+	})
+
+	t.Run("Sin archivo golden", func(t *testing.T) {
+		r := &recorder{TB: t}
+		composetest.AssertYAMLEqual(r, config, filepath.Join(dir, "missing.yml"))
+		if !strings.Contains(r.fatal, "COMPOSETEST_UPDATE=1") {
+			t.Errorf("Se esperaba un fallo indicando COMPOSETEST_UPDATE: %q", r.fatal)
+		}
+	})
+}
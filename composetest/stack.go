@@ -0,0 +1,77 @@
+// Package composetest convierte una configuración de compose en un arnés de
+// tests de integración: levanta el stack aislado, espera a que esté sano,
+// expone los puertos asignados y lo elimina al terminar el test.
+package composetest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cdvelop/compose"
+)
+
+// defaultStartTimeout limita el arranque cuando el test no tiene deadline
+const defaultStartTimeout = 5 * time.Minute
+
+// Config es lo que StartStack necesita de una configuración; la cumple el
+// valor devuelto por compose.NewCompose
+type Config interface {
+	MakeEphemeral(t compose.TestingT) error
+	Up(ctx context.Context, opts ...compose.UpOption) error
+	HostPort(service, containerPort string) string
+}
+
+// Stack es un stack levantado por StartStack
+type Stack struct {
+	t      testing.TB
+	config Config
+}
+
+// StartStack escribe la configuración en un directorio temporal con un nombre
+// de proyecto único y puertos del host libres, ejecuta "docker compose up" y
+// espera a que todos los servicios estén sanos (o en ejecución si no tienen
+// healthcheck). El stack se elimina con sus volúmenes en t.Cleanup. Cualquier
+// fallo termina el test con t.Fatal
+func StartStack(t testing.TB, config Config, opts ...compose.UpOption) *Stack {
+	t.Helper()
+
+	if err := config.MakeEphemeral(t); err != nil {
+		t.Fatalf("composetest: preparing stack: %v", err)
+	}
+
+	deadline := time.Now().Add(defaultStartTimeout)
+	if d, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+		if testDeadline, ok := d.Deadline(); ok {
+			deadline = testDeadline
+		}
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	opts = append([]compose.UpOption{compose.WithWaitFor()}, opts...)
+	if err := config.Up(ctx, opts...); err != nil {
+		t.Fatalf("composetest: starting stack: %v", err)
+	}
+	return &Stack{t: t, config: config}
+}
+
+// HostPort devuelve el puerto del host publicado para el puerto del contenedor.
+// Termina el test si el servicio no lo publica
+func (s *Stack) HostPort(service, containerPort string) string {
+	s.t.Helper()
+
+	port := s.config.HostPort(service, containerPort)
+	if port == "" {
+		s.t.Fatalf("composetest: service %q does not publish port %s", service, containerPort)
+	}
+	return port
+}
+
+// Addr devuelve la dirección host:puerto para conectarse al puerto del
+// contenedor desde el test, por ejemplo "127.0.0.1:49321"
+func (s *Stack) Addr(service, containerPort string) string {
+	s.t.Helper()
+	return net.JoinHostPort("127.0.0.1", s.HostPort(service, containerPort))
+}
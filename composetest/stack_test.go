@@ -0,0 +1,57 @@
+package composetest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"github.com/cdvelop/compose/composetest"
+)
+
+// fakeDocker instala un ejecutable docker que registra sus argumentos y
+// anuncia soporte de "up --wait"
+func fakeDocker(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+	script := "#!/bin/sh\necho \"$@\" >> " + logPath + "\ncase \"$*\" in *\"up --help\"*) echo \"  --wait\";; esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatalf("Error creando docker falso: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+func TestStartStack(t *testing.T) {
+	log := fakeDocker(t)
+
+	t.Run("stack", func(t *testing.T) {
+		db := *compose.NewService("db").SetImage("postgres:16").AddPort("5432", "5432")
+		config, _ := compose.NewCompose("3.8", db)
+
+		stack := composetest.StartStack(t, config)
+
+		port := stack.HostPort("db", "5432")
+		if port == "5432" {
+			t.Errorf("Se esperaba un puerto aleatorio, se obtuvo %s", port)
+		}
+		if addr := stack.Addr("db", "5432"); addr != "127.0.0.1:"+port {
+			t.Errorf("Dirección incorrecta: %s", addr)
+		}
+	})
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(calls) != 3 || !strings.HasSuffix(calls[1], "up -d --wait") || !strings.HasSuffix(calls[2], "down -v --remove-orphans") {
+		t.Errorf("Invocaciones inesperadas: %q", calls)
+	}
+	if !strings.Contains(calls[1], "-p eph-teststartstack-stack-") {
+		t.Errorf("Se esperaba un proyecto único: %q", calls[1])
+	}
+}
@@ -0,0 +1,67 @@
+package compose_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestConcurrentBuild(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	config, _ := compose.NewCompose("3.8")
+
+	// Validate, Bytes, GenerateOnly y Set leen los servicios mientras otras goroutines los añaden
+	done := make(chan struct{})
+	readers := make(chan struct{})
+	go func() {
+		defer close(readers)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				config.Validate()
+				config.Bytes()
+				config.GenerateOnly("worker-0")
+				config.Set("version", "3.8")
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("worker-%d", i)
+			s := compose.NewService(name).
+				SetImage("myworker").
+				AddEnvironment(fmt.Sprintf("WORKER_%d_TOKEN", i), "secret")
+			config.AddService(*s)
+		}(i)
+	}
+	wg.Wait()
+	close(done)
+	<-readers
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got := len(serviceNames(t, data)); got != 20 {
+		t.Errorf("Se esperaban 20 servicios, se obtuvieron %d", got)
+	}
+
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	keys, err := compose.ListEnvFromFile()
+	if err != nil || len(keys) != 20 {
+		t.Errorf("Se perdieron variables del .env: %d %v", len(keys), err)
+	}
+}
@@ -0,0 +1,176 @@
+package compose
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeKind indica el tipo de un cambio entre dos configuraciones
+type ChangeKind int
+
+const (
+	ServiceAdded   ChangeKind = iota // el servicio solo existe en la nueva configuración
+	ServiceRemoved                   // el servicio solo existe en la anterior
+	FieldAdded                       // el campo solo existe en la nueva configuración
+	FieldRemoved                     // el campo solo existe en la anterior
+	FieldChanged                     // el campo cambió de valor
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ServiceAdded:
+		return "service added"
+	case ServiceRemoved:
+		return "service removed"
+	case FieldAdded:
+		return "field added"
+	case FieldRemoved:
+		return "field removed"
+	default:
+		return "field changed"
+	}
+}
+
+// Change describe una diferencia entre dos configuraciones. Los valores son
+// los del YAML generado (string, int, bool, []any o map[string]any)
+type Change struct {
+	Kind    ChangeKind
+	Service string // vacío para las secciones superiores (networks, configs, name...)
+	Field   string // clave del compose, con "." para las claves de mapas: "environment.DB_HOST"
+	Old     any
+	New     any
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ServiceAdded, ServiceRemoved:
+		return fmt.Sprintf("%s: %s", c.Service, c.Kind)
+	}
+	prefix := c.Field
+	if c.Service != "" {
+		prefix = c.Service + "." + c.Field
+	}
+	switch c.Kind {
+	case FieldAdded:
+		return fmt.Sprintf("%s: added %v", prefix, c.New)
+	case FieldRemoved:
+		return fmt.Sprintf("%s: removed %v", prefix, c.Old)
+	default:
+		return fmt.Sprintf("%s: %v -> %v", prefix, c.Old, c.New)
+	}
+}
+
+// ChangeSet es la lista de cambios entre dos configuraciones, ordenada por
+// servicio y campo
+type ChangeSet []Change
+
+// Empty indica si las configuraciones generan el mismo compose
+func (cs ChangeSet) Empty() bool {
+	return len(cs) == 0
+}
+
+// ChangedServices devuelve los servicios nuevos o modificados, por ejemplo para
+// ejecutar "docker compose up" solo sobre ellos. No incluye los eliminados
+func (cs ChangeSet) ChangedServices() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, c := range cs {
+		if c.Service == "" || c.Kind == ServiceRemoved || seen[c.Service] {
+			continue
+		}
+		seen[c.Service] = true
+		names = append(names, c.Service)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffConfigs compara lo que generan a y b y devuelve los servicios añadidos o
+// eliminados y los campos que cambiaron con sus valores anterior y nuevo
+func DiffConfigs(a, b *composeConfig) (ChangeSet, error) {
+	before, err := decodeConfig(a)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeConfig(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes ChangeSet
+
+	oldServices, _ := before["services"].(map[string]any)
+	newServices, _ := after["services"].(map[string]any)
+	for _, name := range unionKeys(oldServices, newServices) {
+		oldService, inOld := oldServices[name].(map[string]any)
+		newService, inNew := newServices[name].(map[string]any)
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: ServiceAdded, Service: name, New: newService})
+		case !inNew:
+			changes = append(changes, Change{Kind: ServiceRemoved, Service: name, Old: oldService})
+		default:
+			changes = append(changes, diffFields(name, "", oldService, newService)...)
+		}
+	}
+
+	delete(before, "services")
+	delete(after, "services")
+	changes = append(changes, diffFields("", "", before, after)...)
+
+	return changes, nil
+}
+
+// decodeConfig genera el YAML de c y lo decodifica en mapas
+func decodeConfig(c *composeConfig) (map[string]any, error) {
+	data, err := c.body()
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]any)
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// diffFields compara dos mapas clave a clave. Los mapas anidados de primer
+// nivel (environment, sysctls...) se comparan también por clave
+func diffFields(service, prefix string, before, after map[string]any) []Change {
+	var changes []Change
+	for _, key := range unionKeys(before, after) {
+		field := prefix + key
+		oldValue, inOld := before[key]
+		newValue, inNew := after[key]
+
+		oldMap, oldIsMap := oldValue.(map[string]any)
+		newMap, newIsMap := newValue.(map[string]any)
+
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Kind: FieldAdded, Service: service, Field: field, New: newValue})
+		case !inNew:
+			changes = append(changes, Change{Kind: FieldRemoved, Service: service, Field: field, Old: oldValue})
+		case oldIsMap && newIsMap && prefix == "":
+			changes = append(changes, diffFields(service, field+".", oldMap, newMap)...)
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, Change{Kind: FieldChanged, Service: service, Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// unionKeys devuelve las claves de ambos mapas ordenadas
+func unionKeys(a, b map[string]any) []string {
+	keys := sortedKeys(a)
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
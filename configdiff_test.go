@@ -0,0 +1,53 @@
+package compose_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *compose.NewService("db").SetImage("postgres:15").AddEnvironment("POSTGRES_DB", "app")
+	api := *compose.NewService("api").SetImage("api:1.0")
+	worker := *compose.NewService("worker").SetImage("worker:1.0")
+	before, _ := compose.NewCompose("3.8", db, api, worker)
+
+	db2 := *compose.NewService("db").SetImage("postgres:16").
+		AddEnvironment("POSTGRES_DB", "app").
+		AddEnvironment("POSTGRES_USER", "admin")
+	cache := *compose.NewService("cache").SetImage("redis:7")
+	after, _ := compose.NewCompose("3.8", db2, api, cache)
+	after.SetProjectName("shop")
+
+	changes, err := compose.DiffConfigs(before, after)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	want := []string{
+		"cache: service added",
+		"db.environment.POSTGRES_USER: added admin",
+		"db.image: postgres:15 -> postgres:16",
+		"worker: service removed",
+		"name: added shop",
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cambios incorrectos:\n got: %q\nwant: %q", got, want)
+	}
+
+	if services := changes.ChangedServices(); !reflect.DeepEqual(services, []string{"cache", "db"}) {
+		t.Errorf("Servicios modificados incorrectos: %v", services)
+	}
+
+	same, err := compose.DiffConfigs(before, before)
+	if err != nil || !same.Empty() {
+		t.Errorf("Una configuración no debe diferir de sí misma: %v %v", same, err)
+	}
+}
@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InlineConfig representa un archivo de configuración embebido en el docker-compose
+// mediante "configs: content:", sin necesidad de mantener el archivo en disco
+type InlineConfig struct {
+	Name    string // nombre del config a nivel superior
+	Target  string // ruta dentro del contenedor, por defecto /<Name>
+	Content string // contenido del archivo
+}
+
+// AddConfig embebe un archivo de configuración en el servicio
+func (s *service) AddConfig(config InlineConfig) *service {
+	if config.Name == "" {
+		s.errors = append(s.errors, invalidField(s.name, "config_name", config.Name))
+		return s
+	}
+	s.configs = append(s.configs, config)
+	return s
+}
+
+// collectConfigs reúne los configs de todos los servicios para la sección superior,
+// respetando el orden de aparición y sin repetir nombres
+func (c composeConfig) collectConfigs() ([]InlineConfig, error) {
+	var out []InlineConfig
+	seen := make(map[string]string)
+
+	for _, s := range c.services {
+		for _, config := range s.configs {
+			content, exists := seen[config.Name]
+			if !exists {
+				seen[config.Name] = config.Content
+				out = append(out, config)
+				continue
+			}
+			if content != config.Content {
+				return nil, fmt.Errorf("config %q is defined with different content", config.Name)
+			}
+		}
+	}
+	return out, nil
+}
+
+// writeBlockScalar escribe un texto multilínea como bloque literal YAML (heredoc)
+func writeBlockScalar(b *strings.Builder, indent string, content string) {
+	if !blockScalarSafe(content) {
+		b.WriteString(yamlQuote(content) + "\n")
+		return
+	}
+
+	// con la primera línea no vacía sangrada hay que indicar la sangría
+	header := "|"
+	if first := strings.TrimLeft(content, "\n"); strings.HasPrefix(first, " ") {
+		header += "2"
+	}
+	if !strings.HasSuffix(content, "\n") {
+		header += "-"
+	} else if strings.HasSuffix(content, "\n\n") {
+		header += "+"
+	}
+	b.WriteString(header + "\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(indent + line + "\n")
+	}
+}
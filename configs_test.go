@@ -0,0 +1,59 @@
+package compose_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInlineConfigs(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	const nginxConf = "server {\n    listen 80;\n    return 200 \"ok\";\n}\n"
+
+	web := *compose.NewService("web").
+		SetImage("nginx").
+		AddEnvironment("GREETING", "hola\nmundo").
+		AddConfig(compose.InlineConfig{
+			Name:    "nginx_conf",
+			Target:  "/etc/nginx/conf.d/default.conf",
+			Content: nginxConf,
+		})
+
+	config, _ := compose.NewCompose("3.8", web)
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	if err := config.SaveIfDifferent(path); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Environment map[string]string `yaml:"environment"`
+			Configs     []struct {
+				Source string `yaml:"source"`
+				Target string `yaml:"target"`
+			} `yaml:"configs"`
+		} `yaml:"services"`
+		Configs map[string]struct {
+			Content string `yaml:"content"`
+		} `yaml:"configs"`
+	}
+	if err := yaml.Unmarshal(readFile(t, path), &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	if got := result.Configs["nginx_conf"].Content; got != nginxConf {
+		t.Errorf("Contenido incorrecto:\nEsperado: %q\nObtenido: %q", nginxConf, got)
+	}
+
+	refs := result.Services["web"].Configs
+	if len(refs) != 1 || refs[0].Source != "nginx_conf" || refs[0].Target != "/etc/nginx/conf.d/default.conf" {
+		t.Errorf("Referencia de config incorrecta: %+v", refs)
+	}
+
+	if got := result.Services["web"].Environment["GREETING"]; got != "hola\nmundo" {
+		t.Errorf("Variable multilínea incorrecta: %q", got)
+	}
+}
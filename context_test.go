@@ -0,0 +1,56 @@
+package compose_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestContextVariants(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	web := *compose.NewService("web").SetImage("nginx").AddPort("", "80")
+	config, _ := compose.NewCompose("3.8", web)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("Cancelados", func(t *testing.T) {
+		if err := config.SaveIfDifferentContext(canceled); !errors.Is(err, context.Canceled) {
+			t.Errorf("SaveIfDifferentContext: se esperaba context.Canceled, se obtuvo %v", err)
+		}
+		if _, err := config.SaveIfChangedContext(canceled); !errors.Is(err, context.Canceled) {
+			t.Errorf("SaveIfChangedContext: se esperaba context.Canceled, se obtuvo %v", err)
+		}
+		if _, err := config.DiffContext(canceled, "docker-compose.yml"); !errors.Is(err, context.Canceled) {
+			t.Errorf("DiffContext: se esperaba context.Canceled, se obtuvo %v", err)
+		}
+		if _, err := config.SaveQuadletContext(canceled, "units"); !errors.Is(err, context.Canceled) {
+			t.Errorf("SaveQuadletContext: se esperaba context.Canceled, se obtuvo %v", err)
+		}
+		if _, err := os.Stat("docker-compose.yml"); !os.IsNotExist(err) {
+			t.Error("No se esperaba escribir el archivo con el contexto cancelado")
+		}
+		if files, _ := filepath.Glob("units/*"); len(files) != 0 {
+			t.Errorf("No se esperaban unidades escritas: %q", files)
+		}
+	})
+
+	t.Run("Con contexto activo", func(t *testing.T) {
+		status, err := config.SaveIfChangedContext(context.Background())
+		if err != nil || status != compose.SaveCreated {
+			t.Fatalf("Se esperaba SaveCreated: %v %v", status, err)
+		}
+		diff, err := config.DiffContext(context.Background(), "docker-compose.yml")
+		if err != nil || diff != "" {
+			t.Errorf("Se esperaba un diff vacío: %q %v", diff, err)
+		}
+		if err := config.CheckHostPortsContext(context.Background()); err != nil {
+			t.Errorf("Error inesperado: %v", err)
+		}
+	})
+}
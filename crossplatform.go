@@ -0,0 +1,121 @@
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// LineEnding indica el fin de línea de los archivos YAML generados
+type LineEnding int
+
+const (
+	// LineEndingLF usa \n, lo habitual en Linux y macOS (por defecto)
+	LineEndingLF LineEnding = iota
+	// LineEndingCRLF usa \r\n, para repositorios de Windows con core.autocrlf
+	LineEndingCRLF
+)
+
+// SetLineEnding elige el fin de línea del compose y sus overrides. La suma de
+// la cabecera se calcula siempre sobre el contenido con \n, así un archivo no
+// se considera editado a mano solo porque git le cambió los fines de línea
+func (c *composeConfig) SetLineEnding(ending LineEnding) *composeConfig {
+	c.lineEnding = ending
+	return c
+}
+
+// withHeader antepone la cabecera de archivo generado y aplica el fin de línea
+// elegido con SetLineEnding
+func (c *composeConfig) withHeader(body []byte) []byte {
+	data := withHeader(bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n")))
+	if c.lineEnding == LineEndingCRLF {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	return data
+}
+
+// Modos de aislamiento de contenedores Windows aceptados por SetIsolation
+const (
+	IsolationDefault = "default"
+	IsolationProcess = "process"
+	IsolationHyperV  = "hyperv"
+)
+
+// SetIsolation establece el aislamiento del contenedor (isolation), solo
+// significativo en contenedores Windows. Un servicio con aislamiento o con
+// plataforma "windows/..." escribe sus volúmenes con rutas nativas de Windows
+func (s *service) SetIsolation(mode string) *service {
+	switch mode {
+	case IsolationDefault, IsolationProcess, IsolationHyperV:
+		s.isolation = mode
+	default:
+		s.errors = append(s.errors, invalidField(s.name, "isolation", mode))
+	}
+	return s
+}
+
+// CredentialSpec indica la especificación de credenciales gMSA de un
+// contenedor Windows. Se debe usar exactamente uno de los campos
+type CredentialSpec struct {
+	File     string // archivo en el directorio CredentialSpecs de docker
+	Registry string // valor del registro de Windows
+	Config   string // nombre de un config del compose
+}
+
+// SetCredentialSpec establece credential_spec para que el contenedor Windows
+// se autentique en el dominio con una cuenta gMSA
+func (s *service) SetCredentialSpec(spec CredentialSpec) *service {
+	set := 0
+	for _, v := range []string{spec.File, spec.Registry, spec.Config} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		s.errors = append(s.errors, invalidField(s.name, "credential_spec", fmt.Sprintf("%+v", spec)))
+		return s
+	}
+	s.credentialSpec = &spec
+	return s
+}
+
+// windowsContainer indica si el servicio corre en un contenedor Windows
+func (s service) windowsContainer() bool {
+	return s.isolation != "" || strings.HasPrefix(s.platform, "windows/")
+}
+
+// pathStyle devuelve el estilo de rutas de los volúmenes del servicio: los
+// contenedores Windows usan siempre rutas nativas
+func (c composeConfig) pathStyle(s service) WindowsPathStyle {
+	if s.windowsContainer() {
+		return WindowsPathNative
+	}
+	return c.bindMounts.WindowsPaths
+}
+
+// volumeTarget devuelve el destino del volumen; en contenedores Windows las
+// rutas con unidad, como C:/data, se escriben con separadores \
+func volumeTarget(s service, target string) string {
+	if !s.windowsContainer() {
+		return target
+	}
+	return normalizeWindowsPath(target, WindowsPathNative)
+}
+
+// writeWindowsFields escribe isolation y credential_spec
+func writeWindowsFields(b *strings.Builder, s service) {
+	if s.isolation != "" {
+		fmt.Fprintf(b, "    isolation: %s\n", yamlQuote(s.isolation))
+	}
+	if spec := s.credentialSpec; spec != nil {
+		b.WriteString("    credential_spec:\n")
+		switch {
+		case spec.File != "":
+			fmt.Fprintf(b, "      file: %s\n", yamlQuote(spec.File))
+		case spec.Registry != "":
+			fmt.Fprintf(b, "      registry: %s\n", yamlQuote(spec.Registry))
+		default:
+			fmt.Fprintf(b, "      config: %s\n", yamlQuote(spec.Config))
+		}
+	}
+}
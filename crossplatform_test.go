@@ -0,0 +1,96 @@
+package compose_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestLineEndingCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	web := *compose.NewService("web").SetImage("nginx").AddPort("8080", "80")
+	config, _ := compose.NewCompose("3.8", web)
+	config.SetLineEnding(compose.LineEndingCRLF)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if bytes.Count(data, []byte("\n")) != bytes.Count(data, []byte("\r\n")) {
+		t.Errorf("Todas las líneas deben terminar en \\r\\n:\n%q", data)
+	}
+
+	if _, err := config.Save(context.Background(), compose.SaveTo(path)); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	result, err := config.Save(context.Background(), compose.SaveTo(path))
+	if err != nil || result.Status != compose.SaveUnchanged {
+		t.Errorf("Se esperaba SaveUnchanged, se obtuvo %v (%v)", result.Status, err)
+	}
+
+	// un checkout que vuelve a \n no cuenta como edición manual
+	lf := bytes.ReplaceAll(readFile(t, path), []byte("\r\n"), []byte("\n"))
+	os.WriteFile(path, lf, 0644)
+	result, err = config.Save(context.Background(), compose.SaveTo(path))
+	if err != nil || result.Status != compose.SaveUpdated {
+		t.Errorf("Se esperaba SaveUpdated, se obtuvo %v (%v)", result.Status, err)
+	}
+}
+
+func TestWindowsContainer(t *testing.T) {
+	iis := *compose.NewService("iis").SetImage("mcr.microsoft.com/windows/servercore/iis").
+		SetPlatform("windows/amd64").
+		SetIsolation(compose.IsolationHyperV).
+		SetCredentialSpec(compose.CredentialSpec{File: "webapp.json"}).
+		AddVolume(compose.Volume{Source: "C:/inetpub/logs", Target: "c:/inetpub/logs"})
+	api := *compose.NewService("api").SetImage("api:1.0").
+		AddVolume(compose.Volume{Source: `C:\src\api`, Target: "/app"})
+
+	config, _ := compose.NewCompose("3.8", iis, api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	for _, want := range []string{
+		"    isolation: \"hyperv\"\n",
+		"    credential_spec:\n      file: \"webapp.json\"\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Falta %q en:\n%s", want, data)
+		}
+	}
+	if got := strings.Join(serviceVolumes(t, data, "iis"), ","); got != `C:\inetpub\logs:C:\inetpub\logs` {
+		t.Errorf("El contenedor Windows debe usar rutas nativas: %s", got)
+	}
+	if got := strings.Join(serviceVolumes(t, data, "api"), ","); got != "/c/src/api:/app" {
+		t.Errorf("El contenedor Linux debe usar rutas POSIX: %s", got)
+	}
+
+	if err := config.Validate(compose.SchemaStrict); err != nil {
+		t.Errorf("No se esperaban errores de validación: %v", err)
+	}
+}
+
+func TestWindowsContainerInvalid(t *testing.T) {
+	s := compose.NewService("app").SetImage("app:1.0").
+		SetIsolation("container").
+		SetCredentialSpec(compose.CredentialSpec{File: "a.json", Registry: "b"})
+
+	err := s.Err()
+	if !errors.Is(err, compose.ErrInvalidValue) {
+		t.Fatalf("Se esperaba ErrInvalidValue, se obtuvo %v", err)
+	}
+	for _, want := range []string{"invalid isolation", "invalid credential spec"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Falta %q en el error: %v", want, err)
+		}
+	}
+}
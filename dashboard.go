@@ -0,0 +1,124 @@
+package compose
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSnapshotLogLines es la cantidad de líneas de log incluidas por servicio
+const defaultSnapshotLogLines = 5
+
+// ServiceHealth resume el estado de un servicio en un instante
+type ServiceHealth struct {
+	Service      string
+	Container    string   // id del contenedor, vacío si no fue creado
+	State        string   // running, exited, restarting... o "not created"
+	Health       string   // healthy, unhealthy, starting o vacío sin healthcheck
+	RestartCount int      // reinicios del contenedor
+	LastLogs     []string // últimas líneas de log
+	Err          error    // error al consultar docker para este servicio
+}
+
+// StackHealth agrupa el estado de todos los servicios del stack
+type StackHealth struct {
+	Taken    time.Time
+	Services []ServiceHealth
+}
+
+// HealthSnapshot consulta en paralelo estado, salud, reinicios y últimos logs de
+// cada servicio. logLines limita las líneas de log por servicio (5 por defecto).
+// Pensado como fuente de datos para TUIs y dashboards
+func (c *composeConfig) HealthSnapshot(ctx context.Context, logLines ...int) StackHealth {
+	lines := defaultSnapshotLogLines
+	if len(logLines) > 0 {
+		lines = logLines[0]
+	}
+
+	snapshot := StackHealth{
+		Taken:    time.Now(),
+		Services: make([]ServiceHealth, len(c.services)),
+	}
+
+	var wg sync.WaitGroup
+	for i, s := range c.services {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			snapshot.Services[i] = c.serviceHealth(ctx, name, lines)
+		}(i, s.name)
+	}
+	wg.Wait()
+
+	return snapshot
+}
+
+// WatchHealth toma un HealthSnapshot cada interval y lo entrega a fn
+// hasta que se cancela el contexto
+func (c *composeConfig) WatchHealth(ctx context.Context, interval time.Duration, fn func(StackHealth)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(c.HealthSnapshot(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceHealth consulta docker para un servicio
+func (c *composeConfig) serviceHealth(ctx context.Context, name string, logLines int) ServiceHealth {
+	h := ServiceHealth{Service: name}
+
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+	if id == "" {
+		h.State = "not created"
+		return h
+	}
+	h.Container = id
+
+	out, err := runDocker(ctx, "inspect", "--format",
+		"{{.State.Status}}|{{if .State.Health}}{{.State.Health.Status}}{{end}}|{{.RestartCount}}", id)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "|", 3)
+	if len(parts) == 3 {
+		h.State, h.Health = parts[0], parts[1]
+		h.RestartCount, _ = strconv.Atoi(parts[2])
+	}
+
+	if logLines > 0 {
+		out, err := c.runCompose(ctx, "logs", "--no-color", "--no-log-prefix", "--tail", strconv.Itoa(logLines), name)
+		if err != nil {
+			h.Err = err
+			return h
+		}
+		if text := strings.TrimRight(string(out), "\n"); text != "" {
+			h.LastLogs = strings.Split(text, "\n")
+		}
+	}
+	return h
+}
+
+// containerID devuelve el id del contenedor de un servicio o vacío si no existe
+func (c *composeConfig) containerID(ctx context.Context, name string) (string, error) {
+	out, err := c.runCompose(ctx, "ps", "-q", name)
+	if err != nil {
+		return "", err
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return id, nil
+}
@@ -0,0 +1,38 @@
+package compose_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestHealthSnapshot(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *" ps -q db") echo db123;;
+  *" ps -q "*) ;;
+  inspect*) echo "running|healthy|2";;
+  *" logs "*) printf 'ready\naccepting connections\n';;
+esac`)
+
+	db := *compose.NewService("db").SetImage("postgres:16")
+	api := *compose.NewService("api").SetImage("api")
+	config, _ := compose.NewCompose("3.8", db, api)
+
+	snapshot := config.HealthSnapshot(context.Background(), 2)
+	if len(snapshot.Services) != 2 {
+		t.Fatalf("Cantidad de servicios incorrecta: %d", len(snapshot.Services))
+	}
+
+	got := snapshot.Services[0]
+	if got.Err != nil || got.Container != "db123" || got.State != "running" || got.Health != "healthy" || got.RestartCount != 2 {
+		t.Errorf("Estado de db incorrecto: %+v", got)
+	}
+	if len(got.LastLogs) != 2 || got.LastLogs[1] != "accepting connections" {
+		t.Errorf("Logs incorrectos: %q", got.LastLogs)
+	}
+
+	if api := snapshot.Services[1]; api.State != "not created" || api.Container != "" {
+		t.Errorf("Estado de api incorrecto: %+v", api)
+	}
+}
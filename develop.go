@@ -0,0 +1,82 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WatchAction es la acción de docker compose watch al detectar un cambio
+type WatchAction string
+
+const (
+	// WatchSync copia los archivos modificados al contenedor
+	WatchSync WatchAction = "sync"
+	// WatchRebuild reconstruye la imagen y recrea el contenedor
+	WatchRebuild WatchAction = "rebuild"
+	// WatchSyncRestart copia los archivos y reinicia el contenedor
+	WatchSyncRestart WatchAction = "sync+restart"
+)
+
+// WatchRule es una regla de develop.watch: Path del host vigilado, Action a
+// ejecutar, Target dentro del contenedor (requerido por las acciones sync) e
+// Ignore con patrones relativos a Path que no disparan la acción
+type WatchRule struct {
+	Path   string
+	Action WatchAction
+	Target string
+	Ignore []string
+}
+
+// SetDevelopWatch reemplaza las reglas de develop.watch del servicio, usadas por
+// "docker compose watch" para recargar en caliente stacks de desarrollo.
+// Requiere EnableFeature(FeatureDevelop)
+func (s *service) SetDevelopWatch(rules ...WatchRule) *service {
+	var valid []WatchRule
+	for _, r := range rules {
+		switch {
+		case r.Path == "":
+			s.errors = append(s.errors, invalidField(s.name, "develop.watch.path", r.Path))
+		case r.Action != WatchSync && r.Action != WatchRebuild && r.Action != WatchSyncRestart:
+			s.errors = append(s.errors, invalidField(s.name, "develop.watch.action", string(r.Action)))
+		case r.Action != WatchRebuild && r.Target == "":
+			s.errors = append(s.errors, fmt.Errorf("service %q: watch action %s on %q requires a target", s.name, r.Action, r.Path))
+		default:
+			r.Ignore = append([]string(nil), r.Ignore...)
+			valid = append(valid, r)
+		}
+	}
+	s.developWatch = valid
+	return s
+}
+
+// writeDevelopWatch escribe la sección develop del servicio
+func writeDevelopWatch(b *strings.Builder, rules []WatchRule) {
+	b.WriteString("    develop:\n")
+	b.WriteString("      watch:\n")
+	for _, r := range rules {
+		fmt.Fprintf(b, "        - path: %s\n", yamlQuote(r.Path))
+		fmt.Fprintf(b, "          action: %s\n", yamlQuote(string(r.Action)))
+		if r.Target != "" {
+			fmt.Fprintf(b, "          target: %s\n", yamlQuote(r.Target))
+		}
+		if len(r.Ignore) > 0 {
+			b.WriteString("          ignore:\n")
+			for _, pattern := range r.Ignore {
+				fmt.Fprintf(b, "            - %s\n", yamlQuote(pattern))
+			}
+		}
+	}
+}
+
+// cloneWatchRules copia las reglas sin compartir las listas Ignore
+func cloneWatchRules(rules []WatchRule) []WatchRule {
+	if rules == nil {
+		return nil
+	}
+	out := make([]WatchRule, len(rules))
+	for i, r := range rules {
+		r.Ignore = append([]string(nil), r.Ignore...)
+		out[i] = r
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package compose_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDevelopWatch(t *testing.T) {
+	rules := []compose.WatchRule{
+		{Path: "./src", Action: compose.WatchSync, Target: "/app/src", Ignore: []string{"node_modules/"}},
+		{Path: "package.json", Action: compose.WatchRebuild},
+		{Path: "./config", Action: compose.WatchSyncRestart, Target: "/etc/app"},
+	}
+
+	t.Run("Emite develop.watch", func(t *testing.T) {
+		web := *compose.NewService("web").SetBuild(".").SetDevelopWatch(rules...)
+		config, _ := compose.NewCompose("3.8", web)
+		config.EnableFeature(compose.FeatureDevelop)
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		var result struct {
+			Services map[string]struct {
+				Develop struct {
+					Watch []struct {
+						Path   string   `yaml:"path"`
+						Action string   `yaml:"action"`
+						Target string   `yaml:"target"`
+						Ignore []string `yaml:"ignore"`
+					} `yaml:"watch"`
+				} `yaml:"develop"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Error parseando YAML: %v", err)
+		}
+
+		watch := result.Services["web"].Develop.Watch
+		if len(watch) != 3 {
+			t.Fatalf("Se esperaban 3 reglas, se obtuvo %d:\n%s", len(watch), data)
+		}
+		if watch[0].Path != "./src" || watch[0].Action != "sync" || watch[0].Target != "/app/src" ||
+			!reflect.DeepEqual(watch[0].Ignore, []string{"node_modules/"}) {
+			t.Errorf("Regla sync incorrecta: %+v", watch[0])
+		}
+		if watch[1].Action != "rebuild" || watch[1].Target != "" {
+			t.Errorf("Regla rebuild incorrecta: %+v", watch[1])
+		}
+		if watch[2].Action != "sync+restart" {
+			t.Errorf("Regla sync+restart incorrecta: %+v", watch[2])
+		}
+		if err := config.Validate(compose.SchemaStrict); err != nil {
+			t.Errorf("Se esperaba un archivo válido según el schema: %v", err)
+		}
+	})
+
+	t.Run("Requiere FeatureDevelop", func(t *testing.T) {
+		web := *compose.NewService("web").SetBuild(".").SetDevelopWatch(rules...)
+		config, _ := compose.NewCompose("3.8", web)
+
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "develop.watch requires") {
+			t.Errorf("Se esperaba un error por feature no activada: %v", err)
+		}
+	})
+
+	t.Run("Reglas inválidas", func(t *testing.T) {
+		web := *compose.NewService("web").SetBuild(".").SetDevelopWatch(
+			compose.WatchRule{Path: "./src", Action: "copy", Target: "/app"},
+			compose.WatchRule{Path: "./src", Action: compose.WatchSync},
+		)
+		config, _ := compose.NewCompose("3.8", web)
+		config.EnableFeature(compose.FeatureDevelop)
+
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "develop.watch.action") || !strings.Contains(err.Error(), "requires a target") {
+			t.Errorf("Se esperaban errores de reglas inválidas: %v", err)
+		}
+	})
+}
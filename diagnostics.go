@@ -0,0 +1,127 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// diagnosticsLogLines es la cantidad de líneas de log por servicio incluidas en el diagnóstico
+const diagnosticsLogLines = 200
+
+// redactedValue reemplaza los valores sensibles en el diagnóstico
+const redactedValue = "[REDACTED]"
+
+// ExportDiagnostics reúne en dir lo necesario para reportar un problema con el stack:
+// el YAML generado, la configuración resuelta por docker compose, las claves del
+// .env, la versión de docker compose y los logs recientes de los servicios.
+// Todo valor del .env o pendiente de escribirse en él se oculta, sea cual sea
+// su clave. Los pasos que fallan se registran en errors.txt sin interrumpir la exportación;
+// los errores al generar el YAML o al escribir los archivos además se devuelven
+func (c *composeConfig) ExportDiagnostics(ctx context.Context, dir string) error {
+	if err := c.files.mkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating diagnostics dir: %w", err)
+	}
+
+	envVars, err := c.files.readEnvFile(c.besideComposeFile(defaultEnvFile))
+	if err != nil {
+		return err
+	}
+	redact := secretRedactor(c.envValues(envVars))
+
+	var problems []string
+	var errs []error
+	write := func(name string, data []byte) {
+		if err := c.files.writeFile(filepath.Join(dir, name), []byte(redact(string(data))), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("error writing %s: %w", name, err))
+		}
+	}
+	capture := func(name string, out []byte, err error) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+		if len(out) > 0 {
+			write(name, out)
+		}
+	}
+
+	generated, err := c.Bytes()
+	capture("generated.yml", generated, err)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error generating compose file: %w", err))
+	}
+
+	out, err := c.runCompose(ctx, "config")
+	capture("resolved.yml", out, err)
+
+	write("env.redacted", []byte(redactedEnv(envVars)))
+
+	out, err = runDocker(ctx, "compose", "version")
+	capture("version.txt", out, err)
+
+	out, err = c.runCompose(ctx, "logs", "--no-color", "--timestamps", "--tail", fmt.Sprint(diagnosticsLogLines))
+	capture("logs.txt", out, err)
+
+	if len(problems) > 0 {
+		write("errors.txt", []byte(strings.Join(problems, "\n")+"\n"))
+	}
+	return errors.Join(errs...)
+}
+
+// envValues devuelve los valores del .env junto con los de las variables de
+// los servicios que aún no se escribieron en él, por ejemplo los literales de
+// AddEnvironment, que ya aparecen en el YAML generado
+func (c *composeConfig) envValues(envVars map[string]string) []string {
+	var values []string
+	for _, v := range envVars {
+		values = append(values, v)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.services {
+		for _, d := range s.deferredEnv {
+			if d.value != nil {
+				values = append(values, *d.value)
+			}
+		}
+	}
+	return values
+}
+
+// redactedEnv lista las variables del .env con sus valores ocultos
+func redactedEnv(envVars map[string]string) string {
+	var b strings.Builder
+	for _, k := range sortedKeys(envVars) {
+		value := envVars[k]
+		if value != "" {
+			value = redactedValue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, value)
+	}
+	return b.String()
+}
+
+// secretRedactor devuelve una función que oculta en un texto los valores
+// indicados, por ejemplo en la configuración resuelta o en los logs. Los
+// valores más largos se reemplazan primero para que uno que contenga a otro
+// no quede oculto a medias
+func secretRedactor(values []string) func(string) string {
+	var secrets []string
+	for _, v := range values {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	var pairs []string
+	for _, v := range secrets {
+		pairs = append(pairs, v, redactedValue)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	return replacer.Replace
+}
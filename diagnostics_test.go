@@ -0,0 +1,70 @@
+package compose_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestExportDiagnostics(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	if err := os.WriteFile(".env", []byte("DB_PASSWORD=hunter22\nAPI_KEY=q7\nDB_PASS=opensesame\nPWD=letmein\nDB_HOST=pg.internal\n"), 0644); err != nil {
+		t.Fatalf("Error creando .env: %v", err)
+	}
+
+	fakeDocker(t, `case "$*" in
+  *" config") echo "DB_PASSWORD: hunter22 DB_PASS: opensesame";;
+  "compose version") echo "Docker Compose version v2.27.0";;
+  *" logs "*) echo "db | login with hunter22 pin q7 pwd letmein at pg.internal"; exit 1;;
+esac`)
+
+	db := *compose.NewService("db").SetImage("postgres:16").AddEnvironment("POSTGRES_INITDB_ARGS", "--auth=sup3rlit")
+	config, _ := compose.NewCompose("3.8", db)
+
+	out := filepath.Join(work, "bundle")
+	if err := config.ExportDiagnostics(context.Background(), out); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, name := range []string{"generated.yml", "resolved.yml", "env.redacted", "version.txt", "logs.txt", "errors.txt"} {
+		files[name] = string(readFile(t, filepath.Join(out, name)))
+	}
+
+	for name, content := range files {
+		for _, secret := range []string{"hunter22", "q7", "opensesame", "letmein", "pg.internal", "sup3rlit"} {
+			if strings.Contains(content, secret) {
+				t.Errorf("%s contiene el valor %q sin ocultar: %q", name, secret, content)
+			}
+		}
+	}
+	if !strings.Contains(files["env.redacted"], "DB_HOST=[REDACTED]") || !strings.Contains(files["env.redacted"], "DB_PASSWORD=[REDACTED]") {
+		t.Errorf("env.redacted incorrecto: %q", files["env.redacted"])
+	}
+	if !strings.Contains(files["version.txt"], "v2.27.0") || !strings.Contains(files["errors.txt"], "logs.txt") {
+		t.Errorf("Diagnóstico incompleto: %v", files)
+	}
+
+	// los errores de escritura se devuelven
+	config.SetFS(failingFS{compose.NewMemFS()})
+	if err := config.ExportDiagnostics(context.Background(), out); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Se esperaba el error de escritura, se obtuvo %v", err)
+	}
+}
+
+// failingFS es un sistema de archivos en memoria que no admite escrituras
+type failingFS struct {
+	*compose.MemFS
+}
+
+func (failingFS) WriteFile(string, []byte, fs.FileMode) error {
+	return errors.New("disk full")
+}
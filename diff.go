@@ -0,0 +1,154 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// diffContextLines es la cantidad de líneas de contexto alrededor de cada cambio
+const diffContextLines = 3
+
+// diffOp es una línea del diff: ' ' sin cambios, '-' eliminada, '+' añadida
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// Diff devuelve el diff unificado entre el archivo en path y el YAML que se
+// generaría, sin escribir nada. Vacío si no hay diferencias
+func (c *composeConfig) Diff(path string) (string, error) {
+	return c.DiffContext(context.Background(), path)
+}
+
+// DiffContext es Diff con un contexto para cancelarlo
+func (c *composeConfig) DiffContext(ctx context.Context, path string) (string, error) {
+	result, err := c.Save(ctx, SaveTo(path), DryRun())
+	return result.Diff, err
+}
+
+// unifiedDiff devuelve el diff unificado entre dos textos, vacío si son iguales
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(ops); {
+		// saltar hasta el próximo cambio
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		from := max(start-diffContextLines, 0)
+
+		// extender el hunk mientras los cambios estén separados por poco contexto
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContextLines {
+				break
+			}
+			end = next
+		}
+		to := min(end+diffContextLines, len(ops))
+
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[from:to] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+
+		start = to
+	}
+	return b.String()
+}
+
+// splitLines separa un texto en líneas sin el salto final
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines calcula la secuencia de operaciones con la subsecuencia común más larga
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
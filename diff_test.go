@@ -0,0 +1,40 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	config, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.25"))
+
+	diff, err := config.Diff(path)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(diff, "+    image: \"nginx:1.25\"\n") {
+		t.Errorf("Diff incorrecto para archivo inexistente:\n%s", diff)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Diff no debe escribir el archivo")
+	}
+
+	if err := config.SaveIfDifferent(path); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if diff, _ := config.Diff(path); diff != "" {
+		t.Errorf("No se esperaba diff:\n%s", diff)
+	}
+
+	updated, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.27"))
+	diff, _ = updated.Diff(path)
+	if !strings.Contains(diff, "-    image: \"nginx:1.25\"\n+    image: \"nginx:1.27\"\n") {
+		t.Errorf("Diff incorrecto:\n%s", diff)
+	}
+}
@@ -1,45 +1,106 @@
 package compose
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 )
 
-// healthCheck representa la configuración de healthcheck
-type healthCheck struct {
-	Test     []string
-	Interval string
-	Timeout  string
-	Retries  int
+// HealthCheck representa la configuración de healthcheck
+type HealthCheck struct {
+	Test        []string
+	Interval    string
+	Timeout     string
+	Retries     int
+	StartPeriod string
 }
 
+// Service es el tipo que devuelven NewService y sus setters, para que otros
+// paquetes como presets puedan recibirlo y devolverlo
+type Service = service
+
 // service representa un servicio en docker-compose
 type service struct {
 	name                string
 	image               string
+	build               string
+	platform            string
+	pullPolicy          string
 	containerName       string
 	ports               []string
+	expose              []string
 	environment         map[string]string
+	labels              map[string]string
+	proxyHost           string
 	volumes             []Volume
+	configs             []InlineConfig
 	serviceDependencies []string
-	command             string
-	networks            []string
+	command             []string
+	commandString       string
+	networks            []networkAttachment
+	networkMode         string
+	extraHosts          []string
+	dns                 []string
+	dnsSearch           []string
+	sidecars            []*service
 	restartPolicy       string
-	healthCheck         *healthCheck
+	capAdd              []string
+	capDrop             []string
+	privileged          bool
+	readOnly            bool
+	securityOpt         []string
+	tmpfs               []string
+	ulimits             []ulimit
+	shmSize             string
+	sysctls             [][2]string
+	deviceReservations  []DeviceReservation
+	devices             []string
+	healthCheck         *HealthCheck
+	developWatch        []WatchRule
+	isolation           string
+	credentialSpec      *CredentialSpec
+	envGroup            string
+	envOverrides        map[Environment]map[string]string
+	scale               *int
+	lazyEnv             bool
+	extensions          []rawField
+	deferredEnv         []deferredEnv
 	errors              []error
 }
 
-// SetRestartPolicy establece la política de reinicio del servicio
+// Políticas de reinicio aceptadas por SetRestartPolicy
+const (
+	RestartNo            = "no"
+	RestartAlways        = "always"
+	RestartOnFailure     = "on-failure"
+	RestartUnlessStopped = "unless-stopped"
+)
+
+// RestartOnFailureRetries devuelve la política on-failure con un máximo de reintentos
+func RestartOnFailureRetries(maxRetries int) string {
+	return fmt.Sprintf("%s:%d", RestartOnFailure, maxRetries)
+}
+
+// SetRestartPolicy establece la política de reinicio del servicio.
+// Acepta las constantes Restart* o RestartOnFailureRetries; otros valores se rechazan
 func (s *service) SetRestartPolicy(policy string) *service {
+	if !validRestartPolicy(policy) {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "restart", Value: policy, Err: ErrInvalidRestartPolicy})
+		return s
+	}
 	s.restartPolicy = policy
 	return s
 }
 
 // SetHealthCheck configura el healthcheck del servicio
 func (s *service) SetHealthCheck(test []string, interval, timeout string, retries int) *service {
-	s.healthCheck = &healthCheck{
+	s.healthCheck = &HealthCheck{
 		Test:     test,
 		Interval: interval,
 		Timeout:  timeout,
@@ -48,7 +109,8 @@ func (s *service) SetHealthCheck(test []string, interval, timeout string, retrie
 	return s
 }
 
-// Volume representa un volumen en docker-compose
+// Volume representa un volumen en docker-compose. Sin Source es un volumen
+// anónimo, como los que declara VOLUME en un Dockerfile
 type Volume struct {
 	Source string `yaml:"-"`
 	Target string `yaml:"-"`
@@ -58,14 +120,58 @@ type Volume struct {
 type composeConfig struct {
 	version  string    `yaml:"version"`
 	services []service `yaml:"services"`
-	volumes  []Volume  `yaml:"volumes,omitempty"`
+	file     string    // último archivo guardado, usado por los comandos docker compose
+
+	// projectDir es el directorio del proyecto cuando el archivo se guarda fuera
+	// de él, como hace MakeEphemeral; ahí están el .env y las rutas relativas
+	projectDir string
+
+	// files indica sobre qué sistema de archivos y con qué ajustes (cifrado,
+	// permisos, .gitignore) se escriben los archivos generados
+	files fileSettings
+
+	projectName    string
+	prefixNames    bool
+	ephemeralPorts map[string]string
+	networks       map[string]NetworkConfig
+	volumes        map[string]VolumeConfig
+	bindMounts     BindMountOptions
+	lineEnding     LineEnding
+
+	envStrictness  EnvStrictness
+	warnings       []string
+	registryAuth   map[string]registryCredentials
+	features       map[Feature]bool
+	includes       []string
+	maxLineLength  *int
+	environment    Environment
+	extensions     []rawField
+	layerFiles     []string
+	serviceOrder   ServiceOrder
+	deployReplicas bool
+	target         Target
+	hooks          map[HookStage][]Hook
+	logger         *slog.Logger
+
+	// mu protege services entre AddService/RemoveService/Set... y quienes los
+	// leen: Validate, la generación (Bytes, Save, EnvironmentBytes, Quadlet,
+	// ToNomad), los filtros como GenerateOnly y los comandos docker. Los setters de la configuración
+	// (SetProjectName, EnableFeature, DefineNetwork...) no toman el lock: se
+	// llaman al preparar la configuración, antes de compartirla entre goroutines
+	mu *sync.Mutex
 }
 
-// NewCompose crea una nueva configuración de docker-compose
+// NewCompose crea una nueva configuración de docker-compose.
+// Los servicios se copian (copy-on-build): modificarlos después no afecta a la
+// configuración, por lo que cada goroutine puede construir sus propios servicios
+// y añadirlos con AddService, que es seguro para uso concurrente junto con
+// Validate y la generación. Los setters de la configuración, como
+// SetProjectName o EnableFeature, deben llamarse antes de compartirla
 func NewCompose(version string, services ...service) (*composeConfig, error) {
 	config := &composeConfig{
 		version:  version,
-		services: services,
+		services: cloneServices(expandSidecars(services)),
+		mu:       new(sync.Mutex),
 	}
 
 	return config, nil
@@ -77,85 +183,293 @@ func (c composeConfig) generateYAML() ([]byte, error) {
 
 	var out_errors []error
 	// Escribir versión
-	fmt.Fprintf(&b, "version: %q\n", c.version)
+	fmt.Fprintf(&b, "version: %s\n", yamlQuote(c.version))
+
+	if c.projectName != "" {
+		fmt.Fprintf(&b, "name: %s\n", yamlQuote(c.projectName))
+	}
+
+	if len(c.includes) > 0 && c.featureEnabled(FeatureInclude) {
+		b.WriteString("include:\n")
+		for _, path := range c.includes {
+			fmt.Fprintf(&b, "  - %s\n", yamlQuote(path))
+		}
+	}
+
+	if err := writeRawFields(&b, "", c.extensions); err != nil {
+		out_errors = append(out_errors, err)
+	}
 
 	// Escribir servicios
 	b.WriteString("services:\n")
-	for _, service := range c.services {
+	for _, service := range c.orderedServices() {
 
 		if len(service.errors) > 0 {
 			out_errors = append(out_errors, service.errors...)
 			continue
 		}
 
-		fmt.Fprintf(&b, "  %s:\n", service.containerName)
-		fmt.Fprintf(&b, "    image: %q\n", service.image)
+		fmt.Fprintf(&b, "  %s:\n", yamlPlain(service.name))
+
+		if service.image != "" {
+			fmt.Fprintf(&b, "    image: %s\n", yamlQuote(service.image))
+		}
+
+		if service.build != "" && !c.swarm() {
+			fmt.Fprintf(&b, "    build: %s\n", yamlQuote(service.build))
+		}
 
-		if service.containerName != "" {
-			fmt.Fprintf(&b, "    container_name: %q\n", service.containerName)
+		if service.platform != "" {
+			fmt.Fprintf(&b, "    platform: %s\n", yamlQuote(service.platform))
+		}
+
+		if service.pullPolicy != "" {
+			fmt.Fprintf(&b, "    pull_policy: %s\n", yamlQuote(service.pullPolicy))
+		}
+
+		if name := c.containerNameFor(service); name != "" && !c.swarm() {
+			fmt.Fprintf(&b, "    container_name: %s\n", yamlQuote(name))
 		}
 
 		if len(service.ports) > 0 {
 			b.WriteString("    ports:\n")
 			for _, port := range service.ports {
-				fmt.Fprintf(&b, "      - \"%s\"\n", port)
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(port))
+			}
+		}
+
+		if len(service.expose) > 0 {
+			b.WriteString("    expose:\n")
+			for _, port := range service.expose {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(port))
 			}
 		}
 
 		if len(service.environment) > 0 {
 			b.WriteString("    environment:\n")
-			for key, value := range service.environment {
-				fmt.Fprintf(&b, "      %q: %q\n", key, value)
+			for _, key := range sortedKeys(service.environment) {
+				value := service.environment[key]
+				if strings.Contains(value, "\n") {
+					fmt.Fprintf(&b, "      %s: ", yamlQuote(key))
+					writeBlockScalar(&b, "        ", value)
+					continue
+				}
+				fmt.Fprintf(&b, "      %s: %s\n", yamlQuote(key), yamlQuote(value))
 			}
 		}
 
 		if len(service.volumes) > 0 {
 			b.WriteString("    volumes:\n")
 			for _, vol := range service.volumes {
-				fmt.Fprintf(&b, "      - %s:%s\n", vol.Source, vol.Target)
+				spec := volumeTarget(service, vol.Target)
+				if vol.Source != "" {
+					source, err := c.bindSource(vol.Source, c.pathStyle(service))
+					if err != nil {
+						out_errors = append(out_errors, &ValidationError{Service: service.name, Field: "volumes", Value: vol.Source, Err: err})
+					}
+					spec = source + ":" + spec
+				}
+				fmt.Fprintf(&b, "      - %s\n", yamlPlain(spec))
+			}
+		}
+
+		if len(service.configs) > 0 {
+			b.WriteString("    configs:\n")
+			for _, config := range service.configs {
+				fmt.Fprintf(&b, "      - source: %s\n", yamlQuote(config.Name))
+				if config.Target != "" {
+					fmt.Fprintf(&b, "        target: %s\n", yamlQuote(config.Target))
+				}
 			}
 		}
 
-		if len(service.serviceDependencies) > 0 {
+		if len(service.serviceDependencies) > 0 && !c.swarm() {
 			b.WriteString("    depends_on:\n")
 			for _, dep := range service.serviceDependencies {
-				fmt.Fprintf(&b, "      - %q\n", dep)
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(dep))
 			}
 		}
 
-		if service.command != "" {
-			fmt.Fprintf(&b, "    command: %q\n", service.command)
+		if service.commandString != "" {
+			fmt.Fprintf(&b, "    command: %s\n", yamlQuote(service.commandString))
+		} else if len(service.command) > 0 {
+			b.WriteString("    command:\n")
+			for _, arg := range service.command {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(arg))
+			}
 		}
 
 		if len(service.networks) > 0 {
-			b.WriteString("    networks:\n")
-			for _, net := range service.networks {
-				fmt.Fprintf(&b, "      - %q\n", net)
+			writeServiceNetworks(&b, service.networks)
+		}
+
+		if len(service.extraHosts) > 0 {
+			b.WriteString("    extra_hosts:\n")
+			for _, host := range service.extraHosts {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(host))
+			}
+		}
+
+		if len(service.dns) > 0 {
+			b.WriteString("    dns:\n")
+			for _, server := range service.dns {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(server))
+			}
+		}
+
+		if len(service.dnsSearch) > 0 {
+			b.WriteString("    dns_search:\n")
+			for _, domain := range service.dnsSearch {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(domain))
+			}
+		}
+
+		if service.networkMode != "" && !c.swarm() {
+			fmt.Fprintf(&b, "    network_mode: %s\n", yamlQuote(service.networkMode))
+		}
+
+		if len(service.capAdd) > 0 {
+			b.WriteString("    cap_add:\n")
+			for _, c := range service.capAdd {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(c))
+			}
+		}
+
+		if len(service.capDrop) > 0 {
+			b.WriteString("    cap_drop:\n")
+			for _, c := range service.capDrop {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(c))
+			}
+		}
+
+		if service.privileged && !c.swarm() {
+			b.WriteString("    privileged: true\n")
+		}
+
+		if service.readOnly {
+			b.WriteString("    read_only: true\n")
+		}
+
+		if len(service.securityOpt) > 0 && !c.swarm() {
+			b.WriteString("    security_opt:\n")
+			for _, opt := range service.securityOpt {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(opt))
+			}
+		}
+
+		writeWindowsFields(&b, service)
+
+		if len(service.tmpfs) > 0 {
+			b.WriteString("    tmpfs:\n")
+			for _, mount := range service.tmpfs {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(mount))
 			}
 		}
 
-		if service.restartPolicy != "" {
-			fmt.Fprintf(&b, "    restart: %q\n", service.restartPolicy)
+		if service.shmSize != "" {
+			fmt.Fprintf(&b, "    shm_size: %s\n", yamlQuote(service.shmSize))
+		}
+
+		if len(service.ulimits) > 0 {
+			b.WriteString("    ulimits:\n")
+			for _, u := range service.ulimits {
+				if u.soft == u.hard {
+					fmt.Fprintf(&b, "      %s: %d\n", yamlPlain(u.name), u.soft)
+					continue
+				}
+				fmt.Fprintf(&b, "      %s:\n", yamlPlain(u.name))
+				fmt.Fprintf(&b, "        soft: %d\n", u.soft)
+				fmt.Fprintf(&b, "        hard: %d\n", u.hard)
+			}
+		}
+
+		if len(service.sysctls) > 0 {
+			b.WriteString("    sysctls:\n")
+			for _, kv := range service.sysctls {
+				fmt.Fprintf(&b, "      %s: %s\n", yamlPlain(kv[0]), yamlQuote(kv[1]))
+			}
+		}
+
+		if len(service.devices) > 0 && !c.swarm() {
+			b.WriteString("    devices:\n")
+			for _, device := range service.devices {
+				fmt.Fprintf(&b, "      - %s\n", yamlQuote(device))
+			}
+		}
+
+		if c.swarm() {
+			writeSwarmDeploy(&b, service)
+		} else if service.scale != nil && !c.deployReplicas {
+			fmt.Fprintf(&b, "    scale: %d\n", *service.scale)
+		}
+
+		if !c.swarm() && (len(service.deviceReservations) > 0 || (service.scale != nil && c.deployReplicas)) {
+			b.WriteString("    deploy:\n")
+			if service.scale != nil && c.deployReplicas {
+				fmt.Fprintf(&b, "      replicas: %d\n", *service.scale)
+			}
+			if len(service.deviceReservations) > 0 {
+				writeDeviceReservations(&b, service.deviceReservations)
+			}
+		}
+
+		if service.restartPolicy != "" && !c.swarm() {
+			fmt.Fprintf(&b, "    restart: %s\n", yamlQuote(service.restartPolicy))
+		}
+
+		if len(service.labels) > 0 {
+			writeLabels(&b, service.labels)
 		}
 
 		if service.healthCheck != nil {
 			b.WriteString("    healthcheck:\n")
 			fmt.Fprintf(&b, "      test:\n")
 			for _, test := range service.healthCheck.Test {
-				fmt.Fprintf(&b, "        - %q\n", test)
+				fmt.Fprintf(&b, "        - %s\n", yamlQuote(test))
 			}
 			if service.healthCheck.Interval != "" {
-				fmt.Fprintf(&b, "      interval: %q\n", service.healthCheck.Interval)
+				fmt.Fprintf(&b, "      interval: %s\n", yamlQuote(service.healthCheck.Interval))
 			}
 			if service.healthCheck.Timeout != "" {
-				fmt.Fprintf(&b, "      timeout: %q\n", service.healthCheck.Timeout)
+				fmt.Fprintf(&b, "      timeout: %s\n", yamlQuote(service.healthCheck.Timeout))
 			}
 			if service.healthCheck.Retries > 0 {
 				fmt.Fprintf(&b, "      retries: %d\n", service.healthCheck.Retries)
 			}
+			if service.healthCheck.StartPeriod != "" {
+				fmt.Fprintf(&b, "      start_period: %s\n", yamlQuote(service.healthCheck.StartPeriod))
+			}
+		}
+
+		if len(service.developWatch) > 0 && c.featureEnabled(FeatureDevelop) && !c.swarm() {
+			writeDevelopWatch(&b, service.developWatch)
+		}
+
+		if err := writeRawFields(&b, "    ", service.extensions); err != nil {
+			out_errors = append(out_errors, err)
+		}
+	}
+
+	c.writeNetworks(&b)
+	c.writeVolumes(&b)
+
+	configs, err := c.collectConfigs()
+	if err != nil {
+		out_errors = append(out_errors, err)
+	}
+	if len(configs) > 0 {
+		b.WriteString("configs:\n")
+		for _, config := range configs {
+			fmt.Fprintf(&b, "  %s:\n", yamlPlain(config.Name))
+			b.WriteString("    content: ")
+			writeBlockScalar(&b, "      ", config.Content)
 		}
 	}
 
+	if err := c.detectCycles(); err != nil {
+		out_errors = append(out_errors, err)
+	}
+
 	if len(out_errors) > 0 {
 		return nil, errors.Join(out_errors...)
 	}
@@ -163,6 +477,16 @@ func (c composeConfig) generateYAML() ([]byte, error) {
 	return []byte(b.String()), nil
 }
 
+// sortedKeys devuelve las claves del mapa ordenadas para que la salida sea estable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // NewService crea una nueva configuración de servicio
 func NewService(name string) *service {
 	return &service{
@@ -172,7 +496,6 @@ func NewService(name string) *service {
 		environment:         make(map[string]string),
 		volumes:             []Volume{},
 		serviceDependencies: []string{},
-		networks:            []string{},
 	}
 }
 
@@ -182,9 +505,19 @@ func (s *service) SetContainerName(name string) *service {
 	return s
 }
 
-// AddPort añade un mapeo de puertos al servicio
+// AddPort añade un mapeo de puertos al servicio. host admite una IP
+// ("127.0.0.1:8080") y ambos rangos ("8000-8010"); container admite protocolo
+// ("53/udp"). Con host vacío el puerto se publica en uno aleatorio del host
 func (s *service) AddPort(host, container string) *service {
-	s.ports = append(s.ports, fmt.Sprintf("%s:%s", host, container))
+	port := container
+	if host != "" {
+		port = host + ":" + container
+	}
+	if err := validatePort(port); err != nil {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "ports", Value: port, Err: err})
+		return s
+	}
+	s.ports = append(s.ports, port)
 	return s
 }
 
@@ -192,46 +525,121 @@ func (s *service) AddPort(host, container string) *service {
 // If a value is provided, it will be used for both public and private values
 // If no value is provided, it will look for the variable in the environment
 // and use ${key} for the public value and the actual value for the private value
-// The private value will be added to the .env file
+// The private value will be added to the .env file when the config is saved
 func (s *service) AddEnvironment(key string, value ...string) *service {
-	var envPubValue, envPrivValue string
+	if s.lazyEnv && len(value) == 0 {
+		return s.deferEnv(key, nil)
+	}
 
-	if len(value) > 0 {
-		envPubValue = value[0]
-		envPrivValue = value[0]
-	} else {
-		// Buscar en variables de entorno
-		val, exists := os.LookupEnv(key)
-		if !exists {
-			s.errors = append(s.errors, fmt.Errorf("environment variable %s not found", key))
-			return s
-		}
-		// Usar ${key} para el valor público
-		envPubValue = fmt.Sprintf("${%s}", key)
-		// Usar el valor real para el privado
-		envPrivValue = val
+	envPubValue, envPrivValue, err := resolveEnvValue(s.name, key, value...)
+	if err != nil {
+		s.errors = append(s.errors, err)
+		return s
 	}
 
 	if envPrivValue != "" {
-		AddEnvToFile(key, envPrivValue)
+		s.storeEnv(deferredEnv{key: key, value: &envPrivValue})
 	}
 
 	s.environment[key] = envPubValue
 	return s
 }
 
-// AddVolume añade un volumen al servicio
+// AddEnvironmentMap adds several variables with explicit values, as if calling
+// AddEnvironment(key, value) for each one. Keys are added in alphabetical order
+func (s *service) AddEnvironmentMap(vars map[string]string) *service {
+	for _, key := range sortedKeys(vars) {
+		value := vars[key]
+		if value != "" {
+			s.storeEnv(deferredEnv{key: key, value: &value})
+		}
+		s.environment[key] = value
+	}
+	return s
+}
+
+// resolveEnvValue returns the public value for the compose file and the private
+// value for the .env file, as described in AddEnvironment
+func resolveEnvValue(service, key string, value ...string) (string, string, error) {
+	if len(value) > 0 {
+		return value[0], value[0], nil
+	}
+
+	// Buscar en variables de entorno
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		return "", "", &ValidationError{Service: service, Field: "environment", Value: key, Err: ErrEnvVarNotFound}
+	}
+	// Usar ${key} para el valor público y el valor real para el privado
+	return fmt.Sprintf("${%s}", key), val, nil
+}
+
+// SetEnvGroup agrupa en la sección indicada del .env las variables que se añadan
+// después con AddEnvironment o AddSecretEnvironment, por ejemplo "database"
+func (s *service) SetEnvGroup(group string) *service {
+	s.envGroup = group
+	return s
+}
+
+// AddVolume añade un volumen al servicio. Un origen que no es ruta del host
+// (/, ./, ~ o C:\) ni variable se toma como volumen con nombre y se declara en
+// la sección superior volumes; los nombres inválidos y las rutas relativas sin
+// ./ como "data/db" se rechazan
 func (s *service) AddVolume(volume Volume) *service {
+	if err := validVolumeSource(volume.Source); err != nil {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "volumes", Value: volume.Source, Err: err})
+		return s
+	}
 	s.volumes = append(s.volumes, volume)
 	return s
 }
 
 // SetImage establece la imagen del servicio
 func (s *service) SetImage(image string) *service {
+	if !validImageRef(image) {
+		s.errors = append(s.errors, invalidField(s.name, "image", image))
+		return s
+	}
 	s.image = image
 	return s
 }
 
+// SetCommand establece el comando del servicio en forma de lista (exec),
+// cada argumento se escribe por separado por lo que no requiere escapes de shell
+func (s *service) SetCommand(args ...string) *service {
+	s.command = append([]string{}, args...)
+	s.commandString = ""
+	return s
+}
+
+// SetCommandString establece el comando en forma de texto (command: "...").
+// docker compose lo separa en argumentos respetando comillas pero sin pasar
+// por un shell, por lo que no admite tuberías ni redirecciones
+func (s *service) SetCommandString(command string) *service {
+	args, err := splitShellWords(command)
+	if err != nil {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "command", Value: command, Err: err})
+		return s
+	}
+	s.command = args
+	s.commandString = command
+	return s
+}
+
+// SetShellCommand establece un script que se ejecuta con /bin/sh -c,
+// útil cuando se necesitan tuberías, redirecciones o varias órdenes
+func (s *service) SetShellCommand(script string) *service {
+	s.command = []string{"/bin/sh", "-c", script}
+	s.commandString = ""
+	return s
+}
+
+// SetBuild establece el contexto de build del servicio
+func (s *service) SetBuild(context string) *service {
+	s.build = context
+	return s
+}
+
 // DependsOn establece las dependencias del servicio
 func (s *service) DependsOn(services ...service) *service {
 	for _, service := range services {
@@ -240,36 +648,92 @@ func (s *service) DependsOn(services ...service) *service {
 	return s
 }
 
-// SaveIfDifferent guarda el archivo docker-compose.yml solo si es diferente del existente
-func (c *composeConfig) SaveIfDifferent(filename ...string) error {
+// Bytes valida la configuración y devuelve el YAML generado sin tocar el sistema de archivos
+func (c *composeConfig) Bytes() ([]byte, error) {
+	if err := c.runHooks(&HookEvent{Stage: BeforeGenerate}); err != nil {
+		return nil, err
+	}
 
-	composePath := "docker-compose.yml"
-	if len(filename) > 0 {
-		composePath = filename[0]
+	yamlData, err := c.body()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &HookEvent{Stage: AfterGenerate, Data: yamlData}
+	if err := c.runHooks(e); err != nil {
+		return nil, err
+	}
+	return c.withHeader(e.Data), nil
+}
+
+// body genera el YAML validado, sin la cabecera de archivo generado
+func (c *composeConfig) body() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Validar antes de escribir nada
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("configuración inválida: %w", err)
+	}
+
+	// Comprobar que las variables diferidas se pueden resolver; se escriben
+	// en el .env al guardar
+	deferred, err := c.deferredEnvKeys()
+	if err != nil {
+		return nil, err
 	}
 
 	// Generar nuevo YAML usando nuestra implementación personalizada
 	yamlData, err := c.generateYAML()
 	if err != nil {
-		return fmt.Errorf("error al generar YAML: %v", err)
+		return nil, fmt.Errorf("error al generar YAML: %v", err)
 	}
+	yamlData = c.lintYAML(yamlData)
+	c.log(slog.LevelDebug, "compose generated", "bytes", len(yamlData))
 
-	// Verificar si existe archivo actual
-	currentData, err := os.ReadFile(composePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// Si no existe, crear nuevo archivo
-			return os.WriteFile(composePath, yamlData, 0644)
-		}
-		return fmt.Errorf("error al leer archivo: %v", err)
+	// Verificar que las referencias ${VAR} estén definidas
+	c.warnings = c.swarmDropped()
+	if err := c.checkEnvReferences(yamlData, deferred); err != nil {
+		return nil, err
 	}
+	return yamlData, nil
+}
 
-	// Si el contenido es igual, no hacer nada
-	if string(currentData) == string(yamlData) {
-		return nil
+// WriteTo escribe el YAML generado en w, por ejemplo os.Stdout o un buffer en memoria
+func (c *composeConfig) WriteTo(w io.Writer) (int64, error) {
+	yamlData, err := c.Bytes()
+	if err != nil {
+		return 0, err
 	}
+	n, err := w.Write(yamlData)
+	return int64(n), err
+}
 
-	// Guardar nuevo archivo si es diferente
+// SaveIfDifferent guarda el archivo docker-compose.yml solo si es diferente del existente
+func (c *composeConfig) SaveIfDifferent(filename ...string) error {
+	return c.SaveIfDifferentContext(context.Background(), filename...)
+}
+
+// SaveIfDifferentContext es SaveIfDifferent con un contexto para cancelarlo
+// o limitar su duración, por ejemplo mientras VerifyImagesOnSave consulta el registry
+func (c *composeConfig) SaveIfDifferentContext(ctx context.Context, filename ...string) error {
+	_, err := c.SaveIfChangedContext(ctx, filename...)
+	return err
+}
+
+// SaveIfChanged es como SaveIfDifferent pero informa si el archivo se creó,
+// se actualizó o quedó igual, útil para ejecutar Up solo cuando hubo cambios
+func (c *composeConfig) SaveIfChanged(filename ...string) (SaveStatus, error) {
+	return c.SaveIfChangedContext(context.Background(), filename...)
+}
+
+// SaveIfChangedContext es SaveIfChanged con un contexto para cancelarlo
+func (c *composeConfig) SaveIfChangedContext(ctx context.Context, filename ...string) (SaveStatus, error) {
+	opts := []SaveOption{}
+	if len(filename) > 0 {
+		opts = append(opts, SaveTo(filename[0]))
+	}
 
-	return os.WriteFile(composePath, yamlData, 0644)
+	result, err := c.Save(ctx, opts...)
+	return result.Status, err
 }
@@ -1,7 +1,9 @@
 package compose_test
 
 import (
+	"bytes"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/cdvelop/compose"
@@ -11,6 +13,12 @@ import (
 
 func TestComposeGenerator(t *testing.T) {
 	const testFile = "docker-compose.yml"
+	chdir(t, t.TempDir())
+
+	// Validate rechaza variables no definidas
+	t.Setenv("POSTGRES_DB", "ragtag")
+	t.Setenv("POSTGRES_USER", "postgres")
+	t.Setenv("POSTGRES_PASSWORD", "secret")
 
 	dbService := *compose.NewService("db").
 		SetContainerName("db").
@@ -118,3 +126,74 @@ func verifyGeneratedYAML(t *testing.T, filename string) {
 		}
 	}
 }
+
+func TestWriteTo(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx").AddPort("80", "80")
+	config, _ := compose.NewCompose("3.8", web)
+
+	var buf bytes.Buffer
+	n, err := config.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if int(n) != buf.Len() || buf.String() != string(data) {
+		t.Errorf("WriteTo y Bytes difieren:\n%s\n%s", buf.String(), data)
+	}
+
+	invalid, _ := compose.NewCompose("3.8", *compose.NewService("broken"))
+	if _, err := invalid.WriteTo(&buf); err == nil {
+		t.Error("Se esperaba un error de validación")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	script := `echo "it's ready" && psql -c 'SELECT 1' | tee /tmp/out`
+
+	worker := *compose.NewService("worker").SetImage("alpine").SetShellCommand(script)
+	api := *compose.NewService("api").SetImage("api").SetCommand("serve", "--name", `say "hi"`)
+
+	config, _ := compose.NewCompose("3.8", worker, api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Command []string `yaml:"command"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["worker"].Command
+	if len(got) != 3 || got[0] != "/bin/sh" || got[1] != "-c" || got[2] != script {
+		t.Errorf("Comando shell incorrecto: %q", got)
+	}
+
+	got = result.Services["api"].Command
+	if len(got) != 3 || got[2] != `say "hi"` {
+		t.Errorf("Comando exec incorrecto: %q", got)
+	}
+	cli := *compose.NewService("cli").SetImage("cli").SetCommandString(`serve --name "my app"`)
+	config, _ = compose.NewCompose("3.8", cli)
+	data, err = config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), "    command: \"serve --name \\\"my app\\\"\"\n") {
+		t.Errorf("Se esperaba el comando en forma de texto:\n%s", data)
+	}
+
+	err = compose.NewService("bad").SetCommandString(`echo "sin cerrar`).Err()
+	if err == nil || strings.Contains(err.Error(), "docker run") {
+		t.Errorf("Se esperaba un error por comillas sin cerrar sin mencionar docker run: %v", err)
+	}
+}
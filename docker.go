@@ -0,0 +1,126 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultComposeFile es el archivo usado cuando no se indica otro
+const defaultComposeFile = "docker-compose.yml"
+
+// composeFile devuelve la ruta del archivo sobre el que operan los comandos docker compose
+func (c *composeConfig) composeFile() string {
+	if c.file != "" {
+		return c.file
+	}
+	return defaultComposeFile
+}
+
+// composeFiles devuelve los archivos a pasar con -f, en orden de prioridad:
+// el base, el override del entorno activo y los overrides de capas
+func (c *composeConfig) composeFiles() []string {
+	files := []string{c.composeFile()}
+	if c.environment != "" {
+		files = append(files, c.overrideFile(c.environment))
+	}
+	return append(files, c.layerFiles...)
+}
+
+// projectNamePattern son los caracteres que docker compose no admite en nombres de proyecto
+var projectNamePattern = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// project devuelve el nombre de proyecto explícito o el que docker compose usará
+// por defecto: el nombre del directorio del archivo compose, en minúsculas y sin
+// caracteres inválidos
+func (c *composeConfig) project() string {
+	if c.projectName != "" {
+		return c.projectName
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(c.composeFile()))
+	if err != nil {
+		dir = "."
+	}
+	return projectNamePattern.ReplaceAllString(strings.ToLower(filepath.Base(dir)), "")
+}
+
+// runDocker ejecuta el cliente docker y devuelve la salida estándar
+func runDocker(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("docker %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// streamDocker ejecuta el cliente docker escribiendo su salida estándar en w a
+// medida que se produce, para comandos de larga duración como logs -f
+func streamDocker(ctx context.Context, w io.Writer, args ...string) error {
+	return streamDockerOutput(ctx, w, nil, args...)
+}
+
+// streamDockerOutput es como streamDocker pero además copia la salida de
+// errores en errW, donde docker compose escribe el progreso de pull y build
+func streamDockerOutput(ctx context.Context, w, errW io.Writer, args ...string) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if errW != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, errW)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("docker %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// composeArgs antepone a args las opciones de docker compose de la
+// configuración: directorio del proyecto si el archivo está fuera de él,
+// archivo .env del entorno, archivos -f y proyecto
+func (c *composeConfig) composeArgs(args ...string) []string {
+	base := []string{"compose"}
+	if c.projectDir != "" {
+		base = append(base, "--project-directory", c.projectDir)
+	}
+	if c.projectDir != "" || c.environment != "" {
+		envFile := defaultEnvFile
+		if c.environment != "" {
+			envFile = c.environment.EnvFile()
+		}
+		base = append(base, "--env-file", c.besideComposeFile(envFile))
+	}
+	for _, file := range c.composeFiles() {
+		base = append(base, "-f", file)
+	}
+	if c.projectName != "" {
+		base = append(base, "-p", c.projectName)
+	}
+	return append(base, args...)
+}
+
+// runCompose ejecuta docker compose sobre el archivo de la configuración
+func (c *composeConfig) runCompose(ctx context.Context, args ...string) ([]byte, error) {
+	args = c.composeArgs(args...)
+	start := time.Now()
+	out, err := runDocker(ctx, args...)
+	c.logCommand(args, start, err)
+	return out, err
+}
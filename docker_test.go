@@ -0,0 +1,36 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeDocker instala en el PATH un ejecutable "docker" que registra cada
+// invocación en el archivo devuelto y ejecuta el script indicado
+func fakeDocker(t *testing.T, script string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+
+	content := "#!/bin/sh\necho \"$@\" >> " + logPath + "\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(content), 0755); err != nil {
+		t.Fatalf("Error creando docker falso: %v", err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+// dockerCalls devuelve las invocaciones registradas por fakeDocker
+func dockerCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error leyendo llamadas a docker: %v", err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
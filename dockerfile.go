@@ -0,0 +1,202 @@
+package compose
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FromDockerfile completa el servicio con las instrucciones EXPOSE, ENV, VOLUME,
+// USER y HEALTHCHECK de la última etapa del Dockerfile en path, para no repetir
+// en el compose lo que ya declara la imagen. Conviene llamarlo antes que los
+// demás setters para que estos prevalezcan
+func (s *service) FromDockerfile(path string) *service {
+	instructions, err := readDockerfile(path)
+	if err != nil {
+		s.errors = append(s.errors, fmt.Errorf("service %q: %w", s.name, err))
+		return s
+	}
+
+	for _, in := range instructions {
+		if err := s.applyDockerfileInstruction(in); err != nil {
+			s.errors = append(s.errors, fmt.Errorf("service %q: %s line %d: %w", s.name, path, in.line, err))
+		}
+	}
+	return s
+}
+
+// dockerfileInstruction es una instrucción ya unida con sus continuaciones
+type dockerfileInstruction struct {
+	line    int
+	command string // en mayúsculas
+	args    string
+}
+
+// readDockerfile devuelve las instrucciones de la última etapa del Dockerfile
+func readDockerfile(path string) ([]dockerfileInstruction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var instructions []dockerfileInstruction
+	var current strings.Builder
+	start := 0
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if current.Len() == 0 && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if current.Len() == 0 {
+			start = n
+		}
+		if strings.HasPrefix(line, "#") {
+			// comentarios dentro de una instrucción partida
+			continue
+		}
+		if cont, ok := strings.CutSuffix(line, `\`); ok {
+			current.WriteString(cont + " ")
+			continue
+		}
+		current.WriteString(line)
+
+		command, args, _ := strings.Cut(current.String(), " ")
+		current.Reset()
+
+		in := dockerfileInstruction{line: start, command: strings.ToUpper(command), args: strings.TrimSpace(args)}
+		if in.command == "FROM" {
+			// solo interesa la etapa final, la que produce la imagen
+			instructions = nil
+			continue
+		}
+		instructions = append(instructions, in)
+	}
+	return instructions, scanner.Err()
+}
+
+// applyDockerfileInstruction aplica una instrucción al servicio
+func (s *service) applyDockerfileInstruction(in dockerfileInstruction) error {
+	switch in.command {
+	case "EXPOSE":
+		s.Expose(strings.Fields(in.args)...)
+	case "ENV":
+		vars, err := parseDockerfileEnv(in.args)
+		if err != nil {
+			return err
+		}
+		// los valores ya son públicos en la imagen, no van al .env. Los que
+		// referencian otras variables ($PATH...) se resolvieron al construir la
+		// imagen; copiarlos haría que docker compose los interpolara con el
+		// entorno del host, así que se dejan a la imagen
+		for _, kv := range vars {
+			if err := validateEnvKey(kv[0]); err != nil {
+				return err
+			}
+			if strings.Contains(kv[1], "$") {
+				continue
+			}
+			s.environment[kv[0]] = kv[1]
+		}
+	case "VOLUME":
+		paths, err := dockerfileList(in.args)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			s.AddVolume(Volume{Target: p})
+		}
+	case "USER":
+		s.SetRaw("user", in.args)
+	case "HEALTHCHECK":
+		return s.applyDockerfileHealthCheck(in.args)
+	}
+	return nil
+}
+
+// parseDockerfileEnv interpreta "ENV K=V K2=V2" y la forma antigua "ENV K V"
+func parseDockerfileEnv(args string) ([][2]string, error) {
+	key, value, _ := strings.Cut(args, " ")
+	if !strings.Contains(key, "=") {
+		return [][2]string{{key, strings.TrimSpace(value)}}, nil
+	}
+
+	words, err := splitShellWords(args)
+	if err != nil {
+		return nil, err
+	}
+	vars := make([][2]string, 0, len(words))
+	for _, w := range words {
+		k, v, ok := strings.Cut(w, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid ENV %q", w)
+		}
+		vars = append(vars, [2]string{k, v})
+	}
+	return vars, nil
+}
+
+// dockerfileList interpreta argumentos en forma JSON (["a", "b"]) o separados por espacios
+func dockerfileList(args string) ([]string, error) {
+	if !strings.HasPrefix(args, "[") {
+		return strings.Fields(args), nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(args), &list); err != nil {
+		return nil, fmt.Errorf("invalid JSON array %s", args)
+	}
+	return list, nil
+}
+
+// applyDockerfileHealthCheck interpreta
+// "HEALTHCHECK [--interval=.. --timeout=.. --start-period=.. --retries=..] CMD ..." o "HEALTHCHECK NONE"
+func (s *service) applyDockerfileHealthCheck(args string) error {
+	var hc HealthCheck
+	for strings.HasPrefix(args, "--") {
+		option, rest, _ := strings.Cut(args, " ")
+		args = strings.TrimSpace(rest)
+
+		name, value, _ := strings.Cut(strings.TrimPrefix(option, "--"), "=")
+		switch name {
+		case "interval":
+			hc.Interval = value
+		case "timeout":
+			hc.Timeout = value
+		case "start-period":
+			hc.StartPeriod = value
+		case "retries":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid HEALTHCHECK retries %q", value)
+			}
+			hc.Retries = n
+		}
+	}
+
+	command, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+	switch strings.ToUpper(command) {
+	case "NONE":
+		hc.Test = []string{"NONE"}
+	case "CMD":
+		if strings.HasPrefix(rest, "[") {
+			list, err := dockerfileList(rest)
+			if err != nil {
+				return err
+			}
+			hc.Test = append([]string{"CMD"}, list...)
+		} else {
+			hc.Test = []string{"CMD-SHELL", rest}
+		}
+	default:
+		return fmt.Errorf("invalid HEALTHCHECK %q", args)
+	}
+
+	s.SetHealthCheckConfig(hc)
+	return nil
+}
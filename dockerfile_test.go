@@ -0,0 +1,98 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFromDockerfile(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	dockerfile := `# etapa de compilación
+FROM golang:1.22 AS build
+EXPOSE 9999
+ENV CGO_ENABLED=0
+
+FROM alpine:3.20
+ENV APP_ENV=production \
+    APP_NAME="my app"
+ENV LEGACY some value
+ENV PATH=/app/bin:$PATH
+EXPOSE 8080 9090/udp
+VOLUME ["/data", "/logs"]
+USER app
+HEALTHCHECK --interval=30s --timeout=3s --retries=2 \
+  CMD wget -qO- http://localhost:8080/health || exit 1
+`
+	path := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(path, []byte(dockerfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	api := *compose.NewService("api").SetBuild(".").FromDockerfile(path)
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Error("Las variables ENV de la imagen no deben escribirse en el .env")
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Expose      []string          `yaml:"expose"`
+			Environment map[string]string `yaml:"environment"`
+			Volumes     []string          `yaml:"volumes"`
+			User        string            `yaml:"user"`
+			Healthcheck struct {
+				Test     []string `yaml:"test"`
+				Interval string   `yaml:"interval"`
+				Timeout  string   `yaml:"timeout"`
+				Retries  int      `yaml:"retries"`
+			} `yaml:"healthcheck"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["api"]
+	if strings.Join(got.Expose, ",") != "8080,9090/udp" {
+		t.Errorf("expose incorrecto (solo la última etapa): %v", got.Expose)
+	}
+	if got.Environment["APP_ENV"] != "production" || got.Environment["APP_NAME"] != "my app" ||
+		got.Environment["LEGACY"] != "some value" || got.Environment["CGO_ENABLED"] != "" ||
+		got.Environment["PATH"] != "" {
+		t.Errorf("environment incorrecto: %v", got.Environment)
+	}
+	if strings.Join(got.Volumes, ",") != "/data,/logs" {
+		t.Errorf("volumes incorrectos: %v", got.Volumes)
+	}
+	if got.User != "app" {
+		t.Errorf("user incorrecto: %q", got.User)
+	}
+	hc := got.Healthcheck
+	if strings.Join(hc.Test, "|") != "CMD-SHELL|wget -qO- http://localhost:8080/health || exit 1" ||
+		hc.Interval != "30s" || hc.Timeout != "3s" || hc.Retries != 2 {
+		t.Errorf("healthcheck incorrecto: %+v", hc)
+	}
+
+	missing := compose.NewService("x").FromDockerfile(filepath.Join(dir, "nope"))
+	if missing.Err() == nil {
+		t.Error("Se esperaba un error por Dockerfile inexistente")
+	}
+	invalid := filepath.Join(dir, "Dockerfile.invalid")
+	if err := os.WriteFile(invalid, []byte("FROM alpine\nENV bad-key=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := compose.NewService("x").FromDockerfile(invalid).Err(); err == nil {
+		t.Error("Se esperaba un error por clave ENV inválida")
+	}
+}
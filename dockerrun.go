@@ -0,0 +1,386 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// runValueFlags son las opciones de docker run que reciben un valor, con su
+// forma corta normalizada a la larga
+var runValueFlags = map[string]string{
+	"-p": "--publish", "--publish": "--publish",
+	"-e": "--env", "--env": "--env",
+	"-v": "--volume", "--volume": "--volume",
+	"-w": "--workdir", "--workdir": "--workdir",
+	"-u": "--user", "--user": "--user",
+	"-h": "--hostname", "--hostname": "--hostname",
+	"-l": "--label", "--label": "--label",
+	"-m": "--memory", "--memory": "--memory",
+	"--name": "--name", "--restart": "--restart", "--network": "--network", "--net": "--network",
+	"--network-alias": "--network-alias", "--entrypoint": "--entrypoint", "--add-host": "--add-host",
+	"--dns": "--dns", "--dns-search": "--dns-search", "--cap-add": "--cap-add", "--cap-drop": "--cap-drop",
+	"--security-opt": "--security-opt", "--tmpfs": "--tmpfs", "--shm-size": "--shm-size",
+	"--ulimit": "--ulimit", "--sysctl": "--sysctl", "--device": "--device", "--gpus": "--gpus",
+	"--platform": "--platform", "--pull": "--pull", "--expose": "--expose", "--cpus": "--cpus",
+	"--health-cmd": "--health-cmd", "--health-interval": "--health-interval",
+	"--health-timeout": "--health-timeout", "--health-retries": "--health-retries",
+	"--health-start-period": "--health-start-period", "--stop-signal": "--stop-signal",
+	"--stop-timeout": "--stop-timeout",
+}
+
+// runBoolFlags son las opciones sin valor, también agrupables en forma corta (-dit)
+var runBoolFlags = map[string]string{
+	"-d": "--detach", "--detach": "--detach",
+	"-i": "--interactive", "--interactive": "--interactive",
+	"-t": "--tty", "--tty": "--tty",
+	"--rm": "--rm", "--init": "--init", "--privileged": "--privileged", "--read-only": "--read-only",
+}
+
+// runFlag es una opción de docker run ya normalizada
+type runFlag struct {
+	name  string
+	value string
+}
+
+// FromDockerRun convierte una invocación "docker run ..." en un servicio, para
+// migrar comandos sueltos a un compose gestionado. El nombre del servicio sale
+// de --name o, si no se indica, de la imagen. Las opciones que no tienen
+// equivalente (como --env-file o los volúmenes anónimos) devuelven un error
+func FromDockerRun(command string) (*service, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, fmt.Errorf("docker run: %w", err)
+	}
+
+	switch {
+	case len(args) >= 3 && args[0] == "docker" && args[1] == "container" && args[2] == "run":
+		args = args[3:]
+	case len(args) >= 2 && args[0] == "docker" && args[1] == "run":
+		args = args[2:]
+	case len(args) >= 1 && args[0] == "run":
+		args = args[1:]
+	}
+
+	flags, rest, err := parseRunFlags(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) == 0 {
+		return nil, errors.New("docker run: missing image")
+	}
+	image, cmd := rest[0], rest[1:]
+
+	name := serviceNameFromImage(image)
+	for _, f := range flags {
+		if f.name == "--name" {
+			name = f.value
+		}
+	}
+
+	s := NewService(name).SetImage(image)
+	if len(cmd) > 0 {
+		s.SetCommand(cmd...)
+	}
+	if err := applyRunFlags(s, flags); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseRunFlags separa las opciones de docker run de la imagen y su comando
+func parseRunFlags(args []string) ([]runFlag, []string, error) {
+	var flags []runFlag
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return flags, args[i+1:], nil
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return flags, args[i:], nil
+		}
+
+		key, value, hasValue := strings.Cut(arg, "=")
+		if !strings.HasPrefix(arg, "--") && len(arg) > 2 {
+			key, value, hasValue = arg[:2], arg[2:], true
+		}
+
+		if long, ok := runValueFlags[key]; ok {
+			if !hasValue {
+				if i+1 >= len(args) {
+					return nil, nil, fmt.Errorf("docker run: flag %s needs a value", key)
+				}
+				i++
+				value = args[i]
+			}
+			flags = append(flags, runFlag{long, value})
+			continue
+		}
+
+		if long, ok := runBoolFlags[key]; ok && (!hasValue || value == "true") {
+			flags = append(flags, runFlag{long, ""})
+			continue
+		}
+
+		// opciones cortas agrupadas: -dit
+		if !strings.HasPrefix(arg, "--") {
+			grouped := true
+			for _, c := range arg[1:] {
+				if _, ok := runBoolFlags["-"+string(c)]; !ok {
+					grouped = false
+					break
+				}
+			}
+			if grouped {
+				for _, c := range arg[1:] {
+					flags = append(flags, runFlag{runBoolFlags["-"+string(c)], ""})
+				}
+				continue
+			}
+		}
+		return nil, nil, fmt.Errorf("docker run: unsupported flag %s", key)
+	}
+	return flags, nil, nil
+}
+
+// applyRunFlags aplica las opciones al servicio con sus setters equivalentes
+func applyRunFlags(s *service, flags []runFlag) error {
+	var hc HealthCheck
+
+	for _, f := range flags {
+		switch f.name {
+		case "--detach", "--rm", "--name":
+			// sin equivalente en compose o ya aplicado
+		case "--interactive":
+			s.SetRaw("stdin_open", true)
+		case "--tty":
+			s.SetRaw("tty", true)
+		case "--init":
+			s.SetRaw("init", true)
+		case "--privileged":
+			s.SetPrivileged(true)
+		case "--read-only":
+			s.SetReadOnly(true)
+		case "--publish":
+			host, container := splitRunPort(f.value)
+			s.AddPort(host, container)
+		case "--env":
+			if key, value, ok := strings.Cut(f.value, "="); ok {
+				s.AddEnvironment(key, value)
+			} else {
+				s.AddEnvironment(key)
+			}
+		case "--volume":
+			source, target, ok := strings.Cut(f.value, ":")
+			if !ok {
+				return fmt.Errorf("docker run: anonymous volume %q is not supported, give it a name", f.value)
+			}
+			s.AddVolume(Volume{Source: source, Target: target})
+		case "--workdir":
+			s.SetRaw("working_dir", f.value)
+		case "--user":
+			s.SetRaw("user", f.value)
+		case "--hostname":
+			s.SetRaw("hostname", f.value)
+		case "--label":
+			key, value, _ := strings.Cut(f.value, "=")
+			s.SetLabel(key, value)
+		case "--memory":
+			s.SetRaw("mem_limit", f.value)
+		case "--cpus":
+			s.SetRaw("cpus", f.value)
+		case "--restart":
+			s.SetRestartPolicy(f.value)
+		case "--network":
+			switch {
+			case f.value == "host" || f.value == "none" || f.value == "bridge" || strings.HasPrefix(f.value, "container:"):
+				s.SetNetworkMode(f.value)
+			default:
+				s.AttachNetwork(f.value)
+			}
+		case "--network-alias":
+			if len(s.networks) == 0 {
+				return fmt.Errorf("docker run: --network-alias %q needs a user-defined --network", f.value)
+			}
+			n := &s.networks[len(s.networks)-1]
+			n.Aliases = append(n.Aliases, f.value)
+		case "--entrypoint":
+			s.SetRaw("entrypoint", f.value)
+		case "--add-host":
+			host, ip, _ := strings.Cut(f.value, ":")
+			s.AddExtraHost(host, ip)
+		case "--dns":
+			s.SetDNS(append(s.dns, f.value)...)
+		case "--dns-search":
+			s.SetDNSSearch(append(s.dnsSearch, f.value)...)
+		case "--cap-add":
+			s.AddCapability(f.value)
+		case "--cap-drop":
+			s.DropCapability(f.value)
+		case "--security-opt":
+			s.AddSecurityOpt(f.value)
+		case "--tmpfs":
+			mount, opts, ok := strings.Cut(f.value, ":")
+			if ok {
+				s.AddTmpfs(mount, strings.Split(opts, ",")...)
+			} else {
+				s.AddTmpfs(mount)
+			}
+		case "--shm-size":
+			s.SetShmSize(f.value)
+		case "--ulimit":
+			if err := applyRunUlimit(s, f.value); err != nil {
+				return err
+			}
+		case "--sysctl":
+			key, value, _ := strings.Cut(f.value, "=")
+			s.SetSysctl(key, value)
+		case "--device":
+			parts := strings.SplitN(f.value, ":", 3)
+			for len(parts) < 3 {
+				parts = append(parts, "")
+			}
+			s.AddDevice(parts[0], parts[1], parts[2])
+		case "--gpus":
+			count := AllDevices
+			if f.value != "all" {
+				n, err := strconv.Atoi(strings.TrimPrefix(f.value, "count="))
+				if err != nil {
+					return fmt.Errorf("docker run: unsupported --gpus value %q", f.value)
+				}
+				count = n
+			}
+			s.RequestGPU(count)
+		case "--platform":
+			s.SetPlatform(f.value)
+		case "--pull":
+			s.SetPullPolicy(f.value)
+		case "--expose":
+			s.Expose(f.value)
+		case "--stop-signal":
+			s.SetRaw("stop_signal", f.value)
+		case "--stop-timeout":
+			s.SetRaw("stop_grace_period", f.value+"s")
+		case "--health-cmd":
+			hc.Test = []string{"CMD-SHELL", f.value}
+		case "--health-interval":
+			hc.Interval = f.value
+		case "--health-timeout":
+			hc.Timeout = f.value
+		case "--health-start-period":
+			hc.StartPeriod = f.value
+		case "--health-retries":
+			n, err := strconv.Atoi(f.value)
+			if err != nil {
+				return fmt.Errorf("docker run: invalid --health-retries %q", f.value)
+			}
+			hc.Retries = n
+		}
+	}
+
+	if len(hc.Test) > 0 {
+		s.SetHealthCheckConfig(hc)
+	}
+	return s.Err()
+}
+
+// splitRunPort separa un -p de docker run en la parte del host (IP y puerto)
+// y la del contenedor
+func splitRunPort(value string) (host, container string) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return "", value
+	}
+	return value[:i], value[i+1:]
+}
+
+// applyRunUlimit aplica un --ulimit nombre=soft[:hard]
+func applyRunUlimit(s *service, value string) error {
+	name, limits, _ := strings.Cut(value, "=")
+	softValue, hardValue, ok := strings.Cut(limits, ":")
+	if !ok {
+		hardValue = softValue
+	}
+	soft, errSoft := strconv.Atoi(softValue)
+	hard, errHard := strconv.Atoi(hardValue)
+	if errSoft != nil || errHard != nil {
+		return fmt.Errorf("docker run: invalid --ulimit %q", value)
+	}
+	s.SetUlimit(name, soft, hard)
+	return nil
+}
+
+// serviceNameFromImage deriva un nombre de servicio de la imagen:
+// "ghcr.io/org/api:1.0" -> "api"
+func serviceNameFromImage(image string) string {
+	name, _, _ := strings.Cut(image, "@")
+	name = path.Base(name)
+	name, _, _ = strings.Cut(name, ":")
+	return name
+}
+
+// splitShellWords separa un comando en palabras como lo haría sh: respeta
+// comillas simples y dobles, escapes con "\" y continuaciones de línea
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			if i+1 >= len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			next := runes[i]
+			switch {
+			case next == '\n':
+				// continuación de línea
+			case quote == '"' && !strings.ContainsRune("\"\\$`", next):
+				current.WriteRune('\\')
+				current.WriteRune(next)
+				inWord = true
+			default:
+				current.WriteRune(next)
+				inWord = true
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,109 @@
+package compose_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFromDockerRun(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	s, err := compose.FromDockerRun(`docker run -dit --rm --name web \
+		-p 127.0.0.1:8080:80 -p 443 \
+		-e GREETING="hello world" --env=MODE=prod \
+		-v ./html:/usr/share/nginx/html:ro \
+		--restart unless-stopped --network backend --network-alias www \
+		--ulimit nofile=1024:2048 --health-cmd 'curl -f http://localhost/' --health-retries 3 \
+		-l team=web nginx:1.25 nginx -g 'daemon off;'`)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	config, _ := compose.NewCompose("3.8", *s)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Image       string            `yaml:"image"`
+			Ports       []string          `yaml:"ports"`
+			Environment map[string]string `yaml:"environment"`
+			Volumes     []string          `yaml:"volumes"`
+			Restart     string            `yaml:"restart"`
+			Command     []string          `yaml:"command"`
+			Networks    map[string]struct {
+				Aliases []string `yaml:"aliases"`
+			} `yaml:"networks"`
+			Healthcheck struct {
+				Test    []string `yaml:"test"`
+				Retries int      `yaml:"retries"`
+			} `yaml:"healthcheck"`
+			Labels    map[string]string `yaml:"labels"`
+			Tty       bool              `yaml:"tty"`
+			StdinOpen bool              `yaml:"stdin_open"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	web, ok := result.Services["web"]
+	if !ok {
+		t.Fatalf("Falta el servicio web:\n%s", data)
+	}
+	if web.Image != "nginx:1.25" || web.Restart != "unless-stopped" || !web.Tty || !web.StdinOpen {
+		t.Errorf("Servicio incorrecto: %+v", web)
+	}
+	if strings.Join(web.Ports, ",") != "127.0.0.1:8080:80,443" {
+		t.Errorf("Puertos incorrectos: %v", web.Ports)
+	}
+	if web.Environment["GREETING"] != "hello world" || web.Environment["MODE"] != "prod" {
+		t.Errorf("Entorno incorrecto: %v", web.Environment)
+	}
+	if strings.Join(web.Volumes, ",") != "./html:/usr/share/nginx/html:ro" {
+		t.Errorf("Volúmenes incorrectos: %v", web.Volumes)
+	}
+	if strings.Join(web.Command, " ") != "nginx -g daemon off;" {
+		t.Errorf("Comando incorrecto: %q", web.Command)
+	}
+	if aliases := web.Networks["backend"].Aliases; len(aliases) != 1 || aliases[0] != "www" {
+		t.Errorf("Redes incorrectas: %+v", web.Networks)
+	}
+	if strings.Join(web.Healthcheck.Test, "|") != "CMD-SHELL|curl -f http://localhost/" || web.Healthcheck.Retries != 3 {
+		t.Errorf("Healthcheck incorrecto: %+v", web.Healthcheck)
+	}
+	if web.Labels["team"] != "web" {
+		t.Errorf("Labels incorrectos: %v", web.Labels)
+	}
+
+	named, err := compose.FromDockerRun("docker run ghcr.io/org/api:2.0")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	config, _ = compose.NewCompose("3.8", *named)
+	if data, _ := config.Bytes(); !strings.Contains(string(data), "  api:\n") {
+		t.Errorf("El nombre debe derivarse de la imagen:\n%s", data)
+	}
+
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Error("Convertir el comando no debe escribir el .env")
+	}
+
+	for _, bad := range []string{
+		"docker run --env-file .env nginx", "docker run -v /data nginx", "docker run -d", `docker run "nginx`,
+		"docker run -p 99999:80 nginx", "docker run --restart sometimes nginx",
+	} {
+		if _, err := compose.FromDockerRun(bad); err == nil {
+			t.Errorf("%s: se esperaba un error", bad)
+		}
+	}
+	if _, err := compose.FromDockerRun(`docker run "nginx`); err == nil || !strings.HasPrefix(err.Error(), "docker run: ") {
+		t.Errorf("El error debía indicar que viene de docker run: %v", err)
+	}
+}
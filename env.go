@@ -4,90 +4,188 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 )
 
+// defaultEnvFile is the env file managed by the package when no path is given
+const defaultEnvFile = ".env"
+
 // AddEnvToFile adds environment variables to .env file and ensures .gitignore is properly configured
 // envPath and gitignorePath are optional, defaulting to ".env" and ".gitignore" respectively
 func AddEnvToFile(key string, value string, paths ...string) error {
-	envPath := ".env"
-	gitignorePath := ".gitignore"
+	return addEnvToFile("", key, value, paths...)
+}
 
-	if len(paths) > 0 {
-		envPath = paths[0]
-	}
-	if len(paths) > 1 {
-		gitignorePath = paths[1]
+// AddEnvToFileGroup works like AddEnvToFile but places the variable in a named
+// section of the .env file (e.g. "database", "auth"). New sections are inserted in
+// alphabetical order, each one under a "# === group ===" header
+func AddEnvToFileGroup(group string, key string, value string, paths ...string) error {
+	return addEnvToFile(group, key, value, paths...)
+}
+
+// AddEnvsToFile works like AddEnvToFile for several variables at once, reading
+// and writing the .env file a single time. Keys are added in alphabetical order
+func AddEnvsToFile(vars map[string]string, paths ...string) error {
+	return osFiles.addEnvsToFile("", vars, paths...)
+}
+
+// addEnvToFile adds or updates a variable, keeping its current group when group is empty
+func addEnvToFile(group string, key string, value string, paths ...string) error {
+	return osFiles.addEnvsToFile(group, map[string]string{key: value}, paths...)
+}
+
+// envWriteMu serializes read-modify-write cycles on env files, so services built
+// from several goroutines don't lose each other's variables
+var envWriteMu sync.Mutex
+
+// addEnvsToFile adds or updates variables with a single read-modify-write of the file
+func (f *fileSettings) addEnvsToFile(group string, vars map[string]string, paths ...string) error {
+	envWriteMu.Lock()
+	defer envWriteMu.Unlock()
+
+	for key := range vars {
+		if err := validateEnvKey(key); err != nil {
+			return err
+		}
 	}
 
-	envVars, err := readEnvFile(envPath)
+	envPath := envPathFrom(paths)
+	gitignorePath := f.gitignorePathFrom(paths)
+
+	data, err := f.readEnvData(envPath)
 	if err != nil {
 		return err
 	}
+	doc := parseEnvDocument(data)
 
-	// Add/Update new environment variable
-	envVars[key] = value
+	// Add/Update new environment variables, keeping comments and order
+	for _, key := range sortedKeys(vars) {
+		doc.set(group, key, vars[key])
+	}
 
-	if err := writeEnvFile(envPath, envVars); err != nil {
+	if err := f.writeEnvFile(envPath, doc); err != nil {
 		return err
 	}
 
-	return handleGitignore(gitignorePath, envPath)
+	// Encrypted files are safe to commit, there is no plaintext file to ignore
+	if f.cipher != nil {
+		return nil
+	}
+
+	return f.handleGitignore(gitignorePath, envPath)
 }
 
-// readEnvFile reads and parses an existing .env file
-func readEnvFile(path string) (map[string]string, error) {
-	envVars := make(map[string]string)
-
-	if data, err := os.ReadFile(path); err == nil {
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
-			if len(parts) == 2 {
-				envVars[parts[0]] = parts[1]
-			}
-		}
+// RemoveEnvFromFile deletes a variable from the .env file, dropping its group
+// header if the section is left empty. Removing a missing key is not an error.
+// envPath is optional and defaults to ".env"
+func RemoveEnvFromFile(key string, paths ...string) error {
+	envWriteMu.Lock()
+	defer envWriteMu.Unlock()
+
+	envPath := envPathFrom(paths)
+
+	data, err := osFiles.readEnvData(envPath)
+	if err != nil {
+		return err
+	}
+	doc := parseEnvDocument(data)
+
+	if !doc.remove(key) {
+		return nil
 	}
-	return envVars, nil
+	return osFiles.writeEnvFile(envPath, doc)
 }
 
-// writeEnvFile writes environment variables to a file
-func writeEnvFile(path string, envVars map[string]string) error {
-	var envContent strings.Builder
-	for k, v := range envVars {
-		envContent.WriteString(fmt.Sprintf("%s=%s\n", k, v))
+// ListEnvFromFile returns the keys defined in the .env file, in file order.
+// envPath is optional and defaults to ".env"
+func ListEnvFromFile(paths ...string) ([]string, error) {
+	data, err := osFiles.readEnvData(envPathFrom(paths))
+	if err != nil {
+		return nil, err
 	}
-	return os.WriteFile(path, []byte(envContent.String()), 0644)
+	return parseEnvDocument(data).keys(), nil
 }
 
-// handleGitignore ensures .env is in .gitignore
-func handleGitignore(gitignorePath string, envPath string) error {
-	var gitignoreContent []string
-	envLineExists := false
-	envFileName := filepath.Base(envPath)
-
-	// Read existing .gitignore if it exists
-	if data, err := os.ReadFile(gitignorePath); err == nil {
-		gitignoreContent = strings.Split(string(data), "\n")
-		for _, line := range gitignoreContent {
-			if strings.TrimSpace(line) == envFileName {
-				envLineExists = true
-				break
-			}
-		}
+// HasEnv reports whether key is defined in the .env file.
+// envPath is optional and defaults to ".env"
+func HasEnv(key string, paths ...string) (bool, error) {
+	data, err := osFiles.readEnvData(envPathFrom(paths))
+	if err != nil {
+		return false, err
+	}
+	return parseEnvDocument(data).index(key) >= 0, nil
+}
+
+// envPathFrom returns the env file of an optional paths argument
+func envPathFrom(paths []string) string {
+	if len(paths) > 0 {
+		return paths[0]
 	}
+	return defaultEnvFile
+}
 
-	// Add .env to .gitignore if not present
-	if !envLineExists {
-		// Remove empty lines at the end
-		for len(gitignoreContent) > 0 && gitignoreContent[len(gitignoreContent)-1] == "" {
-			gitignoreContent = gitignoreContent[:len(gitignoreContent)-1]
-		}
-		gitignoreContent = append(gitignoreContent, envFileName)
+// readEnvFile reads and parses an existing .env file
+func (f *fileSettings) readEnvFile(path string) (map[string]string, error) {
+	data, err := f.readEnvData(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseEnv(data), nil
+}
+
+// readEnvData returns the raw content of an env file, or nil if it doesn't exist.
+// When encryption is enabled the "<path>.enc" file is read and decrypted instead
+func (f *fileSettings) readEnvData(path string) ([]byte, error) {
+	cipher := f.cipher
+	if cipher == nil {
+		data, _ := f.readFile(path)
+		return data, nil
+	}
 
-		if err := os.WriteFile(gitignorePath, []byte(strings.Join(gitignoreContent, "\n")+"\n"), 0644); err != nil {
-			return fmt.Errorf("error writing .gitignore file: %v", err)
+	data, err := f.readFile(path + encryptedEnvSuffix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
+		return nil, err
+	}
+
+	plain, err := cipher.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting %s: %w", path+encryptedEnvSuffix, err)
+	}
+	return plain, nil
+}
+
+// parseEnv parses KEY=value lines, skipping comments
+func parseEnv(data []byte) map[string]string {
+	return parseEnvDocument(data).vars()
+}
+
+// envGroupHeader formats the comment line that opens a group section
+func envGroupHeader(group string) string {
+	return "# === " + group + " ==="
+}
+
+// writeEnvFile writes the document to path, preserving its comments and order,
+// and creates its directory if needed. When encryption is enabled the content
+// is encrypted and written to "<path>.enc"
+func (f *fileSettings) writeEnvFile(path string, doc *envDocument) error {
+	content := []byte(doc.String())
+	perm, owner := f.envFileAttrs()
+
+	if err := f.mkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	cipher := f.cipher
+	if cipher == nil {
+		return f.writeFile(path, content, perm, owner...)
+	}
+
+	encrypted, err := cipher.Encrypt(content)
+	if err != nil {
+		return fmt.Errorf("error encrypting %s: %w", path, err)
 	}
-	return nil
+	return f.writeFile(path+encryptedEnvSuffix, encrypted, perm, owner...)
 }
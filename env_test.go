@@ -1,6 +1,7 @@
 package compose_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -130,3 +131,199 @@ func TestAddEnvToFile(t *testing.T) {
 		}
 	})
 }
+
+func TestAddEnvToFileGroup(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	gitignorePath := filepath.Join(dir, ".gitignore")
+
+	steps := []struct{ group, key, value string }{
+		{"database", "DB_HOST", "db"},
+		{"auth", "JWT_SECRET", "s3cr3t"},
+		{"", "DEBUG", "true"},
+		{"database", "DB_PORT", "5432"},
+		{"", "DB_HOST", "postgres"}, // conserva su grupo al actualizar
+	}
+	for _, step := range steps {
+		if err := compose.AddEnvToFileGroup(step.group, step.key, step.value, envPath, gitignorePath); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("Error leyendo .env: %v", err)
+	}
+
+	expected := "DEBUG=true\n\n# === auth ===\nJWT_SECRET=s3cr3t\n\n# === database ===\nDB_HOST=postgres\nDB_PORT=5432\n"
+	if string(content) != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, string(content))
+	}
+}
+
+func TestAddEnvToFilePreservesLayout(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	gitignorePath := filepath.Join(dir, ".gitignore")
+
+	initial := "# configuración local\nZETA=1\n\n# puerto de la API\nALPHA=2\n\n# === database ===\n# host interno\nDB_HOST=db\n"
+	if err := os.WriteFile(envPath, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct{ group, key, value string }{
+		{"", "ALPHA", "3"},
+		{"", "BETA", "4"},
+		{"database", "DB_PORT", "5432"},
+		{"auth", "JWT_SECRET", "s3cr3t"},
+	}
+	for _, step := range steps {
+		if err := compose.AddEnvToFileGroup(step.group, step.key, step.value, envPath, gitignorePath); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("Error leyendo .env: %v", err)
+	}
+
+	expected := "# configuración local\nZETA=1\n\n# puerto de la API\nALPHA=3\nBETA=4\n\n" +
+		"# === auth ===\nJWT_SECRET=s3cr3t\n\n" +
+		"# === database ===\n# host interno\nDB_HOST=db\nDB_PORT=5432\n"
+	if string(content) != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, string(content))
+	}
+}
+
+func TestEnvValueQuoting(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	gitignorePath := filepath.Join(dir, ".gitignore")
+
+	values := []struct{ key, value, line string }{
+		{"PLAIN", "postgres://db:5432/app", "PLAIN=postgres://db:5432/app"},
+		{"SPACES", "hello world # not a comment", "SPACES='hello world # not a comment'"},
+		{"QUOTES", `it's "quoted"`, `QUOTES="it's \"quoted\""`},
+		{"MULTILINE", "line1\nline2\\", `MULTILINE="line1\nline2\\"`},
+		{"DOLLAR", "it's $HOME", `DOLLAR="it's $$HOME"`},
+		{"EMPTY", "", "EMPTY="},
+	}
+	for _, v := range values {
+		if err := compose.AddEnvToFile(v.key, v.value, envPath, gitignorePath); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	}
+
+	content := string(readFile(t, envPath))
+	for _, v := range values {
+		if !strings.Contains(content, v.line+"\n") {
+			t.Errorf("Falta la línea %s en:\n%s", v.line, content)
+		}
+	}
+
+	for _, v := range values {
+		got, err := compose.Interpolate("${"+v.key+"}", envPath)
+		if err != nil || got != v.value {
+			t.Errorf("%s: se esperaba %q al releer, se obtuvo %q (%v)", v.key, v.value, got, err)
+		}
+	}
+
+	// releer y reescribir no debe alterar los valores
+	if err := compose.AddEnvToFile("PLAIN", "postgres://db:5432/app", envPath, gitignorePath); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got := string(readFile(t, envPath)); got != content {
+		t.Errorf("El contenido cambió al reescribir:\nAntes: %q\nDespués: %q", content, got)
+	}
+
+	for _, key := range []string{"lower", "1ABC", "WITH-DASH", ""} {
+		if err := compose.AddEnvToFile(key, "x", envPath, gitignorePath); err == nil {
+			t.Errorf("Se esperaba un error para la clave %q", key)
+		}
+	}
+}
+
+func TestAddEnvsToFile(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	gitignorePath := filepath.Join(dir, ".gitignore")
+
+	if err := os.WriteFile(envPath, []byte("# existente\nB=old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]string{"C": "3", "A": "1", "B": "2"}
+	if err := compose.AddEnvsToFile(vars, envPath, gitignorePath); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	expected := "# existente\nB=2\nA=1\nC=3\n"
+	if got := string(readFile(t, envPath)); got != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, got)
+	}
+
+	if err := compose.AddEnvsToFile(map[string]string{"OK": "1", "bad": "2"}, envPath, gitignorePath); err == nil {
+		t.Error("Se esperaba un error por clave inválida")
+	}
+	if got := string(readFile(t, envPath)); got != expected {
+		t.Error("Una clave inválida no debe escribir ninguna variable")
+	}
+}
+
+func TestAddEnvironmentMap(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	api := *compose.NewService("api").
+		SetImage("myapi").
+		SetEnvGroup("api").
+		AddEnvironmentMap(map[string]string{"PORT": "8080", "HOST": "0.0.0.0"})
+
+	config, _ := compose.NewCompose("3.8", api)
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	data := readFile(t, "docker-compose.yml")
+	if !strings.Contains(string(data), "      \"HOST\": \"0.0.0.0\"\n      \"PORT\": \"8080\"\n") {
+		t.Errorf("Variables de entorno incorrectas:\n%s", data)
+	}
+
+	expected := "# === api ===\nHOST=0.0.0.0\nPORT=8080\n"
+	if got := string(readFile(t, ".env")); got != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, got)
+	}
+}
+
+func TestRemoveAndListEnv(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+
+	initial := "# general\nDEBUG=true\nPORT=8080\n\n# === auth ===\nJWT_SECRET=s3cr3t\n"
+	if err := os.WriteFile(envPath, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := compose.ListEnvFromFile(envPath)
+	if err != nil || strings.Join(keys, ",") != "DEBUG,PORT,JWT_SECRET" {
+		t.Errorf("Claves incorrectas: %v %v", keys, err)
+	}
+
+	if ok, _ := compose.HasEnv("JWT_SECRET", envPath); !ok {
+		t.Error("Se esperaba JWT_SECRET")
+	}
+
+	for _, key := range []string{"JWT_SECRET", "PORT", "MISSING"} {
+		if err := compose.RemoveEnvFromFile(key, envPath); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	}
+
+	expected := "# general\nDEBUG=true\n"
+	if got := string(readFile(t, envPath)); got != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, got)
+	}
+	if ok, _ := compose.HasEnv("JWT_SECRET", envPath); ok {
+		t.Error("JWT_SECRET debería haberse eliminado")
+	}
+}
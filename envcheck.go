@@ -0,0 +1,105 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// EnvStrictness define qué hacer con referencias ${VAR} no definidas en el YAML generado
+type EnvStrictness int
+
+const (
+	// EnvCheckWarn registra las variables no definidas en Warnings (por defecto)
+	EnvCheckWarn EnvStrictness = iota
+	// EnvCheckStrict falla la generación si hay variables no definidas
+	EnvCheckStrict
+	// EnvCheckOff desactiva la verificación
+	EnvCheckOff
+)
+
+// envReferencePattern captura $$ (escape), ${VAR...} y $VAR
+var envReferencePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)([:?+-][^}]*)?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// SetEnvStrictness establece cómo se tratan las referencias a variables no definidas
+func (c *composeConfig) SetEnvStrictness(mode EnvStrictness) *composeConfig {
+	c.envStrictness = mode
+	return c
+}
+
+// Warnings devuelve las advertencias de la última generación
+func (c *composeConfig) Warnings() []string {
+	return c.warnings
+}
+
+// undefinedEnvReferences devuelve, ordenadas y sin repetir, las variables
+// referenciadas en data que no están en el .env gestionado ni en el entorno.
+// Las referencias con valor por defecto (${VAR:-x}, ${VAR-x}) no se reportan
+func (f *fileSettings) undefinedEnvReferences(data []byte, envPath string) ([]string, error) {
+	envVars, err := f.readEnvFile(envPath)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, match := range envReferencePattern.FindAllSubmatch(data, -1) {
+		name, modifier := string(match[1]), string(match[2])
+		if name == "" {
+			name = string(match[3])
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		if hasFallback(modifier) {
+			continue
+		}
+		seen[name] = true
+
+		if _, defined := envVars[name]; defined {
+			continue
+		}
+		if _, defined := os.LookupEnv(name); defined {
+			continue
+		}
+		missing = append(missing, name)
+	}
+
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// hasFallback indica si el modificador de interpolación evita el error por
+// variable no definida: ${VAR-x}, ${VAR:-x}, ${VAR+x} y ${VAR:+x}
+func hasFallback(modifier string) bool {
+	modifier = strings.TrimPrefix(modifier, ":")
+	return strings.HasPrefix(modifier, "-") || strings.HasPrefix(modifier, "+")
+}
+
+// checkEnvReferences aplica la política de verificación sobre el YAML generado.
+// pending son las variables diferidas que se escribirán en el .env al guardar
+func (c *composeConfig) checkEnvReferences(data []byte, pending map[string]bool) error {
+	if c.envStrictness == EnvCheckOff {
+		return nil
+	}
+
+	missing, err := c.files.undefinedEnvReferences(data, c.besideComposeFile(defaultEnvFile))
+	if err != nil {
+		return err
+	}
+	missing = slices.DeleteFunc(missing, func(name string) bool { return pending[name] })
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if c.envStrictness == EnvCheckStrict {
+		return fmt.Errorf("undefined environment variables referenced: %v", missing)
+	}
+	for _, name := range missing {
+		c.warnings = append(c.warnings, fmt.Sprintf("environment variable %s is referenced but not defined", name))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestEnvReferenceCheck(t *testing.T) {
+	t.Setenv("CHECK_DEFINED", "1")
+
+	worker := *compose.NewService("worker").
+		SetImage("alpine").
+		SetShellCommand("echo ${CHECK_DEFINED} $CHECK_MISSING ${CHECK_OPTIONAL:-x} $$HOME ${CHECK_REQUIRED:?falta}")
+
+	t.Run("Advertencias por defecto", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", worker)
+		if _, err := config.Bytes(); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		warnings := strings.Join(config.Warnings(), "\n")
+		if len(config.Warnings()) != 2 || !strings.Contains(warnings, "CHECK_MISSING") || !strings.Contains(warnings, "CHECK_REQUIRED") {
+			t.Errorf("Advertencias incorrectas: %q", config.Warnings())
+		}
+	})
+
+	t.Run("Modo estricto", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", worker)
+		config.SetEnvStrictness(compose.EnvCheckStrict)
+
+		_, err := config.Bytes()
+		if err == nil || !strings.Contains(err.Error(), "[CHECK_MISSING CHECK_REQUIRED]") {
+			t.Errorf("Error inesperado: %v", err)
+		}
+	})
+
+	t.Run("Verificación desactivada", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", worker)
+		config.SetEnvStrictness(compose.EnvCheckOff)
+
+		if _, err := config.Bytes(); err != nil || len(config.Warnings()) != 0 {
+			t.Errorf("No se esperaban errores ni advertencias: %v %q", err, config.Warnings())
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// EnvCipher encrypts and decrypts the content of managed env files
+type EnvCipher interface {
+	Encrypt(plain []byte) ([]byte, error)
+	Decrypt(encrypted []byte) ([]byte, error)
+}
+
+// encryptedEnvSuffix is appended to the env path when encryption is enabled
+const encryptedEnvSuffix = ".enc"
+
+// SetEnvEncryption enables encryption for the env files written by the config.
+// While enabled, its env variables are written to "<path>.enc" instead of the
+// plaintext file, which is never created. Pass nil to disable it again.
+func (c *composeConfig) SetEnvEncryption(cipher EnvCipher) *composeConfig {
+	c.files.cipher = cipher
+	return c
+}
+
+// DecryptEnvFile reads and decrypts an encrypted env file (e.g. ".env.enc"),
+// returning its variables so they can be loaded at runtime
+func DecryptEnvFile(path string, cipher EnvCipher) (map[string]string, error) {
+	data, err := osFiles.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := cipher.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting %s: %w", path, err)
+	}
+	return parseEnv(plain), nil
+}
+
+// AgeCipher returns a cipher backed by the age CLI. Recipients are used to
+// encrypt and identityFile (e.g. ~/.config/age/keys.txt) to decrypt.
+func AgeCipher(identityFile string, recipients ...string) EnvCipher {
+	return ageCipher{identityFile: identityFile, recipients: recipients}
+}
+
+type ageCipher struct {
+	identityFile string
+	recipients   []string
+}
+
+func (a ageCipher) Encrypt(plain []byte) ([]byte, error) {
+	if len(a.recipients) == 0 {
+		return nil, fmt.Errorf("age encryption requires at least one recipient")
+	}
+	args := []string{"--armor"}
+	for _, r := range a.recipients {
+		args = append(args, "-r", r)
+	}
+	return runFilter("age", plain, args...)
+}
+
+func (a ageCipher) Decrypt(encrypted []byte) ([]byte, error) {
+	return runFilter("age", encrypted, "-d", "-i", a.identityFile)
+}
+
+// SopsCipher returns a cipher backed by the sops CLI using the dotenv format.
+// Keys are taken from .sops.yaml unless extra args (e.g. "--age", "age1...") are given.
+func SopsCipher(args ...string) EnvCipher {
+	return sopsCipher{args: args}
+}
+
+type sopsCipher struct {
+	args []string
+}
+
+func (s sopsCipher) Encrypt(plain []byte) ([]byte, error) {
+	return runFilter("sops", plain, s.command("--encrypt")...)
+}
+
+func (s sopsCipher) Decrypt(encrypted []byte) ([]byte, error) {
+	return runFilter("sops", encrypted, s.command("--decrypt")...)
+}
+
+func (s sopsCipher) command(action string) []string {
+	args := append([]string{action, "--input-type", "dotenv", "--output-type", "dotenv"}, s.args...)
+	return append(args, "/dev/stdin")
+}
+
+// runFilter runs an external tool feeding input through stdin and returns stdout
+func runFilter(name string, input []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestEncryptedEnv(t *testing.T) {
+	// age falso: "cifra" con base64 para poder verificar el flujo completo
+	bin := t.TempDir()
+	script := "#!/bin/sh\ncase \"$*\" in *-d*) base64 -d;; *) base64;; esac\n"
+	if err := os.WriteFile(filepath.Join(bin, "age"), []byte(script), 0755); err != nil {
+		t.Fatalf("Error creando age falso: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	chdir(t, t.TempDir())
+	t.Setenv("API_TOKEN", "s3cr3t")
+	t.Setenv("DB_HOST", "db")
+
+	cipher := compose.AgeCipher("keys.txt", "age1recipient")
+	api := *compose.NewService("api").SetImage("api:1.0").
+		AddEnvironment("API_TOKEN").
+		AddEnvironment("DB_HOST")
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetEnvEncryption(cipher)
+
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Error("No debe existir el .env en texto plano")
+	}
+	if _, err := os.Stat(".gitignore"); !os.IsNotExist(err) {
+		t.Error("El .env cifrado no necesita .gitignore")
+	}
+
+	encrypted := string(readFile(t, ".env.enc"))
+	if strings.Contains(encrypted, "s3cr3t") {
+		t.Error("El archivo cifrado contiene el secreto en texto plano")
+	}
+
+	vars, err := compose.DecryptEnvFile(".env.enc", cipher)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if vars["API_TOKEN"] != "s3cr3t" || vars["DB_HOST"] != "db" {
+		t.Errorf("Variables descifradas incorrectas: %v", vars)
+	}
+
+	// otra configuración del mismo proceso no hereda el cifrado
+	plain, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx").AddEnvironment("WEB_TOKEN", "t0k"))
+	if _, err := plain.Save(context.Background(), compose.SaveTo("web.yml")); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(readFile(t, ".env")), "WEB_TOKEN=t0k") {
+		t.Error("Se esperaba el .env en texto plano para la otra configuración")
+	}
+}
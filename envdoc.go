@@ -0,0 +1,225 @@
+package compose
+
+import (
+	"strings"
+)
+
+// envLine is a single line of an env file. Comments and blank lines are kept
+// verbatim so they survive updates; variable lines also carry their key/value
+type envLine struct {
+	text  string
+	key   string
+	value string
+	isVar bool
+}
+
+// envDocument is an ordered view of an env file that preserves comments,
+// blank lines and the position of every variable across updates
+type envDocument struct {
+	lines []envLine
+}
+
+// parseEnvDocument parses the content of an env file, keeping every line
+func parseEnvDocument(data []byte) *envDocument {
+	doc := &envDocument{}
+
+	content := strings.TrimSuffix(string(data), "\n")
+	if content == "" {
+		return doc
+	}
+
+	for _, text := range strings.Split(content, "\n") {
+		line := envLine{text: text}
+		trimmed := strings.TrimSpace(text)
+		if !strings.HasPrefix(trimmed, "#") {
+			if key, value, found := strings.Cut(trimmed, "="); found {
+				line.key, line.value, line.isVar = strings.TrimSpace(key), unquoteEnvValue(value), true
+			}
+		}
+		doc.lines = append(doc.lines, line)
+	}
+	return doc
+}
+
+// varLine builds the line for KEY=value, quoting the value when needed
+func varLine(key, value string) envLine {
+	return envLine{text: key + "=" + quoteEnvValue(value), key: key, value: value, isVar: true}
+}
+
+// headerLine builds the section header of a group
+func headerLine(group string) envLine {
+	return envLine{text: envGroupHeader(group)}
+}
+
+// groupName returns the group opened by a "# === group ===" header line
+func (l envLine) groupName() (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(l.text), "# === ")
+	if !ok || !strings.HasSuffix(name, " ===") {
+		return "", false
+	}
+	return strings.TrimSuffix(name, " ==="), true
+}
+
+// blank reports whether the line is empty
+func (l envLine) blank() bool {
+	return strings.TrimSpace(l.text) == ""
+}
+
+// String renders the document back to file content
+func (d *envDocument) String() string {
+	if len(d.lines) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, line := range d.lines {
+		b.WriteString(line.text + "\n")
+	}
+	return b.String()
+}
+
+// vars returns the variables of the document as a map
+func (d *envDocument) vars() map[string]string {
+	vars := make(map[string]string)
+	for _, line := range d.lines {
+		if line.isVar {
+			vars[line.key] = line.value
+		}
+	}
+	return vars
+}
+
+// index returns the line of key, or -1 if the key is not defined
+func (d *envDocument) index(key string) int {
+	for i, line := range d.lines {
+		if line.isVar && line.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupAt returns the group of the section containing line i
+func (d *envDocument) groupAt(i int) string {
+	for ; i >= 0; i-- {
+		if name, ok := d.lines[i].groupName(); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// set adds or updates a variable. Existing variables are updated in place and
+// keep their group unless a different one is given; new ones are appended to
+// the end of their group's section, creating it in alphabetical order if needed
+func (d *envDocument) set(group, key, value string) {
+	if i := d.index(key); i >= 0 {
+		if group == "" || group == d.groupAt(i) {
+			d.lines[i] = varLine(key, value)
+			return
+		}
+		d.lines = append(d.lines[:i], d.lines[i+1:]...)
+	}
+
+	start, end, found := d.section(group)
+	if !found {
+		d.addSection(group, varLine(key, value))
+		return
+	}
+
+	// after the last non-blank line of the section
+	pos := start
+	for i := start; i < end; i++ {
+		if !d.lines[i].blank() {
+			pos = i + 1
+		}
+	}
+	d.insert(pos, varLine(key, value))
+
+	// keep a blank line between the ungrouped variables and the first section
+	if pos+1 < len(d.lines) {
+		if _, ok := d.lines[pos+1].groupName(); ok {
+			d.insert(pos+1, envLine{})
+		}
+	}
+}
+
+// remove deletes key, and its group header when the section is left empty.
+// Reports whether the key was defined
+func (d *envDocument) remove(key string) bool {
+	i := d.index(key)
+	if i < 0 {
+		return false
+	}
+
+	group := d.groupAt(i)
+	d.lines = append(d.lines[:i], d.lines[i+1:]...)
+	if group == "" {
+		return true
+	}
+
+	start, end, _ := d.section(group)
+	for _, line := range d.lines[start+1 : end] {
+		if !line.blank() {
+			return true
+		}
+	}
+	d.lines = append(d.lines[:start], d.lines[end:]...)
+
+	// no dejar líneas en blanco sobrantes al final
+	for n := len(d.lines); n > 0 && d.lines[n-1].blank(); n-- {
+		d.lines = d.lines[:n-1]
+	}
+	return true
+}
+
+// keys returns the defined keys in file order
+func (d *envDocument) keys() []string {
+	var keys []string
+	for _, line := range d.lines {
+		if line.isVar {
+			keys = append(keys, line.key)
+		}
+	}
+	return keys
+}
+
+// section returns the line range [start, end) of a group. The ungrouped
+// section runs from the top of the file to the first header
+func (d *envDocument) section(group string) (start, end int, found bool) {
+	start, found = 0, group == ""
+	for i, line := range d.lines {
+		name, ok := line.groupName()
+		if !ok {
+			continue
+		}
+		if found {
+			return start, i, true
+		}
+		if name == group {
+			start, found = i, true
+		}
+	}
+	return start, len(d.lines), found
+}
+
+// addSection creates the section of group before the first section that sorts
+// after it, or at the end of the file
+func (d *envDocument) addSection(group string, line envLine) {
+	for i, l := range d.lines {
+		if name, ok := l.groupName(); ok && name > group {
+			d.insert(i, headerLine(group), line, envLine{})
+			return
+		}
+	}
+
+	if n := len(d.lines); n > 0 && !d.lines[n-1].blank() {
+		d.lines = append(d.lines, envLine{})
+	}
+	d.lines = append(d.lines, headerLine(group), line)
+}
+
+// insert places lines at position i
+func (d *envDocument) insert(i int, lines ...envLine) {
+	d.lines = append(d.lines[:i], append(lines, d.lines[i:]...)...)
+}
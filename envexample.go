@@ -0,0 +1,81 @@
+package compose
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// envExampleFile es el ejemplo sin secretos que se genera junto al .env
+const envExampleFile = defaultEnvFile + ".example"
+
+// WithEnvExample hace que Save escriba también .env.example, seguro de
+// versionar, con todas las claves del .env sin sus valores
+func WithEnvExample() SaveOption {
+	return func(o *saveOptions) {
+		o.envExample = true
+	}
+}
+
+// EnvExampleBytes genera el contenido de .env.example: cada clave del .env
+// junto al archivo compose y de los servicios, sin valor y con un comentario
+// de los servicios que la usan
+func (c *composeConfig) EnvExampleBytes() ([]byte, error) {
+	users := make(map[string][]string)
+	for _, s := range c.services {
+		for key := range s.environment {
+			users[key] = append(users[key], s.name)
+		}
+	}
+
+	data, err := c.files.readEnvData(c.besideComposeFile(defaultEnvFile))
+	if err != nil {
+		return nil, err
+	}
+	keys := parseEnvDocument(data).keys()
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		seen[key] = true
+	}
+	for _, key := range sortedKeys(users) {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Copy to .env and fill in the values\n")
+	for _, key := range keys {
+		b.WriteString("\n")
+		if names := users[key]; len(names) > 0 {
+			sort.Strings(names)
+			b.WriteString("# used by: " + strings.Join(names, ", ") + "\n")
+		}
+		b.WriteString(key + "=\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// saveEnvExample escribe .env.example junto al archivo compose solo si su
+// contenido cambió
+func (c *composeConfig) saveEnvExample() error {
+	data, err := c.EnvExampleBytes()
+	if err != nil {
+		return err
+	}
+	path := c.besideComposeFile(envExampleFile)
+	if current, err := c.files.readFile(path); err == nil && string(current) == string(data) {
+		return nil
+	}
+	return c.files.writeFile(path, data, defaultComposeFileMode)
+}
+
+// besideComposeFile devuelve la ruta de name en el directorio del proyecto,
+// donde docker compose busca el .env: el del archivo compose o projectDir si
+// el archivo se guarda fuera de él, como hace MakeEphemeral
+func (c *composeConfig) besideComposeFile(name string) string {
+	if c.projectDir != "" {
+		return filepath.Join(c.projectDir, name)
+	}
+	return filepath.Join(filepath.Dir(c.composeFile()), name)
+}
@@ -0,0 +1,81 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestEnvExample(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	if err := os.WriteFile(".env", []byte("EXTRA=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	db := *compose.NewService("db").SetImage("postgres").AddEnvironment("DB_PASSWORD", "secret")
+	api := *compose.NewService("api").SetImage("myapi").
+		AddEnvironment("DB_PASSWORD", "secret").
+		AddEnvironment("PORT", "8080")
+
+	config, _ := compose.NewCompose("3.8", db, api)
+	if _, err := config.Save(context.Background(), compose.WithEnvExample()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	expected := "# Copy to .env and fill in the values\n\nEXTRA=\n\n# used by: api, db\nDB_PASSWORD=\n\n# used by: api\nPORT=\n"
+	if got := string(readFile(t, ".env.example")); got != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, got)
+	}
+
+	// con SaveTo el .env y el ejemplo son los del directorio del archivo compose
+	if err := os.MkdirAll("deploy", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("deploy/.env", []byte("DEPLOY_ONLY=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Save(context.Background(), compose.SaveTo("deploy/docker-compose.yml"), compose.WithEnvExample()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	expected = "# Copy to .env and fill in the values\n\nDEPLOY_ONLY=\n\n# used by: api, db\nDB_PASSWORD=\n\n# used by: api\nPORT=\n"
+	if got := string(readFile(t, "deploy/.env.example")); got != expected {
+		t.Errorf("Contenido inesperado:\nEsperado: %q\nObtenido: %q", expected, got)
+	}
+}
+
+func TestSaveToSubdirectoryKeepsEnvBesideCompose(t *testing.T) {
+	chdir(t, t.TempDir())
+	if err := os.Mkdir("deploy", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	api := *compose.NewService("api").SetImage("myapi").
+		AddEnvironment("PORT", "8080").
+		AddSecretEnvironment("API_TOKEN")
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetEnvStrictness(compose.EnvCheckStrict)
+
+	if _, err := config.Save(context.Background(), compose.SaveTo("deploy/docker-compose.yml"), compose.WithEnvExample()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if env := string(readFile(t, "deploy/.env")); !strings.Contains(env, "PORT=8080\n") || !strings.Contains(env, "API_TOKEN=") {
+		t.Errorf("deploy/.env incorrecto: %q", env)
+	}
+	if ignore := string(readFile(t, "deploy/.gitignore")); ignore != ".env\n" {
+		t.Errorf("deploy/.gitignore incorrecto: %q", ignore)
+	}
+	if example := string(readFile(t, "deploy/.env.example")); !strings.Contains(example, "API_TOKEN=\n") {
+		t.Errorf("deploy/.env.example incorrecto: %q", example)
+	}
+	for _, name := range []string{".env", ".gitignore", ".env.example"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("No debe escribirse %s en el directorio de trabajo", name)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Environment es una etapa de despliegue (dev, staging, prod) con su propio
+// archivo .env.<entorno> y su override docker-compose.<entorno>.yml
+type Environment string
+
+// Entornos habituales; se admite cualquier otro nombre
+const (
+	EnvDev     Environment = "dev"
+	EnvStaging Environment = "staging"
+	EnvProd    Environment = "prod"
+)
+
+// EnvFile devuelve el archivo de variables del entorno, por ejemplo ".env.prod"
+func (e Environment) EnvFile() string {
+	return defaultEnvFile + "." + string(e)
+}
+
+// AddEnvironmentFor funciona como AddEnvironment pero solo para el entorno env:
+// el valor privado se guarda en .env.<env> al guardar la configuración y el
+// público en el override del entorno
+func (s *service) AddEnvironmentFor(env Environment, key string, value ...string) *service {
+	envPubValue, envPrivValue, err := resolveEnvValue(s.name, key, value...)
+	if err != nil {
+		s.errors = append(s.errors, err)
+		return s
+	}
+
+	if envPrivValue != "" {
+		s.storeEnv(deferredEnv{key: key, file: env.EnvFile(), value: &envPrivValue})
+	}
+
+	if s.envOverrides == nil {
+		s.envOverrides = make(map[Environment]map[string]string)
+	}
+	if s.envOverrides[env] == nil {
+		s.envOverrides[env] = make(map[string]string)
+	}
+	s.envOverrides[env][key] = envPubValue
+	return s
+}
+
+// Environments devuelve, ordenados, los entornos usados por los servicios
+func (c *composeConfig) Environments() []Environment {
+	seen := make(map[Environment]bool)
+	for _, s := range c.services {
+		for env := range s.envOverrides {
+			seen[env] = true
+		}
+	}
+
+	envs := make([]Environment, 0, len(seen))
+	for env := range seen {
+		envs = append(envs, env)
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i] < envs[j] })
+	return envs
+}
+
+// UseEnvironment hace que los comandos docker compose usen --env-file .env.<env>
+// y apilen el override del entorno sobre el archivo base
+func (c *composeConfig) UseEnvironment(env Environment) *composeConfig {
+	c.environment = env
+	return c
+}
+
+// overrideFile devuelve la ruta del override del entorno junto al archivo base,
+// por ejemplo docker-compose.prod.yml
+func (c *composeConfig) overrideFile(env Environment) string {
+	base := c.composeFile()
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + string(env) + ext
+}
+
+// EnvironmentBytes genera el override del entorno con los valores de
+// AddEnvironmentFor de cada servicio
+func (c *composeConfig) EnvironmentBytes(env Environment) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("configuración inválida: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("services:\n")
+	for _, s := range c.services {
+		vars := s.envOverrides[env]
+		if len(vars) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  %s:\n", yamlPlain(s.name))
+		b.WriteString("    environment:\n")
+		for _, key := range sortedKeys(vars) {
+			fmt.Fprintf(&b, "      %s: %s\n", yamlQuote(key), yamlQuote(vars[key]))
+		}
+	}
+	return c.withHeader(c.lintYAML([]byte(b.String()))), nil
+}
+
+// SaveEnvironments escribe el override de cada entorno usado junto al archivo
+// base, solo si su contenido cambió, junto con los .env.<env> de
+// AddEnvironmentFor. Devuelve las rutas de los overrides
+func (c *composeConfig) SaveEnvironments(ctx context.Context) ([]string, error) {
+	// generar todo antes de escribir, así un error no deja archivos a medias
+	envs := c.Environments()
+	overrides := make([][]byte, len(envs))
+	for i, env := range envs {
+		data, err := c.EnvironmentBytes(env)
+		if err != nil {
+			return nil, err
+		}
+		overrides[i] = data
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := c.resolveDeferredEnv(); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for i, env := range envs {
+		if err := ctx.Err(); err != nil {
+			return paths, err
+		}
+
+		data := overrides[i]
+		path := c.overrideFile(env)
+		if current, err := c.files.readFile(path); err != nil || string(current) != string(data) {
+			if err := c.files.writeFile(path, data, defaultComposeFileMode); err != nil {
+				return paths, err
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,63 @@
+package compose_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestEnvironments(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	t.Setenv("DB_PASSWORD", "prod-secret")
+
+	api := *compose.NewService("api").
+		SetImage("myapi").
+		AddEnvironment("LOG_LEVEL", "debug").
+		AddEnvironmentFor(compose.EnvDev, "LOG_LEVEL", "debug").
+		AddEnvironmentFor(compose.EnvProd, "LOG_LEVEL", "warn").
+		AddEnvironmentFor(compose.EnvProd, "DB_PASSWORD")
+
+	config, _ := compose.NewCompose("3.8", api)
+	if err := config.SaveIfDifferent(); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	paths, err := config.SaveEnvironments(context.Background())
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if strings.Join(paths, ",") != "docker-compose.dev.yml,docker-compose.prod.yml" {
+		t.Errorf("Overrides incorrectos: %v", paths)
+	}
+
+	prod := string(readFile(t, "docker-compose.prod.yml"))
+	for _, want := range []string{
+		"services:\n  api:\n    environment:\n",
+		`      "DB_PASSWORD": "${DB_PASSWORD}"`,
+		`      "LOG_LEVEL": "warn"`,
+	} {
+		if !strings.Contains(prod, want) {
+			t.Errorf("Falta %q en el override:\n%s", want, prod)
+		}
+	}
+
+	if env := string(readFile(t, ".env.prod")); env != "LOG_LEVEL=warn\nDB_PASSWORD=prod-secret\n" {
+		t.Errorf(".env.prod incorrecto: %q", env)
+	}
+	if env := string(readFile(t, ".env.dev")); env != "LOG_LEVEL=debug\n" {
+		t.Errorf(".env.dev incorrecto: %q", env)
+	}
+
+	log := fakeDocker(t, "")
+	if err := config.UseEnvironment(compose.EnvProd).Up(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	want := "compose --env-file .env.prod -f docker-compose.yml -f docker-compose.prod.yml up -d"
+	if calls := dockerCalls(t, log); calls[0] != want {
+		t.Errorf("Comando incorrecto:\nEsperado: %q\nObtenido: %q", want, calls[0])
+	}
+}
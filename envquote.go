@@ -0,0 +1,85 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// envKeyPattern is the accepted form of variable names in managed env files
+var envKeyPattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// safeEnvValue matches values that can be written without quotes
+var safeEnvValue = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// validateEnvKey checks that key is a valid env file variable name
+func validateEnvKey(key string) error {
+	if !envKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid env key %q: must match [A-Z_][A-Z0-9_]*", key)
+	}
+	return nil
+}
+
+// quoteEnvValue formats a value for an env file following the usual dotenv
+// conventions: plain values are written as is, values without newlines or
+// single quotes are single quoted (taken literally), anything else is double
+// quoted with \\, \", \n, \r and \t escapes and $ written as $$, since
+// docker compose interpolates double quoted values
+func quoteEnvValue(value string) string {
+	if safeEnvValue.MatchString(value) {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "$", "$$")
+	return `"` + r.Replace(value) + `"`
+}
+
+// unquoteEnvValue reverses quoteEnvValue. Unquoted values lose surrounding
+// spaces and any inline " #" comment
+func unquoteEnvValue(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		return raw[1 : len(raw)-1]
+	}
+
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		var b strings.Builder
+		inner := raw[1 : len(raw)-1]
+		for i := 0; i < len(inner); i++ {
+			c := inner[i]
+			if c == '$' && i < len(inner)-1 && inner[i+1] == '$' {
+				b.WriteByte('$')
+				i++
+				continue
+			}
+			if c != '\\' || i == len(inner)-1 {
+				b.WriteByte(c)
+				continue
+			}
+			i++
+			switch inner[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"':
+				b.WriteByte(inner[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(inner[i])
+			}
+		}
+		return b.String()
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	return raw
+}
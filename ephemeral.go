@@ -0,0 +1,145 @@
+package compose
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ephemeralTeardownTimeout limita el tiempo de "docker compose down" al terminar
+const ephemeralTeardownTimeout = 2 * time.Minute
+
+// TestingT es el subconjunto de testing.TB que usa Ephemeral
+type TestingT interface {
+	Helper()
+	Name() string
+	TempDir() string
+	Cleanup(func())
+}
+
+// Ephemeral prepara un stack aislado para tests o jobs de CI en paralelo:
+// nombre de proyecto único, puertos del host libres elegidos al azar, sin
+// container_name fijos y el archivo en un directorio temporal; los comandos
+// docker compose usan el directorio actual como --project-directory y su .env,
+// de modo que las variables y las rutas relativas no cambian. Los volúmenes con
+// nombre quedan dentro del proyecto y se eliminan con "docker compose down -v",
+// que se registra en t.Cleanup y por lo tanto se ejecuta aunque el test entre en pánico.
+// Usar HostPort para conocer el puerto asignado a cada servicio
+func Ephemeral(t TestingT, version string, services ...service) (*composeConfig, error) {
+	t.Helper()
+
+	config, err := NewCompose(version, services...)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.MakeEphemeral(t); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// MakeEphemeral aplica a una configuración ya construida lo mismo que Ephemeral
+func (c *composeConfig) MakeEphemeral(t TestingT) error {
+	t.Helper()
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := projectNamePattern.ReplaceAllString(strings.ToLower(t.Name()), "-")
+	if len(name) > 40 {
+		name = name[:40]
+	}
+
+	c.mu.Lock()
+	c.projectName = strings.Trim("eph-"+name, "-") + "-" + hex.EncodeToString(suffix)
+	c.ephemeralPorts = make(map[string]string)
+	for i := range c.services {
+		s := &c.services[i]
+		s.containerName = ""
+
+		ports := make([]string, 0, len(s.ports))
+		for _, port := range s.ports {
+			rewritten, err := c.randomizeHostPort(s.name, port)
+			if err != nil {
+				c.mu.Unlock()
+				return err
+			}
+			ports = append(ports, rewritten)
+		}
+		s.ports = ports
+	}
+	c.mu.Unlock()
+
+	// el archivo va a un directorio temporal, pero ${VAR}, ./ y build siguen
+	// resolviéndose desde el proyecto
+	dir, err := filepath.Abs(".")
+	if err != nil {
+		return err
+	}
+	c.projectDir = dir
+
+	if err := c.SaveIfDifferent(filepath.Join(t.TempDir(), defaultComposeFile)); err != nil {
+		return err
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ephemeralTeardownTimeout)
+		defer cancel()
+		c.runCompose(ctx, "down", "-v", "--remove-orphans")
+	})
+	return nil
+}
+
+// HostPort devuelve el puerto del host asignado por Ephemeral al puerto del
+// contenedor indicado, o vacío si el servicio no lo publica
+func (c *composeConfig) HostPort(service, containerPort string) string {
+	return c.ephemeralPorts[service+"/"+containerPort]
+}
+
+// randomizeHostPort reemplaza el puerto del host de una publicación por uno libre.
+// Los rangos de puertos se dejan sin cambios
+func (c *composeConfig) randomizeHostPort(serviceName, port string) (string, error) {
+	spec, proto, hasProto := strings.Cut(port, "/")
+
+	i := strings.LastIndex(spec, ":")
+	container := spec[i+1:]
+	if strings.Contains(container, "-") {
+		return port, nil
+	}
+
+	ip := ""
+	if i >= 0 {
+		if j := strings.LastIndex(spec[:i], ":"); j >= 0 {
+			ip = spec[:j+1]
+		}
+	}
+
+	free, err := freePort()
+	if err != nil {
+		return "", err
+	}
+	c.ephemeralPorts[serviceName+"/"+container] = free
+
+	out := ip + free + ":" + container
+	if hasProto {
+		out += "/" + proto
+	}
+	return out, nil
+}
+
+// freePort pide al sistema un puerto TCP libre
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("error finding a free port: %w", err)
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port), nil
+}
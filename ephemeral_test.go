@@ -0,0 +1,48 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestEphemeral(t *testing.T) {
+	log := fakeDocker(t, "")
+
+	t.Run("stack", func(t *testing.T) {
+		db := *compose.NewService("db").SetImage("postgres:16").AddPort("5432", "5432")
+		config, err := compose.Ephemeral(t, "3.8", db)
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		port := config.HostPort("db", "5432")
+		if port == "" || port == "5432" {
+			t.Errorf("Puerto aleatorio no asignado: %q", port)
+		}
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if strings.Contains(string(data), "container_name") || !strings.Contains(string(data), port+":5432") {
+			t.Errorf("YAML efímero incorrecto:\n%s", data)
+		}
+	})
+
+	calls := dockerCalls(t, log)
+	last := calls[len(calls)-1]
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(last, "--project-directory "+wd+" --env-file "+filepath.Join(wd, ".env")+" -f ") {
+		t.Errorf("El proyecto efímero debe resolverse desde el directorio actual: %q", last)
+	}
+	if !strings.Contains(last, "-p eph-testephemeral-stack-") || !strings.HasSuffix(last, "down -v --remove-orphans") {
+		t.Errorf("Se esperaba el down del proyecto efímero: %q", last)
+	}
+}
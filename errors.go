@@ -0,0 +1,79 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errores que se pueden distinguir con errors.Is
+var (
+	ErrEnvVarNotFound       = errors.New("environment variable not found")
+	ErrInvalidPort          = errors.New("invalid port")
+	ErrPortConflict         = errors.New("port conflict")
+	ErrPortInUse            = errors.New("port already in use")
+	ErrInvalidRestartPolicy = errors.New("invalid restart policy")
+	ErrInvalidValue         = errors.New("invalid")
+	ErrDuplicateService     = errors.New("duplicate service name")
+	ErrDuplicateContainer   = errors.New("duplicate container name")
+	ErrUnknownDependency    = errors.New("depends on unknown service")
+	ErrDependencyCycle      = errors.New("dependency cycle detected")
+	ErrMissingImage         = errors.New("has no image and no build context")
+	ErrSchemaViolation      = errors.New("does not match compose-spec schema")
+	ErrBindSourceNotFound   = errors.New("bind mount source not found")
+)
+
+// ValidationError describe un problema en un campo de un servicio. Se obtiene
+// con errors.As y su causa se compara con errors.Is contra los Err* del paquete
+type ValidationError struct {
+	Service string // servicio afectado, vacío si es de la configuración
+	Field   string // clave del compose, por ejemplo "ports" o "restart"
+	Value   string // valor rechazado, si aplica
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if e.Service != "" {
+		fmt.Fprintf(&b, "service %q: ", e.Service)
+	}
+	b.WriteString(e.Err.Error())
+	if e.Value != "" {
+		fmt.Fprintf(&b, " %q", e.Value)
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalidField construye el error de un valor no aceptado en field, por
+// ejemplo `service "api": invalid platform "linux"`
+func invalidField(service, field, value string) *ValidationError {
+	return &ValidationError{
+		Service: service,
+		Field:   field,
+		Value:   value,
+		Err:     fmt.Errorf("%w %s", ErrInvalidValue, strings.ReplaceAll(field, "_", " ")),
+	}
+}
+
+// Err devuelve los errores acumulados al construir el servicio, unidos en uno
+// solo, o nil. Permite fallar antes de generar el YAML
+func (s *service) Err() error {
+	return errors.Join(s.errors...)
+}
+
+// Err devuelve los errores acumulados por los servicios de la configuración.
+// A diferencia de Validate no revisa las relaciones entre servicios
+func (c *composeConfig) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, s := range c.services {
+		errs = append(errs, s.errors...)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package compose_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestTypedErrors(t *testing.T) {
+	ghost := *compose.NewService("ghost").SetImage("alpine")
+	a := *compose.NewService("a").SetImage("alpine").AddPort("80a", "80").AddEnvironment("TYPED_ERRORS_MISSING")
+	b := *compose.NewService("a").SetImage("alpine").SetContainerName("a").DependsOn(ghost)
+
+	config, _ := compose.NewCompose("3.8", a, b)
+	err := config.Validate()
+
+	for _, target := range []error{
+		compose.ErrInvalidPort,
+		compose.ErrEnvVarNotFound,
+		compose.ErrDuplicateService,
+		compose.ErrDuplicateContainer,
+		compose.ErrUnknownDependency,
+	} {
+		if !errors.Is(err, target) {
+			t.Errorf("Se esperaba errors.Is(err, %v):\n%v", target, err)
+		}
+	}
+
+	var verr *compose.ValidationError
+	if !errors.As(err, &verr) || verr.Service != "a" {
+		t.Errorf("Se esperaba un ValidationError del servicio a: %#v", verr)
+	}
+
+	s := *compose.NewService("x").SetImage("x").SetPlatform("linux")
+	config, _ = compose.NewCompose("3.8", s)
+	err = config.Validate()
+	if !errors.Is(err, compose.ErrInvalidValue) || !errors.As(err, &verr) || verr.Field != "platform" {
+		t.Errorf("Error de plataforma incorrecto: %v", err)
+	}
+	if err.Error() != `service "x": invalid platform "linux"` {
+		t.Errorf("Mensaje incorrecto: %v", err)
+	}
+
+	for _, tc := range []struct {
+		field   string
+		service *compose.Service
+	}{
+		{"network_mode", compose.NewService("x").SetImage("x").SetNetworkMode("overlay")},
+		{"network_mode", compose.NewService("x").SetImage("x").SetNetworkMode("host").AddPort("80", "80")},
+		{"network_mode", compose.NewService("x").SetImage("x").SetNetworkMode("service:ghost")},
+		{"config_name", compose.NewService("x").SetImage("x").AddConfig(compose.InlineConfig{Content: "x"})},
+		{"secret_length", compose.NewService("x").SetImage("x").AddSecretEnvironment("API_KEY", 0)},
+	} {
+		config, _ = compose.NewCompose("3.8", *tc.service)
+		err = config.Validate()
+		if !errors.As(err, &verr) || verr.Service != "x" || verr.Field != tc.field {
+			t.Errorf("Se esperaba un ValidationError de %s: %v", tc.field, err)
+		}
+	}
+}
+
+func TestErrAccessors(t *testing.T) {
+	ok := compose.NewService("ok").SetImage("nginx")
+	if err := ok.Err(); err != nil {
+		t.Errorf("Error inesperado: %v", err)
+	}
+
+	bad := compose.NewService("bad").SetImage("nginx").SetRestartPolicy("sometimes").SetScale(-1)
+	if err := bad.Err(); !errors.Is(err, compose.ErrInvalidRestartPolicy) || !errors.Is(err, compose.ErrInvalidValue) {
+		t.Errorf("Se esperaban ambos errores: %v", err)
+	}
+
+	config, _ := compose.NewCompose("3.8", *ok, *bad)
+	if err := config.Err(); !errors.Is(err, compose.ErrInvalidRestartPolicy) {
+		t.Errorf("Se esperaba el error del servicio: %v", err)
+	}
+}
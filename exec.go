@@ -0,0 +1,116 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// ExecOption configura el comportamiento de Exec
+type ExecOption func(*execOptions)
+
+type execOptions struct {
+	user    string
+	workdir string
+	env     []string
+	stdin   io.Reader
+	index   int
+}
+
+// ExecUser ejecuta el comando como el usuario indicado, por ejemplo "postgres"
+func ExecUser(user string) ExecOption {
+	return func(o *execOptions) {
+		o.user = user
+	}
+}
+
+// ExecWorkdir ejecuta el comando en el directorio indicado del contenedor
+func ExecWorkdir(dir string) ExecOption {
+	return func(o *execOptions) {
+		o.workdir = dir
+	}
+}
+
+// ExecEnv añade una variable de entorno al comando
+func ExecEnv(key, value string) ExecOption {
+	return func(o *execOptions) {
+		o.env = append(o.env, key+"="+value)
+	}
+}
+
+// ExecStdin pasa r como entrada estándar del comando, por ejemplo un volcado SQL
+func ExecStdin(r io.Reader) ExecOption {
+	return func(o *execOptions) {
+		o.stdin = r
+	}
+}
+
+// ExecIndex elige la réplica del servicio escalado en la que ejecutar (desde 1)
+func ExecIndex(index int) ExecOption {
+	return func(o *execOptions) {
+		o.index = index
+	}
+}
+
+// ExecResult es el resultado de un comando ejecutado con Exec
+type ExecResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// Exec ejecuta un comando puntual (migraciones, psql...) en el contenedor en
+// marcha del servicio con "docker compose exec -T". Un código de salida distinto
+// de cero no es un error: se informa en ExitCode junto con la salida. Solo se
+// devuelve error si no se pudo ejecutar docker o se canceló el contexto
+func (c *composeConfig) Exec(ctx context.Context, service string, cmd []string, opts ...ExecOption) (ExecResult, error) {
+	var o execOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	args := []string{"exec", "-T"}
+	if o.user != "" {
+		args = append(args, "--user", o.user)
+	}
+	if o.workdir != "" {
+		args = append(args, "--workdir", o.workdir)
+	}
+	for _, kv := range o.env {
+		args = append(args, "--env", kv)
+	}
+	if o.index > 0 {
+		args = append(args, "--index", fmt.Sprint(o.index))
+	}
+	args = append(args, service)
+	args = append(args, cmd...)
+
+	args = c.composeArgs(args...)
+	start := time.Now()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, "docker", args...)
+	command.Stdin = o.stdin
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	err := command.Run()
+	c.logCommand(args, start, err)
+	result := ExecResult{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	case err != nil:
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package compose_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestExec(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	config, _ := compose.NewCompose("3.8", db)
+
+	t.Run("Devuelve salida y código", func(t *testing.T) {
+		log := fakeDocker(t, `cat; echo "oops" >&2; exit 3`)
+
+		result, err := config.Exec(context.Background(), "db", []string{"psql", "-c", "select 1"},
+			compose.ExecUser("postgres"), compose.ExecEnv("PGDATABASE", "app"), compose.ExecStdin(strings.NewReader("input\n")))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if string(result.Stdout) != "input\n" || string(result.Stderr) != "oops\n" || result.ExitCode != 3 {
+			t.Errorf("Resultado incorrecto: %+v", result)
+		}
+
+		calls := dockerCalls(t, log)
+		want := "compose -f docker-compose.yml exec -T --user postgres --env PGDATABASE=app db psql -c select 1"
+		if calls[0] != want {
+			t.Errorf("Comando incorrecto:\nEsperado: %q\nObtenido: %q", want, calls[0])
+		}
+	})
+
+	t.Run("Error si docker no está disponible", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if _, err := config.Exec(context.Background(), "db", []string{"true"}); err == nil {
+			t.Error("Se esperaba un error sin docker")
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rawField es una clave con un valor Go arbitrario que se serializa como YAML
+type rawField struct {
+	key   string
+	value any
+}
+
+// setRawField añade o reemplaza key conservando el orden de inserción
+func setRawField(fields []rawField, key string, value any) []rawField {
+	for i := range fields {
+		if fields[i].key == key {
+			fields[i].value = value
+			return fields
+		}
+	}
+	return append(fields, rawField{key, value})
+}
+
+// rawFieldValue devuelve el valor de key, por ejemplo para exportar a otros formatos
+func rawFieldValue(fields []rawField, key string) (any, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+	return nil, false
+}
+
+// SetExtension añade un campo de extensión "x-..." al servicio, con cualquier
+// valor serializable a YAML (mapas, slices, structs con tags yaml...)
+func (s *service) SetExtension(key string, value any) *service {
+	if !strings.HasPrefix(key, "x-") {
+		s.errors = append(s.errors, fmt.Errorf("service %q: extension %q must start with x-", s.name, key))
+		return s
+	}
+	s.extensions = setRawField(s.extensions, key, value)
+	return s
+}
+
+// SetExtension añade un campo de extensión "x-..." de primer nivel, útil para
+// metadatos de la organización o bloques compartidos
+func (c *composeConfig) SetExtension(key string, value any) *composeConfig {
+	c.extensions = setRawField(c.extensions, key, value)
+	return c
+}
+
+// validateExtensions revisa que las extensiones de primer nivel empiecen por x-
+func (c *composeConfig) validateExtensions() []error {
+	var errs []error
+	for _, f := range c.extensions {
+		if !strings.HasPrefix(f.key, "x-") {
+			errs = append(errs, fmt.Errorf("extension %q must start with x-", f.key))
+		}
+	}
+	return errs
+}
+
+// writeRawFields serializa cada campo con yaml.v3 bajo la sangría indicada
+func writeRawFields(b *strings.Builder, indent string, fields []rawField) error {
+	for _, f := range fields {
+		data, err := yaml.Marshal(map[string]any{f.key: f.value})
+		if err != nil {
+			return fmt.Errorf("error marshalling %s: %w", f.key, err)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			if line == "" {
+				b.WriteString("\n")
+				continue
+			}
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtensions(t *testing.T) {
+	type owner struct {
+		Team  string `yaml:"team"`
+		Slack string `yaml:"slack"`
+	}
+
+	web := *compose.NewService("web").
+		SetImage("nginx").
+		SetExtension("x-owner", owner{Team: "platform", Slack: "#web"})
+
+	config, _ := compose.NewCompose("3.8", web)
+	config.SetExtension("x-common", map[string]any{"labels": []string{"a", "b"}})
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Common struct {
+			Labels []string `yaml:"labels"`
+		} `yaml:"x-common"`
+		Services map[string]struct {
+			Owner owner `yaml:"x-owner"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+
+	if strings.Join(result.Common.Labels, ",") != "a,b" {
+		t.Errorf("x-common incorrecto:\n%s", data)
+	}
+	if result.Services["web"].Owner.Team != "platform" {
+		t.Errorf("x-owner incorrecto:\n%s", data)
+	}
+
+	bad := *compose.NewService("web").SetImage("nginx").SetExtension("owner", "x")
+	config, _ = compose.NewCompose("3.8", bad)
+	if _, err := config.Bytes(); err == nil {
+		t.Error("Se esperaba un error por extensión sin prefijo x-")
+	}
+}
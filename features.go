@@ -0,0 +1,74 @@
+package compose
+
+import "fmt"
+
+// Feature identifica una parte nueva o experimental del compose-spec cuya
+// emisión debe activarse explícitamente con EnableFeature
+type Feature string
+
+const (
+	// FeatureInclude habilita la sección superior include (docker compose 2.20+)
+	FeatureInclude Feature = "include"
+	// FeatureDevelop habilita la sección develop de los servicios (docker compose watch)
+	FeatureDevelop Feature = "develop"
+)
+
+// featureAliases acepta nombres alternativos de las features
+var featureAliases = map[Feature]Feature{
+	"x-develop": FeatureDevelop,
+}
+
+// knownFeatures son las features que el generador sabe emitir
+var knownFeatures = map[Feature]bool{
+	FeatureInclude: true,
+	FeatureDevelop: true,
+}
+
+// EnableFeature activa la emisión de features nuevas o experimentales del
+// compose-spec. Sin activarlas la salida se mantiene en la parte estable del spec
+// y Validate falla si la configuración las usa
+func (c *composeConfig) EnableFeature(features ...Feature) *composeConfig {
+	if c.features == nil {
+		c.features = make(map[Feature]bool)
+	}
+	for _, f := range features {
+		if alias, ok := featureAliases[f]; ok {
+			f = alias
+		}
+		c.features[f] = true
+	}
+	return c
+}
+
+// featureEnabled indica si la feature fue activada
+func (c *composeConfig) featureEnabled(f Feature) bool {
+	return c.features[f]
+}
+
+// validateFeatures detecta features desconocidas y usos de features no activadas
+func (c *composeConfig) validateFeatures() []error {
+	var errs []error
+	for f := range c.features {
+		if !knownFeatures[f] {
+			errs = append(errs, fmt.Errorf("unknown feature %q", f))
+		}
+	}
+	if len(c.includes) > 0 && !c.featureEnabled(FeatureInclude) {
+		errs = append(errs, fmt.Errorf("include requires EnableFeature(%q)", FeatureInclude))
+	}
+	if !c.featureEnabled(FeatureDevelop) {
+		for _, s := range c.services {
+			if len(s.developWatch) > 0 {
+				errs = append(errs, fmt.Errorf("service %q: develop.watch requires EnableFeature(%q)", s.name, FeatureDevelop))
+			}
+		}
+	}
+	return errs
+}
+
+// Include añade archivos compose a la sección superior include.
+// Requiere EnableFeature(FeatureInclude)
+func (c *composeConfig) Include(paths ...string) *composeConfig {
+	c.includes = append(c.includes, paths...)
+	return c
+}
@@ -0,0 +1,51 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFeatures(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx")
+
+	t.Run("Include sin activar falla", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", web)
+		config.Include("infra/compose.yml")
+
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "include requires") {
+			t.Errorf("Se esperaba un error por feature no activada: %v", err)
+		}
+	})
+
+	t.Run("Include activado", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", web)
+		config.EnableFeature(compose.FeatureInclude, "x-develop").Include("infra/compose.yml")
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		var result struct {
+			Include []string `yaml:"include"`
+		}
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Error parseando YAML: %v", err)
+		}
+		if len(result.Include) != 1 || result.Include[0] != "infra/compose.yml" {
+			t.Errorf("include incorrecto: %q", result.Include)
+		}
+	})
+
+	t.Run("Feature desconocida", func(t *testing.T) {
+		config, _ := compose.NewCompose("3.8", web)
+		config.EnableFeature("teleport")
+
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `unknown feature "teleport"`) {
+			t.Errorf("Se esperaba un error por feature desconocida: %v", err)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package compose
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// WriteFS es el sistema de archivos sobre el que el paquete lee y escribe los
+// archivos generados: docker-compose.yml y sus overrides, los .env,
+// .env.example, .gitignore y las exportaciones. WriteFile debe reemplazar el
+// archivo completo
+type WriteFS interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(path string, perm fs.FileMode) error
+}
+
+// fileSettings reúne cómo una configuración lee y escribe sus archivos. El
+// valor cero usa el sistema de archivos del sistema operativo, .env en texto
+// plano con modo 0600 y el .gitignore por defecto
+type fileSettings struct {
+	fsys      WriteFS
+	cipher    EnvCipher
+	envPerm   os.FileMode
+	envOwner  []fileOwner
+	gitignore GitignoreOptions
+}
+
+// osFiles son los ajustes de las funciones del paquete que no dependen de una
+// configuración, como AddEnvToFile
+var osFiles = &fileSettings{}
+
+// SetFS hace que las operaciones de archivos de la configuración usen fsys,
+// por ejemplo NewMemFS() en tests. nil vuelve al sistema de archivos del
+// sistema operativo. Los comandos docker (Up, ValidateWithCLI...) y Watch
+// siguen necesitando archivos reales, ya que los lee otro proceso
+func (c *composeConfig) SetFS(fsys WriteFS) *composeConfig {
+	c.files.fsys = fsys
+	return c
+}
+
+// readFile lee path del sistema de archivos configurado
+func (f *fileSettings) readFile(path string) ([]byte, error) {
+	if f.fsys != nil {
+		return f.fsys.ReadFile(path)
+	}
+	return os.ReadFile(path)
+}
+
+// writeFile escribe path en el sistema de archivos configurado; en el del
+// sistema operativo de forma atómica y asignando owner si se indica
+func (f *fileSettings) writeFile(path string, data []byte, perm os.FileMode, owner ...fileOwner) error {
+	if f.fsys != nil {
+		return f.fsys.WriteFile(path, data, perm)
+	}
+	return writeFileAtomic(path, data, perm, owner...)
+}
+
+// mkdirAll crea el directorio en el sistema de archivos configurado
+func (f *fileSettings) mkdirAll(path string, perm os.FileMode) error {
+	if f.fsys != nil {
+		return f.fsys.MkdirAll(path, perm)
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// MemFS es un WriteFS en memoria, seguro para uso concurrente
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]memFile
+}
+
+type memFile struct {
+	data []byte
+	perm fs.FileMode
+}
+
+// NewMemFS crea un sistema de archivos en memoria vacío
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]memFile)}
+}
+
+// ReadFile devuelve una copia del contenido, o un error fs.ErrNotExist
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+// WriteFile guarda una copia de data
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[filepath.Clean(name)] = memFile{append([]byte(nil), data...), perm}
+	return nil
+}
+
+// MkdirAll no hace nada: los directorios de MemFS son implícitos
+func (m *MemFS) MkdirAll(path string, perm fs.FileMode) error {
+	return nil
+}
+
+// Files devuelve las rutas de los archivos escritos, ordenadas
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Perm devuelve el modo con que se escribió name
+func (m *MemFS) Perm(name string) (fs.FileMode, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.files[filepath.Clean(name)]
+	return f.perm, ok
+}
@@ -0,0 +1,59 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestMemFS(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+
+	mem := compose.NewMemFS()
+
+	api := *compose.NewService("api").SetImage("api:1.0").AddEnvironment("API_KEY", "s3cret")
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetFS(mem)
+
+	result, err := config.Save(context.Background(), compose.WithEnvExample())
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if result.Status != compose.SaveCreated {
+		t.Errorf("Se esperaba SaveCreated, se obtuvo %v", result.Status)
+	}
+
+	want := []string{".env", ".env.example", ".gitignore", "docker-compose.yml"}
+	if got := mem.Files(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Archivos incorrectos: %q", got)
+	}
+	if env, _ := mem.ReadFile(".env"); !strings.Contains(string(env), "API_KEY=s3cret") {
+		t.Errorf(".env incorrecto:\n%s", env)
+	}
+	if perm, _ := mem.Perm(".env"); perm != 0600 {
+		t.Errorf("Se esperaba el modo 0600 para .env, se obtuvo %v", perm)
+	}
+
+	// nada llega al disco
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("No se esperaban archivos en disco: %v", entries)
+	}
+
+	// el segundo guardado lee del mismo sistema de archivos
+	if err := config.SaveIfDifferent(); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	status, err := config.SaveIfChanged()
+	if err != nil || status != compose.SaveUnchanged {
+		t.Errorf("Se esperaba SaveUnchanged: %v %v", status, err)
+	}
+
+	if _, err := mem.ReadFile("missing.yml"); !os.IsNotExist(err) {
+		t.Errorf("Se esperaba un error de archivo inexistente: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GenerateOnly devuelve una copia de la configuración con solo los servicios
+// indicados y sus dependencias transitivas, manteniendo el orden original
+func (c *composeConfig) GenerateOnly(names ...string) (*composeConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index := make(map[string]service, len(c.services))
+	for _, s := range c.services {
+		index[s.name] = s
+	}
+
+	var errs []error
+	keep := make(map[string]bool)
+
+	var include func(name string)
+	include = func(name string) {
+		if keep[name] {
+			return
+		}
+		s, exists := index[name]
+		if !exists {
+			return
+		}
+		keep[name] = true
+		for _, dep := range s.serviceDependencies {
+			include(dep)
+		}
+	}
+
+	for _, name := range names {
+		if _, exists := index[name]; !exists {
+			errs = append(errs, fmt.Errorf("unknown service %q", name))
+			continue
+		}
+		include(name)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return c.filtered(keep), nil
+}
+
+// GenerateExcept devuelve una copia de la configuración sin los servicios indicados,
+// salvo los que sigan siendo necesarios como dependencia de los servicios restantes
+func (c *composeConfig) GenerateExcept(names ...string) (*composeConfig, error) {
+	exclude := make(map[string]bool, len(names))
+	for _, name := range names {
+		exclude[name] = true
+	}
+
+	c.mu.Lock()
+	var errs []error
+	for name := range exclude {
+		if !c.hasService(name) {
+			errs = append(errs, fmt.Errorf("unknown service %q", name))
+		}
+	}
+
+	var remaining []string
+	for _, s := range c.services {
+		if !exclude[s.name] {
+			remaining = append(remaining, s.name)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return c.GenerateOnly(remaining...)
+}
+
+// hasService indica si existe un servicio con ese nombre
+func (c *composeConfig) hasService(name string) bool {
+	for _, s := range c.services {
+		if s.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// filtered copia la configuración conservando solo los servicios marcados. La
+// copia no comparte servicios, mapas ni el lock con el original
+func (c *composeConfig) filtered(keep map[string]bool) *composeConfig {
+	out := c.clone()
+	kept := out.services[:0]
+	for _, s := range out.services {
+		if keep[s.name] {
+			kept = append(kept, s)
+		}
+	}
+	out.services = kept
+	return out
+}
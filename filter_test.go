@@ -0,0 +1,120 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func serviceNames(t *testing.T, data []byte) map[string]bool {
+	t.Helper()
+
+	var result struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	names := make(map[string]bool, len(result.Services))
+	for name := range result.Services {
+		names[name] = true
+	}
+	return names
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error leyendo archivo: %v", err)
+	}
+	return data
+}
+
+// chdir cambia el directorio de trabajo durante el test y lo restaura al terminar
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Error leyendo el directorio de trabajo: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Error cambiando de directorio: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("Error restaurando el directorio de trabajo: %v", err)
+		}
+	})
+}
+
+func TestGenerateFilters(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	cache := *compose.NewService("cache").SetImage("redis:7")
+	api := *compose.NewService("api").SetImage("api:latest").DependsOn(db)
+	web := *compose.NewService("web").SetImage("nginx").DependsOn(api, cache)
+
+	config, _ := compose.NewCompose("3.8", db, cache, api, web)
+
+	t.Run("GenerateOnly incluye dependencias transitivas", func(t *testing.T) {
+		partial, err := config.GenerateOnly("api")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), "docker-compose.yml")
+		if err := partial.SaveIfDifferent(path); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		names := serviceNames(t, readFile(t, path))
+		if len(names) != 2 || !names["api"] || !names["db"] {
+			t.Errorf("Servicios incorrectos: %v", names)
+		}
+	})
+
+	t.Run("GenerateExcept descarta servicios indicados", func(t *testing.T) {
+		partial, err := config.GenerateExcept("web", "cache")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), "docker-compose.yml")
+		if err := partial.SaveIfDifferent(path); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		names := serviceNames(t, readFile(t, path))
+		if len(names) != 2 || !names["api"] || !names["db"] {
+			t.Errorf("Servicios incorrectos: %v", names)
+		}
+	})
+
+	t.Run("La copia no comparte redes ni volúmenes", func(t *testing.T) {
+		config.DefineNetwork("backend", compose.NetworkConfig{})
+		partial, err := config.GenerateOnly("db")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		partial.DefineNetwork("extra", compose.NetworkConfig{}).DefineVolume("extra", compose.VolumeConfig{})
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if strings.Contains(string(data), "extra") {
+			t.Errorf("Modificar la copia alteró el original:\n%s", data)
+		}
+	})
+
+	t.Run("Servicio desconocido", func(t *testing.T) {
+		if _, err := config.GenerateOnly("nope"); err == nil {
+			t.Error("Se esperaba un error por servicio desconocido")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// GitignoreOptions configures how managed env files are added to an ignore file
+type GitignoreOptions struct {
+	// Disabled turns off ignore file management entirely
+	Disabled bool
+	// Path is the ignore file to update when none is passed explicitly,
+	// e.g. ".dockerignore". Defaults to ".gitignore"
+	Path string
+	// Patterns are extra entries to ensure, e.g. ".env.*" or "docker-compose.override.yml"
+	Patterns []string
+}
+
+// SetGitignore changes how the config maintains the ignore file when it
+// writes its env files
+func (c *composeConfig) SetGitignore(opts GitignoreOptions) *composeConfig {
+	c.files.gitignore = opts
+	return c
+}
+
+// gitignorePathFrom returns the ignore file of an optional paths argument
+func (f *fileSettings) gitignorePathFrom(paths []string) string {
+	if len(paths) > 1 {
+		return paths[1]
+	}
+	if p := f.gitignore.Path; p != "" {
+		return p
+	}
+	return ".gitignore"
+}
+
+// handleGitignore ensures the env file and any configured patterns are ignored.
+// Entries already covered by an existing pattern (e.g. ".env*") are not added
+func (f *fileSettings) handleGitignore(gitignorePath string, envPath string) error {
+	opts := f.gitignore
+	if opts.Disabled {
+		return nil
+	}
+
+	var gitignoreContent []string
+	if data, err := f.readFile(gitignorePath); err == nil {
+		gitignoreContent = strings.Split(string(data), "\n")
+	}
+
+	var missing []string
+	for _, entry := range append([]string{filepath.Base(envPath)}, opts.Patterns...) {
+		if !ignored(gitignoreContent, entry) {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Remove empty lines at the end
+	for len(gitignoreContent) > 0 && gitignoreContent[len(gitignoreContent)-1] == "" {
+		gitignoreContent = gitignoreContent[:len(gitignoreContent)-1]
+	}
+	gitignoreContent = append(gitignoreContent, missing...)
+
+	if err := f.writeFile(gitignorePath, []byte(strings.Join(gitignoreContent, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing %s file: %v", filepath.Base(gitignorePath), err)
+	}
+	return nil
+}
+
+// ignored reports whether entry is listed verbatim or matched by a glob
+// pattern in lines. A later negation ("!entry") un-ignores it
+func ignored(lines []string, entry string) bool {
+	covered := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negated := strings.HasPrefix(line, "!")
+		pattern := strings.TrimPrefix(strings.TrimPrefix(line, "!"), "/")
+		if pattern == entry {
+			covered = !negated
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, entry); ok {
+			covered = !negated
+		}
+	}
+	return covered
+}
@@ -0,0 +1,60 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestGitignoreOptions(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+
+	save := func(t *testing.T, opts compose.GitignoreOptions) {
+		t.Helper()
+		web := *compose.NewService("web").SetImage("nginx").AddEnvironment("A", "1")
+		config, _ := compose.NewCompose("3.8", web)
+		config.SetGitignore(opts)
+		if _, err := config.Save(context.Background()); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	}
+
+	t.Run("Patrón existente cubre el .env", func(t *testing.T) {
+		ignore := filepath.Join(dir, "covered")
+		if err := os.WriteFile(ignore, []byte("/.env*\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := compose.AddEnvToFile("A", "1", envPath, ignore); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if got := string(readFile(t, ignore)); got != "/.env*\n" {
+			t.Errorf("No debía modificarse: %q", got)
+		}
+	})
+
+	t.Run("Patrones extra y ruta personalizada", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		save(t, compose.GitignoreOptions{
+			Path:     ".dockerignore",
+			Patterns: []string{".env.*", "docker-compose.override.yml"},
+		})
+		if got := string(readFile(t, ".dockerignore")); got != ".env\n.env.*\ndocker-compose.override.yml\n" {
+			t.Errorf("Contenido inesperado: %q", got)
+		}
+		if _, err := os.Stat(".gitignore"); !os.IsNotExist(err) {
+			t.Error("No debía crearse .gitignore")
+		}
+	})
+
+	t.Run("Deshabilitado", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		save(t, compose.GitignoreOptions{Disabled: true})
+		if _, err := os.Stat(".gitignore"); !os.IsNotExist(err) {
+			t.Error("No debía crearse el archivo de ignorados")
+		}
+	})
+}
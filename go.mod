@@ -2,4 +2,7 @@ module github.com/cdvelop/compose
 
 go 1.22.0
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,144 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultNetwork es la red a la que docker compose conecta los servicios sin redes propias
+const defaultNetwork = "default"
+
+// GraphNode es un servicio del grafo
+type GraphNode struct {
+	Service     string
+	Image       string   // imagen o contexto de build
+	Networks    []string // redes a las que se conecta, "default" si no declara ninguna
+	NetworkMode string   // network_mode, en cuyo caso Networks está vacío
+}
+
+// GraphEdge es una dependencia depends_on: From depende de To
+type GraphEdge struct {
+	From string
+	To   string
+}
+
+// Graph es la topología de la configuración: servicios, dependencias y redes
+type Graph struct {
+	Nodes    []GraphNode
+	Edges    []GraphEdge
+	Networks []string // redes usadas, en orden de aparición
+}
+
+// Graph devuelve el grafo de dependencias y redes de la configuración, en el
+// orden de servicios configurado
+func (c *composeConfig) Graph() Graph {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var g Graph
+	seen := make(map[string]bool)
+	for _, s := range c.orderedServices() {
+		node := GraphNode{Service: s.name, Image: s.image, NetworkMode: s.networkMode}
+		if node.Image == "" {
+			node.Image = s.build
+		}
+		for _, n := range s.networks {
+			node.Networks = append(node.Networks, n.name)
+		}
+		if len(node.Networks) == 0 && s.networkMode == "" {
+			node.Networks = []string{defaultNetwork}
+		}
+		for _, n := range node.Networks {
+			if !seen[n] {
+				seen[n] = true
+				g.Networks = append(g.Networks, n)
+			}
+		}
+		g.Nodes = append(g.Nodes, node)
+
+		for _, dep := range s.serviceDependencies {
+			g.Edges = append(g.Edges, GraphEdge{From: s.name, To: dep})
+		}
+	}
+	return g
+}
+
+// DOT representa el grafo en formato Graphviz. Las dependencias son flechas y
+// las redes nodos punteados unidos a sus servicios
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph compose {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, n := range g.Nodes {
+		label := n.Service
+		if n.Image != "" {
+			label += "\n" + n.Image
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotID(n.Service), dotID(label))
+	}
+	for _, network := range g.Networks {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=ellipse, style=dashed];\n", dotID("network:"+network), dotID(network))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotID(e.From), dotID(e.To))
+	}
+	for _, n := range g.Nodes {
+		for _, network := range n.Networks {
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed, arrowhead=none];\n", dotID(n.Service), dotID("network:"+network))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotID entrecomilla un identificador o etiqueta de Graphviz
+func dotID(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// Mermaid representa el grafo como diagrama de flujo de Mermaid, para
+// incrustarlo en Markdown
+func (g Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	for _, n := range g.Nodes {
+		label := n.Service
+		if n.Image != "" {
+			label += "<br/>" + n.Image
+		}
+		fmt.Fprintf(&b, "  %s[%s]\n", mermaidID("svc", n.Service), mermaidLabel(label))
+	}
+	for _, network := range g.Networks {
+		fmt.Fprintf(&b, "  %s((%s))\n", mermaidID("net", network), mermaidLabel(network))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", mermaidID("svc", e.From), mermaidID("svc", e.To))
+	}
+	for _, n := range g.Nodes {
+		for _, network := range n.Networks {
+			fmt.Fprintf(&b, "  %s -.- %s\n", mermaidID("svc", n.Service), mermaidID("net", network))
+		}
+	}
+	return b.String()
+}
+
+// mermaidUnsafe son los caracteres que Mermaid no admite en identificadores
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// mermaidID genera un identificador válido; el prefijo evita choques entre
+// servicios y redes con el mismo nombre
+func mermaidID(prefix, name string) string {
+	return prefix + "_" + mermaidUnsafe.ReplaceAllString(name, "_")
+}
+
+// mermaidLabel entrecomilla una etiqueta de Mermaid
+func mermaidLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
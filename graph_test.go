@@ -0,0 +1,53 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestGraph(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16").AttachNetwork("backend")
+	api := *compose.NewService("api").SetBuild("./api").AttachNetwork("backend").AttachNetwork("frontend").DependsOn(db)
+	web := *compose.NewService("web-ui").SetImage("nginx").DependsOn(api)
+
+	config, _ := compose.NewCompose("3.8", db, api, web)
+	g := config.Graph()
+
+	if len(g.Nodes) != 3 || len(g.Edges) != 2 {
+		t.Fatalf("Grafo incorrecto: %+v", g)
+	}
+	if g.Nodes[1].Image != "./api" || strings.Join(g.Nodes[1].Networks, ",") != "backend,frontend" {
+		t.Errorf("Nodo api incorrecto: %+v", g.Nodes[1])
+	}
+	if strings.Join(g.Networks, ",") != "backend,frontend,default" {
+		t.Errorf("Redes incorrectas: %v", g.Networks)
+	}
+
+	dot := g.DOT()
+	for _, want := range []string{
+		"digraph compose {",
+		`"api" -> "db";`,
+		`"web-ui" -> "api";`,
+		`"db" [label="db\npostgres:16"];`,
+		`"api" -> "network:frontend" [style=dashed, arrowhead=none];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Falta %q en DOT:\n%s", want, dot)
+		}
+	}
+
+	mermaid := g.Mermaid()
+	for _, want := range []string{
+		"graph LR\n",
+		`svc_web_ui["web-ui<br/>nginx"]`,
+		"svc_web_ui --> svc_api",
+		`net_backend(("backend"))`,
+		"svc_db -.- net_backend",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Falta %q en Mermaid:\n%s", want, mermaid)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package compose
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	generatedHeader = "# generated by cdvelop/compose — do not edit\n"
+	checksumPrefix  = "# sha256: "
+)
+
+// ErrManuallyEdited indica que el archivo en disco fue editado a mano después
+// de generarse; usar OverwriteEdits para sobrescribirlo de todas formas
+var ErrManuallyEdited = errors.New("file was edited by hand since it was generated")
+
+// OverwriteEdits permite que Save sobrescriba un archivo editado a mano,
+// registrando un aviso en Warnings en lugar de devolver ErrManuallyEdited
+func OverwriteEdits() SaveOption {
+	return func(o *saveOptions) {
+		o.overwriteEdits = true
+	}
+}
+
+// withHeader antepone la cabecera de archivo generado con la suma del contenido
+func withHeader(body []byte) []byte {
+	sum := sha256.Sum256(body)
+
+	var b bytes.Buffer
+	b.WriteString(generatedHeader)
+	b.WriteString(checksumPrefix + hex.EncodeToString(sum[:]) + "\n")
+	b.Write(body)
+	return b.Bytes()
+}
+
+// manuallyEdited informa si data tiene la cabecera de archivo generado pero su
+// contenido ya no coincide con la suma registrada, sin tener en cuenta los
+// fines de línea. Los archivos sin cabecera no se consideran editados
+func manuallyEdited(data []byte) bool {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	rest, ok := bytes.CutPrefix(data, []byte(generatedHeader))
+	if !ok {
+		return false
+	}
+	line, body, ok := bytes.Cut(rest, []byte("\n"))
+	if !ok {
+		return true
+	}
+	sum, ok := bytes.CutPrefix(line, []byte(checksumPrefix))
+	if !ok {
+		return true
+	}
+	want := sha256.Sum256(body)
+	return string(sum) != hex.EncodeToString(want[:])
+}
+
+// checkManualEdits devuelve ErrManuallyEdited si el archivo actual fue editado
+// a mano, o lo registra como aviso si se permite sobrescribirlo
+func (c *composeConfig) checkManualEdits(path string, current []byte, overwrite bool) error {
+	if !manuallyEdited(current) {
+		return nil
+	}
+	if !overwrite {
+		return fmt.Errorf("%s: %w", path, ErrManuallyEdited)
+	}
+	c.warnings = append(c.warnings, fmt.Sprintf("%s was edited by hand, overwriting", path))
+	return nil
+}
@@ -0,0 +1,67 @@
+package compose_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestGeneratedHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	config, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.25"))
+	if err := config.SaveIfDifferent(path); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	data := readFile(t, path)
+	if !strings.HasPrefix(string(data), "# generated by cdvelop/compose — do not edit\n# sha256: ") {
+		t.Fatalf("Falta la cabecera de archivo generado:\n%s", data)
+	}
+
+	t.Run("Regenerar sin editar", func(t *testing.T) {
+		updated, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.27"))
+		if err := updated.SaveIfDifferent(path); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	})
+
+	edited := strings.Replace(string(readFile(t, path)), "nginx:1.27", "nginx:manual", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Rechaza sobrescribir ediciones manuales", func(t *testing.T) {
+		if err := config.SaveIfDifferent(path); !errors.Is(err, compose.ErrManuallyEdited) {
+			t.Errorf("Se esperaba ErrManuallyEdited: %v", err)
+		}
+		if string(readFile(t, path)) != edited {
+			t.Error("El archivo editado no debe modificarse")
+		}
+	})
+
+	t.Run("OverwriteEdits sobrescribe con aviso", func(t *testing.T) {
+		result, err := config.Save(context.Background(), compose.SaveTo(path), compose.OverwriteEdits())
+		if err != nil || !result.Written {
+			t.Fatalf("Se esperaba sobrescribir: %+v %v", result, err)
+		}
+		if len(config.Warnings()) != 1 {
+			t.Errorf("Se esperaba un aviso: %v", config.Warnings())
+		}
+	})
+
+	t.Run("Archivos sin cabecera se sobrescriben", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "docker-compose.yml")
+		if err := os.WriteFile(other, []byte("services: {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := config.SaveIfDifferent(other); err != nil {
+			t.Errorf("Error inesperado: %v", err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package compose
+
+import "strings"
+
+// SetHealthCheckConfig aplica un healthcheck completo, por ejemplo uno de los
+// predefinidos HealthCheckPostgres, HealthCheckRedis o HealthCheckHTTP
+func (s *service) SetHealthCheckConfig(hc HealthCheck) *service {
+	hc.Test = append([]string{}, hc.Test...)
+	s.healthCheck = &hc
+	return s
+}
+
+// healthCheckDefaults completa un test con intervalos razonables
+func healthCheckDefaults(test ...string) HealthCheck {
+	return HealthCheck{
+		Test:        test,
+		Interval:    "10s",
+		Timeout:     "5s",
+		Retries:     5,
+		StartPeriod: "10s",
+	}
+}
+
+// HealthCheckPostgres usa pg_isready con el usuario y la base de datos del
+// contenedor ($$ evita que docker compose interpole las variables)
+func HealthCheckPostgres() HealthCheck {
+	return healthCheckDefaults("CMD-SHELL", "pg_isready -U $${POSTGRES_USER:-postgres} -d $${POSTGRES_DB:-postgres}")
+}
+
+// HealthCheckMySQL usa mysqladmin ping con la contraseña root del contenedor
+func HealthCheckMySQL() HealthCheck {
+	return healthCheckDefaults("CMD-SHELL", "mysqladmin ping -h localhost -uroot -p$${MYSQL_ROOT_PASSWORD} --silent")
+}
+
+// HealthCheckRedis usa redis-cli ping
+func HealthCheckRedis() HealthCheck {
+	return healthCheckDefaults("CMD", "redis-cli", "ping")
+}
+
+// HealthCheckHTTP comprueba que url responda con un código 2xx/3xx usando curl -f
+func HealthCheckHTTP(url string) HealthCheck {
+	hc := healthCheckDefaults("CMD-SHELL", "curl -fsS "+shellQuote(url)+" > /dev/null || exit 1")
+	hc.Interval = "30s"
+	hc.Retries = 3
+	return hc
+}
+
+// shellQuote protege un argumento con comillas simples para sh
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
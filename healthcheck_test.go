@@ -0,0 +1,45 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHealthCheckPresets(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx").
+		SetHealthCheckConfig(compose.HealthCheckHTTP("http://localhost/it's"))
+	db := *compose.NewService("db").SetImage("postgres").
+		SetHealthCheckConfig(compose.HealthCheckPostgres())
+
+	config, _ := compose.NewCompose("3.8", web, db)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Healthcheck struct {
+				Test        []string `yaml:"test"`
+				Interval    string   `yaml:"interval"`
+				StartPeriod string   `yaml:"start_period"`
+			} `yaml:"healthcheck"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	hc := result.Services["web"].Healthcheck
+	want := `curl -fsS 'http://localhost/it'\''s' > /dev/null || exit 1`
+	if len(hc.Test) != 2 || hc.Test[0] != "CMD-SHELL" || hc.Test[1] != want {
+		t.Errorf("Test HTTP incorrecto: %q", hc.Test)
+	}
+
+	hc = result.Services["db"].Healthcheck
+	if hc.Test[1] != "pg_isready -U $${POSTGRES_USER:-postgres} -d $${POSTGRES_DB:-postgres}" || hc.StartPeriod != "10s" {
+		t.Errorf("Healthcheck postgres incorrecto: %+v", hc)
+	}
+}
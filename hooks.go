@@ -0,0 +1,95 @@
+package compose
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// HookStage es el momento de la generación o el guardado en que se ejecuta un hook
+type HookStage int
+
+const (
+	BeforeGenerate HookStage = iota // antes de validar y generar, puede modificar la configuración
+	AfterGenerate                   // con el YAML generado, antes de la cabecera; puede modificarlo
+	BeforeSave                      // con el contenido final antes de escribirlo; puede modificarlo
+	AfterSave                       // tras un Save terminado, con Written si el archivo cambió
+)
+
+func (s HookStage) String() string {
+	switch s {
+	case BeforeGenerate:
+		return "before-generate"
+	case AfterGenerate:
+		return "after-generate"
+	case BeforeSave:
+		return "before-save"
+	case AfterSave:
+		return "after-save"
+	default:
+		return fmt.Sprintf("stage(%d)", int(s))
+	}
+}
+
+// HookEvent es lo que recibe un hook. Data es nil en BeforeGenerate y Path
+// solo se informa en BeforeSave y AfterSave
+type HookEvent struct {
+	Stage   HookStage
+	Path    string
+	Data    []byte
+	Written bool
+}
+
+// Hook recibe el evento de su etapa; un error detiene la generación o el guardado
+type Hook func(e *HookEvent) error
+
+// RegisterHook ejecuta hook en la etapa indicada, en orden de registro, para
+// inyectar secciones propias, firmar los archivos o avisar a sistemas de CI sin
+// modificar el generador. Los hooks se ejecutan fuera del bloqueo de la
+// configuración, por lo que pueden llamar a sus métodos (AddService, SetExtension...).
+// Si BeforeSave modifica Data la cabecera se recalcula, de modo que el archivo
+// no se considera editado a mano
+func (c *composeConfig) RegisterHook(stage HookStage, hook Hook) *composeConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.hooks == nil {
+		c.hooks = make(map[HookStage][]Hook)
+	}
+	c.hooks[stage] = append(c.hooks[stage], hook)
+	return c
+}
+
+// runHooks ejecuta los hooks de la etapa sobre e
+func (c *composeConfig) runHooks(e *HookEvent) error {
+	c.mu.Lock()
+	hooks := append([]Hook(nil), c.hooks[e.Stage]...)
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(e); err != nil {
+			return fmt.Errorf("%s hook: %w", e.Stage, err)
+		}
+	}
+	return nil
+}
+
+// afterSaveHooks ejecuta AfterSave salvo en modo DryRun
+func (c *composeConfig) afterSaveHooks(result SaveResult, data []byte) error {
+	if result.DryRun {
+		return nil
+	}
+	return c.runHooks(&HookEvent{Stage: AfterSave, Path: result.Path, Data: data, Written: result.Written})
+}
+
+// beforeSaveHooks ejecuta BeforeSave y vuelve a calcular la cabecera si algún
+// hook cambió el contenido
+func (c *composeConfig) beforeSaveHooks(path string, data []byte) ([]byte, error) {
+	e := &HookEvent{Stage: BeforeSave, Path: path, Data: data}
+	if err := c.runHooks(e); err != nil {
+		return nil, err
+	}
+	if bytes.Equal(e.Data, data) {
+		return data, nil
+	}
+	return c.withHeader(stripGeneratedHeader(e.Data)), nil
+}
@@ -0,0 +1,90 @@
+package compose_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestHooks(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	web := *compose.NewService("web").SetImage("nginx")
+	config, _ := compose.NewCompose("3.8", web)
+
+	var stages []string
+	var notified []bool
+	config.
+		RegisterHook(compose.BeforeGenerate, func(e *compose.HookEvent) error {
+			stages = append(stages, e.Stage.String())
+			// los hooks pueden modificar la configuración
+			config.SetExtension("x-owner", "platform")
+			return nil
+		}).
+		RegisterHook(compose.AfterGenerate, func(e *compose.HookEvent) error {
+			stages = append(stages, e.Stage.String())
+			e.Data = append(e.Data, []byte("x-injected: true\n")...)
+			return nil
+		}).
+		RegisterHook(compose.BeforeSave, func(e *compose.HookEvent) error {
+			stages = append(stages, e.Stage.String())
+			sum := sha256.Sum256(e.Data)
+			e.Data = append(e.Data, []byte("# signature: "+hex.EncodeToString(sum[:8])+"\n")...)
+			return nil
+		}).
+		RegisterHook(compose.AfterSave, func(e *compose.HookEvent) error {
+			stages = append(stages, e.Stage.String())
+			notified = append(notified, e.Written)
+			if e.Path != "docker-compose.yml" {
+				t.Errorf("Ruta incorrecta: %q", e.Path)
+			}
+			return nil
+		})
+
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got := strings.Join(stages, ","); got != "before-generate,after-generate,before-save,after-save" {
+		t.Errorf("Etapas incorrectas: %s", got)
+	}
+
+	data := string(readFile(t, "docker-compose.yml"))
+	for _, want := range []string{"x-owner: platform\n", "x-injected: true\n", "# signature: "} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Falta %q en el archivo:\n%s", want, data)
+		}
+	}
+
+	// la firma forma parte de la cabecera recalculada: el segundo Save no lo
+	// considera editado a mano y no reescribe nada
+	result, err := config.Save(context.Background())
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if result.Written {
+		t.Error("No se esperaba reescribir el archivo")
+	}
+	if len(notified) != 2 || !notified[0] || notified[1] {
+		t.Errorf("AfterSave debería informar Written: %v", notified)
+	}
+
+	t.Run("Un error detiene el guardado", func(t *testing.T) {
+		errDenied := errors.New("denied")
+		config, _ := compose.NewCompose("3.8", web)
+		config.RegisterHook(compose.BeforeSave, func(*compose.HookEvent) error { return errDenied })
+
+		_, err := config.Save(context.Background(), compose.SaveTo("other.yml"))
+		if !errors.Is(err, errDenied) || !strings.Contains(err.Error(), "before-save hook") {
+			t.Errorf("Se esperaba el error del hook: %v", err)
+		}
+		if _, err := os.Stat("other.yml"); !os.IsNotExist(err) {
+			t.Error("No se esperaba escribir el archivo")
+		}
+	})
+}
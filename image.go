@@ -0,0 +1,107 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Políticas de descarga de imágenes aceptadas por SetPullPolicy
+const (
+	PullAlways  = "always"
+	PullMissing = "missing"
+	PullNever   = "never"
+	PullBuild   = "build"
+)
+
+// platformPattern valida plataformas con la forma os/arquitectura[/variante]
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+// SetPlatform fija la plataforma de la imagen, por ejemplo "linux/arm64" o "linux/amd64"
+func (s *service) SetPlatform(platform string) *service {
+	if !platformPattern.MatchString(platform) {
+		s.errors = append(s.errors, invalidField(s.name, "platform", platform))
+		return s
+	}
+	s.platform = platform
+	return s
+}
+
+// SetPullPolicy establece cuándo descargar la imagen: PullAlways, PullMissing,
+// PullNever o PullBuild
+func (s *service) SetPullPolicy(policy string) *service {
+	switch policy {
+	case PullAlways, PullMissing, PullNever, PullBuild:
+		s.pullPolicy = policy
+	default:
+		s.errors = append(s.errors, invalidField(s.name, "pull_policy", policy))
+	}
+	return s
+}
+
+// imageRefPattern sigue la gramática de referencias de docker:
+// [registro[:puerto]/]ruta[/ruta...][:tag][@algoritmo:digest]
+var imageRefPattern = regexp.MustCompile(`^` +
+	`(?:(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::\d+)?)/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+
+// digestPattern valida un digest como sha256:<hex>
+var digestPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+
+// validImageRef indica si image es una referencia válida. Las que usan
+// interpolación (${TAG}) no se pueden comprobar hasta que docker las resuelva
+func validImageRef(image string) bool {
+	return strings.Contains(image, "$") || imageRefPattern.MatchString(image)
+}
+
+// SetImageDigest fija el digest de la imagen (por ejemplo "sha256:...") para
+// despliegues reproducibles; se conserva el tag como referencia legible
+func (s *service) SetImageDigest(digest string) *service {
+	if !digestPattern.MatchString(digest) {
+		s.errors = append(s.errors, invalidField(s.name, "image_digest", digest))
+		return s
+	}
+	name, _, _ := strings.Cut(s.image, "@")
+	s.image = name + "@" + digest
+	return s
+}
+
+// PinImages consulta en el registro el digest de cada imagen sin digest y lo
+// fija en el servicio, de modo que el YAML generado sea reproducible. Las
+// imágenes con interpolación y los servicios solo con build se omiten
+func (c *composeConfig) PinImages(ctx context.Context) error {
+	c.mu.Lock()
+	images := make(map[string]string)
+	for _, s := range c.services {
+		if s.image != "" && !strings.Contains(s.image, "@") && !strings.Contains(s.image, "$") {
+			images[s.image] = ""
+		}
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, image := range sortedKeys(images) {
+		digest, err := c.resolveImage(ctx, image)
+		if err == nil && !digestPattern.MatchString(digest) {
+			err = fmt.Errorf("image %s: registry returned no digest", image)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		images[image] = digest
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.services {
+		if digest := images[c.services[i].image]; digest != "" {
+			c.services[i].image += "@" + digest
+		}
+	}
+	return errors.Join(errs...)
+}
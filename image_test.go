@@ -0,0 +1,40 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPlatformAndPullPolicy(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api").
+		SetPlatform("linux/arm64").
+		SetPullPolicy(compose.PullAlways)
+
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Platform   string `yaml:"platform"`
+			PullPolicy string `yaml:"pull_policy"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	if got := result.Services["api"]; got.Platform != "linux/arm64" || got.PullPolicy != "always" {
+		t.Errorf("platform o pull_policy incorrectos: %+v", got)
+	}
+
+	bad := *compose.NewService("bad").SetImage("x").SetPlatform("arm64").SetPullPolicy("sometimes")
+	config, _ = compose.NewCompose("3.8", bad)
+	if err := config.Validate(); err == nil {
+		t.Error("Se esperaba un error por platform y pull_policy inválidos")
+	}
+}
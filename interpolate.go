@@ -0,0 +1,132 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Var devuelve la referencia ${name}, que docker compose resuelve al arrancar
+func Var(name string) string {
+	return "${" + name + "}"
+}
+
+// VarDefault devuelve ${name:-fallback}: fallback si name no está definida o está vacía
+func VarDefault(name, fallback string) string {
+	return "${" + name + ":-" + fallback + "}"
+}
+
+// VarRequired devuelve ${name:?message}: docker compose falla con message si
+// name no está definida o está vacía
+func VarRequired(name, message string) string {
+	return "${" + name + ":?" + message + "}"
+}
+
+// Literal escapa cada $ como $$ para que docker compose no interpole el valor
+func Literal(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+// Interpolate resuelve las referencias ${VAR}, $VAR, ${VAR:-x}, ${VAR-x},
+// ${VAR:?msg}, ${VAR?msg}, ${VAR:+x} y ${VAR+x} de s igual que docker compose,
+// usando el .env (envPath opcional, por defecto ".env") y el entorno, que tiene
+// prioridad. $$ se convierte en $. Útil para previsualizar valores
+func Interpolate(s string, envPath ...string) (string, error) {
+	envVars, err := osFiles.readEnvFile(envPathFrom(envPath))
+	if err != nil {
+		return "", err
+	}
+	return interpolateEnv(s, envVars)
+}
+
+// interpolateEnv resuelve las referencias de s con envVars y el entorno, que
+// tiene prioridad
+func interpolateEnv(s string, envVars map[string]string) (string, error) {
+	lookup := func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := envVars[name]
+		return value, ok
+	}
+
+	var errs []error
+	out := envReferencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+
+		match := envReferencePattern.FindStringSubmatch(ref)
+		name, modifier := match[1], match[2]
+		if name == "" {
+			name = match[3]
+		}
+		value, set := lookup(name)
+
+		// con ":" el valor vacío cuenta como no definido
+		op, arg := "", ""
+		if modifier != "" {
+			colon := strings.HasPrefix(modifier, ":")
+			modifier = strings.TrimPrefix(modifier, ":")
+			op, arg = modifier[:1], modifier[1:]
+			if colon && value == "" {
+				set = false
+			}
+		}
+
+		switch op {
+		case "-":
+			if !set {
+				return arg
+			}
+		case "+":
+			if set {
+				return arg
+			}
+			return ""
+		case "?":
+			if !set {
+				errs = append(errs, fmt.Errorf("required variable %s is missing a value: %s", name, arg))
+				return ""
+			}
+		}
+		return value
+	})
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return out, nil
+}
+
+// Interpolated genera el YAML y resuelve sus referencias con el .env del
+// proyecto, leído con el sistema de archivos y el cifrado de la configuración,
+// mostrando lo que docker compose usará realmente
+func (c *composeConfig) Interpolated() ([]byte, error) {
+	data, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.interpolate(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// projectEnv lee las variables del .env junto al archivo compose, el que usa
+// docker compose para interpolar
+func (c *composeConfig) projectEnv() (map[string]string, error) {
+	return c.files.readEnvFile(c.besideComposeFile(defaultEnvFile))
+}
+
+// interpolate resuelve las referencias de s con el .env del proyecto
+func (c *composeConfig) interpolate(s string) (string, error) {
+	envVars, err := c.projectEnv()
+	if err != nil {
+		return "", err
+	}
+	return interpolateEnv(s, envVars)
+}
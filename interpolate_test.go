@@ -0,0 +1,63 @@
+package compose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestInterpolate(t *testing.T) {
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath, []byte("HOST=db\nEMPTY=\nPORT=5432\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORT", "6543")
+
+	cases := []struct{ in, want string }{
+		{compose.Var("HOST"), "db"},
+		{"$HOST:${PORT}", "db:6543"},
+		{compose.VarDefault("MISSING", "x"), "x"},
+		{compose.VarDefault("EMPTY", "x"), "x"},
+		{"${EMPTY-x}", ""},
+		{"${HOST:+set}${MISSING+set}", "set"},
+		{compose.Literal("pa$$word $HOST"), "pa$$word $HOST"},
+	}
+	for _, c := range cases {
+		got, err := compose.Interpolate(c.in, envPath)
+		if err != nil {
+			t.Errorf("%s: error inesperado: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: se esperaba %q, se obtuvo %q", c.in, c.want, got)
+		}
+	}
+
+	if _, err := compose.Interpolate(compose.VarRequired("MISSING", "set it"), envPath); err == nil {
+		t.Error("Se esperaba un error por variable requerida")
+	}
+}
+
+func TestInterpolatedReadsProjectEnv(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	mem := compose.NewMemFS()
+	if err := mem.WriteFile(".env", []byte("API_TAG=2.1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	api := *compose.NewService("api").SetImage("api:" + compose.Var("API_TAG"))
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetFS(mem)
+
+	data, err := config.Interpolated()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), `image: "api:2.1"`) {
+		t.Errorf("Se esperaba la imagen resuelta con el .env del sistema de archivos configurado:\n%s", data)
+	}
+}
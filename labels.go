@@ -0,0 +1,28 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetLabel añade una etiqueta (labels) al contenedor del servicio, por ejemplo
+// para herramientas como Traefik o Watchtower que se configuran con etiquetas
+func (s *service) SetLabel(key, value string) *service {
+	if key == "" {
+		s.errors = append(s.errors, invalidField(s.name, "labels", key))
+		return s
+	}
+	if s.labels == nil {
+		s.labels = make(map[string]string)
+	}
+	s.labels[key] = value
+	return s
+}
+
+// writeLabels escribe las etiquetas del servicio ordenadas por clave
+func writeLabels(b *strings.Builder, labels map[string]string) {
+	b.WriteString("    labels:\n")
+	for _, key := range sortedKeys(labels) {
+		fmt.Fprintf(b, "      %s: %s\n", yamlQuote(key), yamlQuote(labels[key]))
+	}
+}
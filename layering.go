@@ -0,0 +1,214 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appendedLists son las listas de servicio que docker compose combina al apilar
+// archivos (añade los elementos nuevos) en lugar de reemplazarlas
+var appendedLists = map[string]bool{
+	"ports": true, "expose": true, "volumes": true, "devices": true, "dns": true,
+	"dns_search": true, "tmpfs": true, "cap_add": true, "cap_drop": true,
+	"extra_hosts": true, "security_opt": true, "configs": true, "depends_on": true,
+}
+
+// layering apila una configuración base y overrides completos, guardando cada
+// override solo con lo que cambia respecto a la capa anterior
+type layering struct {
+	base      *composeConfig
+	overrides []*composeConfig
+}
+
+// NewLayering crea las capas: base se guarda completa en su archivo y cada
+// override, que describe la configuración completa deseada, se guarda como
+// docker-compose.override.yml (y .override.2.yml, ...) con solo las diferencias
+func NewLayering(base *composeConfig, overrides ...*composeConfig) *layering {
+	return &layering{base: base, overrides: overrides}
+}
+
+// Files devuelve los archivos en el orden en que se pasan con -f
+func (l *layering) Files() []string {
+	files := []string{l.base.composeFile()}
+	for i := range l.overrides {
+		files = append(files, l.overridePath(i))
+	}
+	return files
+}
+
+// overridePath devuelve la ruta del override i junto al archivo base
+func (l *layering) overridePath(i int) string {
+	base := l.base.composeFile()
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext) + ".override"
+	if i > 0 {
+		name += fmt.Sprintf(".%d", i+1)
+	}
+	return name + ext
+}
+
+// OverrideBytes genera el override i con las claves que difieren de la capa anterior
+func (l *layering) OverrideBytes(i int) ([]byte, error) {
+	prev := l.base
+	if i > 0 {
+		prev = l.overrides[i-1]
+	}
+
+	before, err := prev.body()
+	if err != nil {
+		return nil, err
+	}
+	after, err := l.overrides[i].body()
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeDoc, afterDoc yaml.Node
+	if err := yaml.Unmarshal(before, &beforeDoc); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(after, &afterDoc); err != nil {
+		return nil, err
+	}
+
+	diff := diffNodes(beforeDoc.Content[0], afterDoc.Content[0], "")
+	if diff == nil {
+		diff = &yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(diff); err != nil {
+		return nil, err
+	}
+	return l.base.withHeader(buf.Bytes()), nil
+}
+
+// Save guarda la base y los overrides que hayan cambiado junto con las
+// variables de sus servicios, y hace que los comandos docker compose de la
+// base usen todas las capas
+func (l *layering) Save(ctx context.Context) ([]string, error) {
+	if _, err := l.base.Save(ctx, SaveTo(l.base.composeFile())); err != nil {
+		return nil, err
+	}
+
+	for i := range l.overrides {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := l.overrides[i].resolveDeferredEnv(); err != nil {
+			return nil, err
+		}
+
+		data, err := l.OverrideBytes(i)
+		if err != nil {
+			return nil, err
+		}
+
+		path := l.overridePath(i)
+		if current, err := l.base.files.readFile(path); err != nil || !bytes.Equal(current, data) {
+			if err := l.base.files.writeFile(path, data, defaultComposeFileMode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	files := l.Files()
+	l.base.layerFiles = files[1:]
+	return files, nil
+}
+
+// diffNodes devuelve lo que hay que apilar sobre before para obtener after,
+// o nil si son iguales. Los mapas se combinan clave a clave, las claves
+// eliminadas se marcan con !reset, las listas de appendedLists solo llevan los
+// elementos nuevos (o !override si se quitó alguno) y el resto se reemplaza
+func diffNodes(before, after *yaml.Node, key string) *yaml.Node {
+	if nodesEqual(before, after) {
+		return nil
+	}
+	if before.Kind != after.Kind {
+		return after
+	}
+
+	switch after.Kind {
+	case yaml.MappingNode:
+		out := &yaml.Node{Kind: yaml.MappingNode}
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(after.Content); i += 2 {
+			k, v := after.Content[i], after.Content[i+1]
+			seen[k.Value] = true
+			if prev := mappingValue(before, k.Value); prev == nil {
+				out.Content = append(out.Content, k, v)
+			} else if d := diffNodes(prev, v, k.Value); d != nil {
+				out.Content = append(out.Content, k, d)
+			}
+		}
+		for i := 0; i+1 < len(before.Content); i += 2 {
+			if k := before.Content[i]; !seen[k.Value] {
+				reset := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!reset", Value: "null"}
+				out.Content = append(out.Content, k, reset)
+			}
+		}
+		return out
+
+	case yaml.SequenceNode:
+		if !appendedLists[key] {
+			return after
+		}
+		var added []*yaml.Node
+		for _, item := range after.Content {
+			if !containsNode(before.Content, item) {
+				added = append(added, item)
+			}
+		}
+		for _, item := range before.Content {
+			if !containsNode(after.Content, item) {
+				replaced := *after
+				replaced.Tag = "!override"
+				return &replaced
+			}
+		}
+		return &yaml.Node{Kind: yaml.SequenceNode, Content: added}
+	}
+	return after
+}
+
+// mappingValue devuelve el valor de key en un mapa YAML, o nil
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// containsNode indica si nodes contiene un nodo igual a n
+func containsNode(nodes []*yaml.Node, n *yaml.Node) bool {
+	for _, other := range nodes {
+		if nodesEqual(other, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodesEqual compara dos nodos por su contenido, sin estilos ni comentarios
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package compose_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestLayering(t *testing.T) {
+	dir := t.TempDir()
+
+	db := *compose.NewService("db").SetImage("postgres:16")
+	api := compose.NewService("api").
+		SetImage("myapi:1").
+		AddPort("8080", "8080").
+		SetCommand("serve").
+		SetRestartPolicy(compose.RestartAlways).
+		DependsOn(db)
+	base, _ := compose.NewCompose("3.8", db, *api)
+
+	devAPI := *compose.NewService("api").
+		SetImage("myapi:dev").
+		AddPort("8080", "8080").
+		AddPort("9229", "9229").
+		SetCommand("serve", "--debug").
+		DependsOn(db)
+	dev, _ := compose.NewCompose("3.8", db, devAPI)
+
+	layers := compose.NewLayering(base, dev)
+	base.SetProjectName("shop")
+	dev.SetProjectName("shop")
+	if _, err := base.Save(context.Background(), compose.SaveTo(filepath.Join(dir, "docker-compose.yml"))); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	files, err := layers.Save(context.Background())
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	want := []string{filepath.Join(dir, "docker-compose.yml"), filepath.Join(dir, "docker-compose.override.yml")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("Archivos incorrectos: %v", files)
+	}
+
+	override := string(readFile(t, files[1]))
+	expected := `services:
+  api:
+    image: "myapi:dev"
+    ports:
+      - "9229:9229"
+    command:
+      - "serve"
+      - "--debug"
+    restart: !reset null
+`
+	if !strings.HasSuffix(override, expected) {
+		t.Errorf("Override incorrecto:\nEsperado:\n%s\nObtenido:\n%s", expected, override)
+	}
+	if strings.Contains(override, "version") || strings.Contains(override, "db:") {
+		t.Errorf("El override solo debe contener diferencias:\n%s", override)
+	}
+
+	log := fakeDocker(t, "")
+	if err := base.Up(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	call := "compose -f " + files[0] + " -f " + files[1] + " -p shop up -d"
+	if calls := dockerCalls(t, log); calls[0] != call {
+		t.Errorf("Comando incorrecto:\nEsperado: %q\nObtenido: %q", call, calls[0])
+	}
+}
@@ -0,0 +1,180 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// deferredEnv es una variable que el servicio escribe en su archivo de
+// variables al guardar la configuración, nunca al construirse
+type deferredEnv struct {
+	key      string
+	group    string
+	file     string  // archivo de variables; vacío para el .env
+	value    *string // valor conocido al construir el servicio
+	fallback *string // valor si la variable no está en el entorno
+	secret   int     // longitud del secreto a generar si el archivo no lo tiene
+}
+
+// SetLazyEnv hace que las siguientes llamadas a AddEnvironment sin valor no
+// busquen la variable de inmediato sino al generar el YAML (Bytes, Save...),
+// permitiendo construir la configuración antes de que exista el entorno. El
+// valor se escribe en el .env solo al guardar
+func (s *service) SetLazyEnv(lazy bool) *service {
+	s.lazyEnv = lazy
+	return s
+}
+
+// EnvOrDefault añade la variable como ${key}; al guardar se toma su valor del
+// entorno o fallback si no está definida y se escribe en el .env
+func (s *service) EnvOrDefault(key, fallback string) *service {
+	return s.deferEnv(key, &fallback)
+}
+
+// deferEnv registra la variable para resolverla al generar
+func (s *service) deferEnv(key string, fallback *string) *service {
+	s.storeEnv(deferredEnv{key: key, fallback: fallback})
+	s.environment[key] = fmt.Sprintf("${%s}", key)
+	return s
+}
+
+// storeEnv registra una variable a escribir al guardar, en la sección del
+// .env del servicio
+func (s *service) storeEnv(d deferredEnv) {
+	if err := validateEnvKey(d.key); err != nil {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "environment", Value: d.key, Err: err})
+		return
+	}
+	d.group = s.envGroup
+	s.deferredEnv = append(s.deferredEnv, d)
+}
+
+// deferredEnvValues resuelve las variables diferidas de todos los servicios en
+// el orden en que se añadieron, informando todas las que falten. Las de valor
+// vacío se omiten y los secretos quedan sin valor hasta escribirlos. No escribe nada
+func (c *composeConfig) deferredEnvValues() ([]deferredEnv, error) {
+	var resolved []deferredEnv
+	var missing []error
+
+	for _, s := range c.services {
+		for _, d := range s.deferredEnv {
+			if d.value == nil && d.secret == 0 {
+				value, ok := os.LookupEnv(d.key)
+				if !ok && d.fallback != nil {
+					value, ok = *d.fallback, true
+				}
+				if !ok {
+					missing = append(missing, &ValidationError{Service: s.name, Field: "environment", Value: d.key, Err: ErrEnvVarNotFound})
+					continue
+				}
+				d.value = &value
+			}
+			if d.value != nil && *d.value == "" {
+				continue
+			}
+			resolved = append(resolved, d)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+	return resolved, nil
+}
+
+// deferredEnvKeys devuelve las variables diferidas que tendrán valor en el
+// .env tras guardar, para no informarlas como no definidas
+func (c *composeConfig) deferredEnvKeys() (map[string]bool, error) {
+	resolved, err := c.deferredEnvValues()
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool)
+	for _, d := range resolved {
+		if d.file == "" {
+			keys[d.key] = true
+		}
+	}
+	return keys, nil
+}
+
+// resolveDeferredEnv escribe las variables de los servicios en sus archivos
+// junto al archivo compose, cada uno leído y escrito una sola vez. Solo se llama al guardar: generar el
+// YAML con Bytes no toca el sistema de archivos
+func (c *composeConfig) resolveDeferredEnv() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resolved, err := c.deferredEnvValues()
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	byFile := make(map[string][]deferredEnv)
+	for _, d := range resolved {
+		file := d.file
+		if file == "" {
+			file = defaultEnvFile
+		}
+		file = c.besideComposeFile(file)
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+		byFile[file] = append(byFile[file], d)
+	}
+
+	for _, file := range files {
+		if err := c.files.writeDeferredEnv(file, byFile[file]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDeferredEnv aplica las variables sobre el archivo path y lo reescribe
+// solo si cambió. Los secretos que ya tienen valor en el archivo se conservan
+func (f *fileSettings) writeDeferredEnv(path string, vars []deferredEnv) error {
+	envWriteMu.Lock()
+	defer envWriteMu.Unlock()
+
+	data, err := f.readEnvData(path)
+	if err != nil {
+		return err
+	}
+	doc := parseEnvDocument(data)
+
+	for _, d := range vars {
+		if d.value != nil {
+			doc.set(d.group, d.key, *d.value)
+			continue
+		}
+		if doc.vars()[d.key] != "" {
+			continue
+		}
+		secret, err := RandomSecret(d.secret, CharsetAlphanumeric)
+		if err != nil {
+			return err
+		}
+		doc.set(d.group, d.key, secret)
+	}
+
+	if data != nil && string(data) == doc.String() {
+		return nil
+	}
+	if err := f.writeEnvFile(path, doc); err != nil {
+		return err
+	}
+
+	// Los archivos cifrados se pueden versionar, no hay texto plano que ignorar
+	if f.cipher != nil {
+		return nil
+	}
+	gitignorePath := f.gitignore.Path
+	if gitignorePath == "" {
+		gitignorePath = filepath.Join(filepath.Dir(path), ".gitignore")
+	}
+	return f.handleGitignore(gitignorePath, path)
+}
@@ -0,0 +1,59 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestDeferredEnv(t *testing.T) {
+	work := t.TempDir()
+	chdir(t, work)
+
+	// la configuración se construye antes de que existan las variables
+	api := *compose.NewService("api").
+		SetImage("myapi").
+		SetLazyEnv(true).
+		AddEnvironment("API_TOKEN").
+		EnvOrDefault("LOG_LEVEL", "info")
+	config, _ := compose.NewCompose("3.8", api)
+
+	_, err := config.Bytes()
+	if err == nil || !strings.Contains(err.Error(), "API_TOKEN") {
+		t.Fatalf("Se esperaba un error por API_TOKEN: %v", err)
+	}
+
+	t.Setenv("API_TOKEN", "tok")
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), `"API_TOKEN": "${API_TOKEN}"`) || !strings.Contains(string(data), `"LOG_LEVEL": "${LOG_LEVEL}"`) {
+		t.Errorf("Variables incorrectas:\n%s", data)
+	}
+
+	if len(config.Warnings()) > 0 {
+		t.Errorf("Las variables diferidas no son referencias sin definir: %v", config.Warnings())
+	}
+
+	// Bytes no toca el sistema de archivos; el .env se escribe al guardar
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Fatalf("Bytes no debe escribir el .env: %v", err)
+	}
+	if _, err := config.Save(context.Background(), compose.DryRun()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Fatalf("DryRun no debe escribir el .env: %v", err)
+	}
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if got := string(readFile(t, ".env")); got != "API_TOKEN=tok\nLOG_LEVEL=info\n" {
+		t.Errorf(".env incorrecto: %q", got)
+	}
+}
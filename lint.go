@@ -0,0 +1,113 @@
+package compose
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultMaxLineLength coincide con la regla line-length por defecto de yamllint
+const defaultMaxLineLength = 80
+
+// truthyPattern detecta valores booleanos sin comillas en formas distintas a true/false
+var truthyPattern = regexp.MustCompile(`^(\s*(?:- |[^"'#:]+: ))(yes|Yes|YES|no|No|NO|on|On|ON|off|Off|OFF|True|TRUE|False|FALSE)\s*$`)
+
+// quotedValuePattern detecta líneas cuyo valor es un escalar entre comillas dobles
+var quotedValuePattern = regexp.MustCompile(`^(\s*)(- |[^"'#]+: |"[^"]*": )"`)
+
+// blockHeaderPattern detecta el inicio de un bloque literal (|, |-, |+, |2...)
+var blockHeaderPattern = regexp.MustCompile(`(^|: |- )\|[0-9]?[-+]?$`)
+
+// indentation devuelve la cantidad de espacios al inicio de la línea
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// SetMaxLineLength establece el largo máximo de línea del YAML generado.
+// Los valores entre comillas más largos se parten en varias líneas sin alterar
+// su contenido. Con 0 se desactiva; por defecto es 80 como en yamllint
+func (c *composeConfig) SetMaxLineLength(n int) *composeConfig {
+	c.maxLineLength = &n
+	return c
+}
+
+// lintYAML ajusta el YAML generado a las reglas habituales de yamllint:
+// sin espacios al final de línea, booleanos true/false, largo de línea y
+// un único salto de línea final
+func (c *composeConfig) lintYAML(data []byte) []byte {
+	maxLen := defaultMaxLineLength
+	if c.maxLineLength != nil {
+		maxLen = *c.maxLineLength
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	var out []string
+	blockIndent := -1
+	for _, line := range lines {
+		// el contenido de los bloques literales se copia tal cual
+		if blockIndent >= 0 {
+			if line == "" || indentation(line) > blockIndent {
+				out = append(out, line)
+				continue
+			}
+			blockIndent = -1
+		}
+		if blockHeaderPattern.MatchString(line) {
+			blockIndent = indentation(line)
+		}
+
+		line = strings.TrimRight(line, " \t")
+
+		if m := truthyPattern.FindStringSubmatch(line); m != nil {
+			switch strings.ToLower(m[2]) {
+			case "yes", "on", "true":
+				line = m[1] + "true"
+			default:
+				line = m[1] + "false"
+			}
+		}
+
+		if maxLen > 0 && len(line) > maxLen {
+			out = append(out, foldQuotedLine(line, maxLen)...)
+			continue
+		}
+		out = append(out, line)
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// foldQuotedLine parte una línea cuyo valor es un escalar entre comillas dobles
+// usando saltos escapados ("\" al final de línea), que YAML descarta junto con
+// la sangría de la línea siguiente. Solo se corta después de un espacio para
+// que la continuación no empiece con espacios. Las líneas que no se pueden
+// partir se devuelven sin cambios
+func foldQuotedLine(line string, maxLen int) []string {
+	m := quotedValuePattern.FindStringSubmatch(line)
+	if m == nil || !strings.HasSuffix(line, `"`) {
+		return []string{line}
+	}
+
+	indent := m[1] + "    "
+	start := len(m[0])
+
+	var out []string
+	current := line
+	for len(current) > maxLen {
+		// buscar el último espacio que deje la línea (con "\") dentro del límite
+		cut := -1
+		for i := maxLen - 2; i > start; i-- {
+			if current[i] == ' ' && current[i+1] != ' ' && current[i+1] != '"' {
+				cut = i + 1
+				break
+			}
+		}
+		if cut < 0 {
+			break
+		}
+		out = append(out, current[:cut]+`\`)
+		current = indent + current[cut:]
+		start = len(indent)
+	}
+	return append(out, current)
+}
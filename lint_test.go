@@ -0,0 +1,65 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLintedOutput(t *testing.T) {
+	script := `for i in 1 2 3; do echo "processing batch $$i of the nightly import job"; sleep 5; done && echo done`
+	content := "  - \"this line looks like yaml but belongs to a config file and must stay untouched\"  \n"
+
+	worker := *compose.NewService("worker").
+		SetImage("alpine").
+		SetShellCommand(script).
+		AddConfig(compose.InlineConfig{Name: "list", Content: content})
+
+	config, _ := compose.NewCompose("3.8", worker)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if !strings.HasSuffix(string(data), "\n") || strings.HasSuffix(string(data), "\n\n") {
+		t.Error("Se esperaba un único salto de línea final")
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasSuffix(line, "content: |2") {
+			inBlock = true
+			continue
+		}
+		if inBlock {
+			continue
+		}
+		if len(line) > 80 {
+			t.Errorf("Línea demasiado larga (%d): %s", len(line), line)
+		}
+		if strings.TrimRight(line, " ") != line {
+			t.Errorf("Espacios al final de línea: %q", line)
+		}
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Command []string `yaml:"command"`
+		} `yaml:"services"`
+		Configs map[string]struct {
+			Content string `yaml:"content"`
+		} `yaml:"configs"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+
+	if got := result.Services["worker"].Command[2]; got != script {
+		t.Errorf("El comando cambió al partir la línea:\nEsperado: %q\nObtenido: %q", script, got)
+	}
+	if got := result.Configs["list"].Content; got != content {
+		t.Errorf("El contenido del bloque cambió:\nEsperado: %q\nObtenido: %q", content, got)
+	}
+}
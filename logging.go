@@ -0,0 +1,47 @@
+package compose
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SetLogger hace que la configuración emita eventos estructurados al validar,
+// generar, guardar archivos y ejecutar docker compose, útil cuando el paquete
+// forma parte de una herramienta de aprovisionamiento mayor. Sin logger no se
+// registra nada
+func (c *composeConfig) SetLogger(logger *slog.Logger) *composeConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger = logger
+	return c
+}
+
+// log registra un evento si hay logger configurado
+func (c *composeConfig) log(level slog.Level, msg string, args ...any) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.Log(context.Background(), level, msg, args...)
+}
+
+// logSave registra el resultado de escribir un archivo
+func (c *composeConfig) logSave(path string, status SaveStatus) {
+	if status == SaveUnchanged {
+		c.log(slog.LevelDebug, "file unchanged", "path", path)
+		return
+	}
+	c.log(slog.LevelInfo, "file written", "path", path, "status", status.String())
+}
+
+// logCommand registra una ejecución de docker iniciada en start
+func (c *composeConfig) logCommand(args []string, start time.Time, err error) {
+	attrs := []any{"args", strings.Join(args, " "), "duration", time.Since(start)}
+	if err != nil {
+		c.log(slog.LevelWarn, "docker command failed", append(attrs, "error", err)...)
+		return
+	}
+	c.log(slog.LevelDebug, "docker command", attrs...)
+}
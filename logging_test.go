@@ -0,0 +1,90 @@
+package compose_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestSetLogger(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	web := *compose.NewService("web").SetImage("nginx")
+	config, _ := compose.NewCompose("3.8", web)
+	config.SetLogger(logger)
+
+	events := func() []map[string]any {
+		t.Helper()
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var e map[string]any
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("Error parseando evento %q: %v", line, err)
+			}
+			out = append(out, e)
+		}
+		buf.Reset()
+		return out
+	}
+	messages := func(events []map[string]any) string {
+		var msgs []string
+		for _, e := range events {
+			msgs = append(msgs, e["msg"].(string))
+		}
+		return strings.Join(msgs, ",")
+	}
+
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	got := events()
+	if msgs := messages(got); msgs != "service validated,compose generated,file written" {
+		t.Errorf("Eventos incorrectos: %s", msgs)
+	}
+	if last := got[len(got)-1]; last["path"] != "docker-compose.yml" || last["status"] != "created" {
+		t.Errorf("Evento de escritura incorrecto: %v", last)
+	}
+
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if msgs := messages(events()); !strings.HasSuffix(msgs, "file unchanged") {
+		t.Errorf("Se esperaba file unchanged: %s", msgs)
+	}
+
+	t.Run("Comandos docker", func(t *testing.T) {
+		fakeDocker(t, `echo "boom" >&2; exit 1`)
+
+		if _, err := config.Status(context.Background()); err == nil {
+			t.Fatal("Se esperaba un error de docker")
+		}
+		got := events()
+		if len(got) != 1 || got[0]["msg"] != "docker command failed" || got[0]["level"] != "WARN" {
+			t.Fatalf("Evento incorrecto: %v", got)
+		}
+		if args := got[0]["args"].(string); args != "compose -f docker-compose.yml ps --all --format json" {
+			t.Errorf("Argumentos incorrectos: %q", args)
+		}
+	})
+
+	t.Run("Validación fallida", func(t *testing.T) {
+		bad := *compose.NewService("bad")
+		config, _ := compose.NewCompose("3.8", bad)
+		config.SetLogger(logger)
+
+		if err := config.Validate(); err == nil {
+			t.Fatal("Se esperaba un error de validación")
+		}
+		if got := events(); len(got) != 1 || got[0]["msg"] != "validation failed" || got[0]["errors"] != float64(1) {
+			t.Errorf("Evento incorrecto: %v", got)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package compose
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Logs escribe en w la salida de "docker compose logs" de los servicios
+// indicados (todos si no se indica ninguno), con los mismos archivos, entorno y
+// proyecto que el resto de comandos. Con follow sigue transmitiendo hasta que
+// se cancela el contexto, en cuyo caso devuelve ctx.Err()
+func (c *composeConfig) Logs(ctx context.Context, w io.Writer, follow bool, services ...string) error {
+	args := []string{"logs", "--no-color"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	args = c.composeArgs(append(args, services...)...)
+	start := time.Now()
+	err := streamDocker(ctx, w, args...)
+	c.logCommand(args, start, err)
+	return err
+}
@@ -0,0 +1,50 @@
+package compose_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestLogs(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	config, _ := compose.NewCompose("3.8", db)
+	config.SetProjectName("shop")
+
+	t.Run("Transmite la salida", func(t *testing.T) {
+		log := fakeDocker(t, `echo "db-1  | ready to accept connections"`)
+
+		var out bytes.Buffer
+		if err := config.Logs(context.Background(), &out, false, "db"); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if out.String() != "db-1  | ready to accept connections\n" {
+			t.Errorf("Salida incorrecta: %q", out.String())
+		}
+
+		calls := dockerCalls(t, log)
+		want := "compose -f docker-compose.yml -p shop logs --no-color db"
+		if calls[0] != want {
+			t.Errorf("Comando incorrecto:\nEsperado: %q\nObtenido: %q", want, calls[0])
+		}
+	})
+
+	t.Run("Follow termina al cancelar el contexto", func(t *testing.T) {
+		log := fakeDocker(t, `exec sleep 5`)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := config.Logs(ctx, &bytes.Buffer{}, true)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Se esperaba DeadlineExceeded, se obtuvo %v", err)
+		}
+		if calls := dockerCalls(t, log); calls[0] != "compose -f docker-compose.yml -p shop logs --no-color --follow" {
+			t.Errorf("Comando incorrecto: %q", calls[0])
+		}
+	})
+}
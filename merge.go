@@ -0,0 +1,327 @@
+package compose
+
+import (
+	"maps"
+	"sync"
+)
+
+// Merge combina base con cada overlay siguiendo las reglas de docker compose al
+// apilar archivos: los servicios se emparejan por nombre, los mapas
+// (environment, sysctls, redes...) se combinan clave a clave, las listas como
+// ports, volumes o depends_on añaden los elementos nuevos (volumes y configs
+// reemplazan los de igual destino) y los valores simples y command/healthcheck
+// se reemplazan. No modifica base ni los overlays
+func Merge(base *composeConfig, overlays ...*composeConfig) *composeConfig {
+	out := base.clone()
+	out.warnings = nil
+
+	for _, overlay := range overlays {
+		out.mergeConfig(overlay)
+	}
+	return out
+}
+
+// clone copia la configuración sin compartir con el original servicios, mapas,
+// listas ni el lock, para que modificar la copia no lo altere
+func (c *composeConfig) clone() *composeConfig {
+	out := *c
+	out.services = cloneServices(c.services)
+	out.mu = new(sync.Mutex)
+	out.ephemeralPorts = maps.Clone(c.ephemeralPorts)
+	out.networks = maps.Clone(c.networks)
+	out.volumes = maps.Clone(c.volumes)
+	out.registryAuth = maps.Clone(c.registryAuth)
+	out.features = maps.Clone(c.features)
+	out.includes = append([]string(nil), c.includes...)
+	out.extensions = cloneRawFields(c.extensions)
+	out.layerFiles = append([]string(nil), c.layerFiles...)
+	out.warnings = append([]string(nil), c.warnings...)
+	if c.hooks != nil {
+		out.hooks = make(map[HookStage][]Hook, len(c.hooks))
+		for stage, hooks := range c.hooks {
+			out.hooks[stage] = append([]Hook(nil), hooks...)
+		}
+	}
+	return &out
+}
+
+// mergeConfig aplica un overlay sobre la configuración
+func (c *composeConfig) mergeConfig(overlay *composeConfig) {
+	c.version = mergeString(c.version, overlay.version)
+	c.projectName = mergeString(c.projectName, overlay.projectName)
+	c.includes = appendUnique(c.includes, overlay.includes)
+
+	for name, network := range overlay.networks {
+		if c.networks == nil {
+			c.networks = make(map[string]NetworkConfig)
+		}
+		c.networks[name] = network
+	}
+	for name, volume := range overlay.volumes {
+		if c.volumes == nil {
+			c.volumes = make(map[string]VolumeConfig)
+		}
+		c.volumes[name] = volume
+	}
+	for f, enabled := range overlay.features {
+		if c.features == nil {
+			c.features = make(map[Feature]bool)
+		}
+		c.features[f] = c.features[f] || enabled
+	}
+	for _, f := range overlay.extensions {
+		c.extensions = setRawField(c.extensions, f.key, cloneRawValue(f.value))
+	}
+
+	for _, s := range overlay.services {
+		if existing := c.serviceIndex(s.name); existing >= 0 {
+			c.services[existing].merge(s)
+			continue
+		}
+		c.services = append(c.services, cloneService(s))
+	}
+}
+
+// serviceIndex devuelve la posición del servicio name, o -1
+func (c *composeConfig) serviceIndex(name string) int {
+	for i, s := range c.services {
+		if s.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// merge aplica sobre el servicio los valores de overlay
+func (s *service) merge(overlay service) {
+	s.image = mergeString(s.image, overlay.image)
+	s.build = mergeString(s.build, overlay.build)
+	s.platform = mergeString(s.platform, overlay.platform)
+	s.pullPolicy = mergeString(s.pullPolicy, overlay.pullPolicy)
+	s.containerName = mergeString(s.containerName, overlay.containerName)
+	s.networkMode = mergeString(s.networkMode, overlay.networkMode)
+	s.restartPolicy = mergeString(s.restartPolicy, overlay.restartPolicy)
+	s.shmSize = mergeString(s.shmSize, overlay.shmSize)
+	s.envGroup = mergeString(s.envGroup, overlay.envGroup)
+	s.proxyHost = mergeString(s.proxyHost, overlay.proxyHost)
+	s.isolation = mergeString(s.isolation, overlay.isolation)
+	s.privileged = s.privileged || overlay.privileged
+	s.readOnly = s.readOnly || overlay.readOnly
+
+	if len(overlay.command) > 0 {
+		s.command = append([]string(nil), overlay.command...)
+		s.commandString = overlay.commandString
+	}
+	if overlay.credentialSpec != nil {
+		spec := *overlay.credentialSpec
+		s.credentialSpec = &spec
+	}
+	if overlay.healthCheck != nil {
+		hc := *overlay.healthCheck
+		s.healthCheck = &hc
+	}
+	if overlay.scale != nil {
+		n := *overlay.scale
+		s.scale = &n
+	}
+	if len(overlay.deviceReservations) > 0 {
+		s.deviceReservations = append([]DeviceReservation(nil), overlay.deviceReservations...)
+	}
+	if len(overlay.developWatch) > 0 {
+		s.developWatch = cloneWatchRules(overlay.developWatch)
+	}
+
+	for k, v := range overlay.environment {
+		s.environment[k] = v
+	}
+	for k, v := range overlay.labels {
+		if s.labels == nil {
+			s.labels = make(map[string]string)
+		}
+		s.labels[k] = v
+	}
+	for env, vars := range overlay.envOverrides {
+		if s.envOverrides == nil {
+			s.envOverrides = make(map[Environment]map[string]string)
+		}
+		if s.envOverrides[env] == nil {
+			s.envOverrides[env] = make(map[string]string)
+		}
+		for k, v := range vars {
+			s.envOverrides[env][k] = v
+		}
+	}
+
+	s.ports = appendUnique(s.ports, overlay.ports)
+	s.expose = appendUnique(s.expose, overlay.expose)
+	s.serviceDependencies = appendUnique(s.serviceDependencies, overlay.serviceDependencies)
+	s.extraHosts = appendUnique(s.extraHosts, overlay.extraHosts)
+	s.dns = appendUnique(s.dns, overlay.dns)
+	s.dnsSearch = appendUnique(s.dnsSearch, overlay.dnsSearch)
+	s.capAdd = appendUnique(s.capAdd, overlay.capAdd)
+	s.capDrop = appendUnique(s.capDrop, overlay.capDrop)
+	s.securityOpt = appendUnique(s.securityOpt, overlay.securityOpt)
+	s.tmpfs = appendUnique(s.tmpfs, overlay.tmpfs)
+	s.devices = appendUnique(s.devices, overlay.devices)
+
+	s.volumes = mergeBy(s.volumes, overlay.volumes, func(v Volume) string { return v.Target })
+	s.configs = mergeBy(s.configs, overlay.configs, func(c InlineConfig) string { return c.Name })
+	s.networks = mergeBy(s.networks, cloneNetworks(overlay.networks), func(n networkAttachment) string { return n.name })
+	s.ulimits = mergeBy(s.ulimits, overlay.ulimits, func(u ulimit) string { return u.name })
+	s.sysctls = mergeBy(s.sysctls, overlay.sysctls, func(kv [2]string) string { return kv[0] })
+
+	for _, f := range overlay.extensions {
+		s.extensions = setRawField(s.extensions, f.key, cloneRawValue(f.value))
+	}
+	s.deferredEnv = append(s.deferredEnv, overlay.deferredEnv...)
+	s.errors = append(s.errors, overlay.errors...)
+}
+
+// cloneServices copia cada servicio con cloneService
+func cloneServices(services []service) []service {
+	out := make([]service, len(services))
+	for i, s := range services {
+		out[i] = cloneService(s)
+	}
+	return out
+}
+
+// cloneService copia el servicio para poder modificarlo sin afectar al original
+func cloneService(s service) service {
+	out := s
+	out.ports = append([]string(nil), s.ports...)
+	out.expose = append([]string(nil), s.expose...)
+	out.environment = maps.Clone(s.environment)
+	if out.environment == nil {
+		out.environment = make(map[string]string)
+	}
+	out.labels = maps.Clone(s.labels)
+	out.volumes = append([]Volume(nil), s.volumes...)
+	out.configs = append([]InlineConfig(nil), s.configs...)
+	out.serviceDependencies = append([]string(nil), s.serviceDependencies...)
+	out.command = append([]string(nil), s.command...)
+	out.networks = cloneNetworks(s.networks)
+	out.extraHosts = append([]string(nil), s.extraHosts...)
+	out.dns = append([]string(nil), s.dns...)
+	out.dnsSearch = append([]string(nil), s.dnsSearch...)
+	out.sidecars = append([]*service(nil), s.sidecars...)
+	out.capAdd = append([]string(nil), s.capAdd...)
+	out.capDrop = append([]string(nil), s.capDrop...)
+	out.securityOpt = append([]string(nil), s.securityOpt...)
+	out.tmpfs = append([]string(nil), s.tmpfs...)
+	out.ulimits = append([]ulimit(nil), s.ulimits...)
+	out.sysctls = append([][2]string(nil), s.sysctls...)
+	out.deviceReservations = append([]DeviceReservation(nil), s.deviceReservations...)
+	out.devices = append([]string(nil), s.devices...)
+	out.developWatch = cloneWatchRules(s.developWatch)
+	if s.healthCheck != nil {
+		hc := *s.healthCheck
+		hc.Test = append([]string(nil), hc.Test...)
+		out.healthCheck = &hc
+	}
+	if s.credentialSpec != nil {
+		spec := *s.credentialSpec
+		out.credentialSpec = &spec
+	}
+	if s.scale != nil {
+		n := *s.scale
+		out.scale = &n
+	}
+	if s.envOverrides != nil {
+		out.envOverrides = make(map[Environment]map[string]string, len(s.envOverrides))
+		for env, vars := range s.envOverrides {
+			out.envOverrides[env] = maps.Clone(vars)
+		}
+	}
+	out.deferredEnv = append([]deferredEnv(nil), s.deferredEnv...)
+	out.extensions = cloneRawFields(s.extensions)
+	out.errors = append([]error(nil), s.errors...)
+	return out
+}
+
+// cloneNetworks copia las redes de un servicio junto con sus alias
+func cloneNetworks(networks []networkAttachment) []networkAttachment {
+	out := append([]networkAttachment(nil), networks...)
+	for i := range out {
+		out[i].Aliases = append([]string(nil), out[i].Aliases...)
+	}
+	return out
+}
+
+// cloneRawFields copia los campos de extensión y sus valores
+func cloneRawFields(fields []rawField) []rawField {
+	out := append([]rawField(nil), fields...)
+	for i := range out {
+		out[i].value = cloneRawValue(out[i].value)
+	}
+	return out
+}
+
+// cloneRawValue copia los mapas y slices de un valor de extensión, que es lo
+// que se suele pasar a SetExtension. Otros valores, como structs o punteros,
+// se comparten
+func cloneRawValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = cloneRawValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = cloneRawValue(item)
+		}
+		return out
+	case map[string]string:
+		return maps.Clone(v)
+	case []string:
+		return append([]string(nil), v...)
+	}
+	return value
+}
+
+// mergeString devuelve overlay si no está vacío
+func mergeString(base, overlay string) string {
+	if overlay != "" {
+		return overlay
+	}
+	return base
+}
+
+// appendUnique añade a base los elementos de overlay que aún no contiene
+func appendUnique(base, overlay []string) []string {
+	for _, item := range overlay {
+		found := false
+		for _, existing := range base {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			base = append(base, item)
+		}
+	}
+	return base
+}
+
+// mergeBy reemplaza en base los elementos de overlay con la misma clave y
+// añade al final los nuevos
+func mergeBy[T any](base, overlay []T, key func(T) string) []T {
+	for _, item := range overlay {
+		replaced := false
+		for i := range base {
+			if key(base[i]) == key(item) {
+				base[i] = item
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, item)
+		}
+	}
+	return base
+}
@@ -0,0 +1,104 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMerge(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *compose.NewService("db").SetImage("postgres:16")
+	api := *compose.NewService("api").
+		SetImage("myapi:1").
+		AddPort("8080", "8080").
+		AddEnvironment("LOG_LEVEL", "info").
+		AddEnvironment("PORT", "8080").
+		AddVolume(compose.Volume{Source: "./data", Target: "/data"}).
+		SetCommand("serve")
+	base, _ := compose.NewCompose("3.8", db, api)
+
+	devAPI := *compose.NewService("api").
+		SetImage("myapi:dev").
+		AddPort("9229", "9229").
+		AddEnvironment("LOG_LEVEL", "debug").
+		AddVolume(compose.Volume{Source: "./src", Target: "/data"}).
+		SetCommand("serve", "--debug")
+	cache := *compose.NewService("cache").SetImage("redis:7")
+	dev, _ := compose.NewCompose("3.8", devAPI, cache)
+
+	merged := compose.Merge(base, dev)
+
+	var result struct {
+		Services map[string]struct {
+			Image       string            `yaml:"image"`
+			Ports       []string          `yaml:"ports"`
+			Environment map[string]string `yaml:"environment"`
+			Volumes     []string          `yaml:"volumes"`
+			Command     []string          `yaml:"command"`
+		} `yaml:"services"`
+	}
+	data, err := merged.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["api"]
+	if got.Image != "myapi:dev" {
+		t.Errorf("image incorrecta: %q", got.Image)
+	}
+	if strings.Join(got.Ports, ",") != "8080:8080,9229:9229" {
+		t.Errorf("ports incorrectos: %v", got.Ports)
+	}
+	if got.Environment["LOG_LEVEL"] != "debug" || got.Environment["PORT"] != "8080" {
+		t.Errorf("environment incorrecto: %v", got.Environment)
+	}
+	if strings.Join(got.Volumes, ",") != "./src:/data" {
+		t.Errorf("volumes incorrectos: %v", got.Volumes)
+	}
+	if strings.Join(got.Command, " ") != "serve --debug" {
+		t.Errorf("command incorrecto: %v", got.Command)
+	}
+	if _, ok := result.Services["cache"]; !ok || len(result.Services) != 3 {
+		t.Errorf("servicios incorrectos: %v", result.Services)
+	}
+
+	// la base no se modifica
+	original, _ := base.Bytes()
+	if strings.Contains(string(original), "9229") || strings.Contains(string(original), "cache") {
+		t.Errorf("Merge modificó la base:\n%s", original)
+	}
+}
+
+func TestMergeDoesNotShareNestedValues(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	aliases := []string{"api"}
+	meta := map[string]any{"team": "core", "tags": []any{"web"}}
+	api := *compose.NewService("api").SetImage("api:1.0").
+		AttachNetwork("backend", compose.NetworkAttachment{Aliases: aliases}).
+		SetExtension("x-meta", meta)
+
+	base, _ := compose.NewCompose("3.8", api)
+	merged := compose.Merge(base)
+
+	aliases[0] = "changed"
+	meta["team"] = "changed"
+	meta["tags"].([]any)[0] = "changed"
+
+	for _, config := range []interface{ Bytes() ([]byte, error) }{base, merged} {
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if strings.Contains(string(data), "changed") {
+			t.Errorf("La copia comparte alias o extensiones con el servicio original:\n%s", data)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddExtraHost añade una entrada a /etc/hosts del contenedor (extra_hosts).
+// ip acepta "host-gateway" para resolver al host, por ejemplo con host.docker.internal en Linux
+func (s *service) AddExtraHost(host, ip string) *service {
+	s.extraHosts = append(s.extraHosts, fmt.Sprintf("%s:%s", host, ip))
+	return s
+}
+
+// SetDNS establece los servidores DNS del contenedor
+func (s *service) SetDNS(servers ...string) *service {
+	s.dns = append([]string{}, servers...)
+	return s
+}
+
+// SetDNSSearch establece los dominios de búsqueda DNS del contenedor
+func (s *service) SetDNSSearch(domains ...string) *service {
+	s.dnsSearch = append([]string{}, domains...)
+	return s
+}
+
+// SetNetworkMode establece el modo de red del contenedor: "host", "none",
+// "bridge", "service:<nombre>" o "container:<nombre>". Validate comprueba que
+// no se combine con networks ni con puertos publicados cuando no corresponde
+func (s *service) SetNetworkMode(mode string) *service {
+	s.networkMode = mode
+	return s
+}
+
+// validateNetworkMode revisa el modo de red de un servicio
+func (c *composeConfig) validateNetworkMode(s service) []error {
+	if s.networkMode == "" {
+		return nil
+	}
+
+	var errs []error
+	reject := func(value string, err error) {
+		errs = append(errs, &ValidationError{Service: s.name, Field: "network_mode", Value: value, Err: err})
+	}
+	invalid := fmt.Errorf("%w network_mode", ErrInvalidValue)
+	switch kind, target, _ := strings.Cut(s.networkMode, ":"); kind {
+	case "bridge":
+	case "host", "none":
+		if len(s.ports) > 0 {
+			reject(s.networkMode, errors.New("ports cannot be published with network_mode"))
+		}
+	case "service", "container":
+		if target == "" {
+			reject(s.networkMode, invalid)
+		} else if kind == "service" && !c.hasService(target) {
+			reject(target, errors.New("network_mode references unknown service"))
+		}
+		if len(s.ports) > 0 {
+			reject(target, errors.New("ports cannot be published when sharing the network namespace of"))
+		}
+	default:
+		reject(s.networkMode, invalid)
+	}
+
+	if len(s.networks) > 0 {
+		reject("", errors.New("network_mode and networks are mutually exclusive"))
+	}
+	return errs
+}
+
+// NetworkAttachment configura la conexión de un servicio a una red
+type NetworkAttachment struct {
+	Aliases     []string // nombres adicionales del servicio dentro de la red
+	IPv4Address string   // IP fija, requiere una subred definida con DefineNetwork
+	IPv6Address string
+	Priority    int // orden de conexión, mayor primero
+}
+
+// networkAttachment asocia una red a su configuración
+type networkAttachment struct {
+	name string
+	NetworkAttachment
+}
+
+// AttachNetwork conecta el servicio a la red con alias, IPs fijas o prioridad.
+// Las redes usadas se declaran automáticamente en la sección superior networks
+func (s *service) AttachNetwork(name string, attachment ...NetworkAttachment) *service {
+	var a NetworkAttachment
+	if len(attachment) > 0 {
+		a = attachment[0]
+	}
+	for i, n := range s.networks {
+		if n.name == name {
+			s.networks[i].NetworkAttachment = a
+			return s
+		}
+	}
+	s.networks = append(s.networks, networkAttachment{name, a})
+	return s
+}
+
+// NetworkConfig define una red en la sección superior networks
+type NetworkConfig struct {
+	Driver   string   // por ejemplo "bridge" u "overlay"
+	External bool     // la red ya existe y no la gestiona compose
+	Subnets  []string // subredes IPAM, necesarias para IPs fijas
+	Name     string   // nombre real de la red, sin el prefijo del proyecto
+}
+
+// DefineNetwork declara una red con su configuración. Las redes usadas con
+// AttachNetwork que no se definan se declaran con la configuración por defecto
+func (c *composeConfig) DefineNetwork(name string, config NetworkConfig) *composeConfig {
+	if c.networks == nil {
+		c.networks = make(map[string]NetworkConfig)
+	}
+	c.networks[name] = config
+	return c
+}
+
+// writeServiceNetworks escribe la forma extendida de networks de un servicio
+func writeServiceNetworks(b *strings.Builder, networks []networkAttachment) {
+	b.WriteString("    networks:\n")
+	for _, n := range networks {
+		a := n.NetworkAttachment
+		if len(a.Aliases) == 0 && a.IPv4Address == "" && a.IPv6Address == "" && a.Priority == 0 {
+			fmt.Fprintf(b, "      %s: {}\n", yamlPlain(n.name))
+			continue
+		}
+		fmt.Fprintf(b, "      %s:\n", yamlPlain(n.name))
+		if len(a.Aliases) > 0 {
+			b.WriteString("        aliases:\n")
+			for _, alias := range a.Aliases {
+				fmt.Fprintf(b, "          - %s\n", yamlQuote(alias))
+			}
+		}
+		if a.IPv4Address != "" {
+			fmt.Fprintf(b, "        ipv4_address: %s\n", yamlQuote(a.IPv4Address))
+		}
+		if a.IPv6Address != "" {
+			fmt.Fprintf(b, "        ipv6_address: %s\n", yamlQuote(a.IPv6Address))
+		}
+		if a.Priority != 0 {
+			fmt.Fprintf(b, "        priority: %d\n", a.Priority)
+		}
+	}
+}
+
+// writeNetworks escribe la sección superior networks con las redes definidas y
+// las usadas por los servicios, en orden de aparición
+func (c composeConfig) writeNetworks(b *strings.Builder) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] && name != "default" {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, s := range c.services {
+		for _, n := range s.networks {
+			add(n.name)
+		}
+	}
+	for _, name := range sortedKeys(c.networks) {
+		add(name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	// traefik.docker.network debe nombrar la red real, así que la red de
+	// Traefik se crea sin el prefijo del proyecto
+	traefikNetworks := make(map[string]bool)
+	for _, s := range c.services {
+		if network := s.labels["traefik.docker.network"]; network != "" {
+			traefikNetworks[network] = true
+		}
+	}
+
+	b.WriteString("networks:\n")
+	for _, name := range names {
+		config := c.networks[name]
+		if traefikNetworks[name] && config.Name == "" && !config.External {
+			config.Name = name
+		}
+		if config.Driver == "" && !config.External && len(config.Subnets) == 0 && config.Name == "" {
+			fmt.Fprintf(b, "  %s: {}\n", yamlPlain(name))
+			continue
+		}
+		fmt.Fprintf(b, "  %s:\n", yamlPlain(name))
+		if config.External {
+			b.WriteString("    external: true\n")
+		}
+		if config.Driver != "" {
+			fmt.Fprintf(b, "    driver: %s\n", yamlQuote(config.Driver))
+		}
+		if config.Name != "" {
+			fmt.Fprintf(b, "    name: %s\n", yamlQuote(config.Name))
+		}
+		if len(config.Subnets) > 0 {
+			b.WriteString("    ipam:\n")
+			b.WriteString("      config:\n")
+			for _, subnet := range config.Subnets {
+				fmt.Fprintf(b, "        - subnet: %s\n", yamlQuote(subnet))
+			}
+		}
+	}
+}
+
+// exposePattern valida puertos de expose: número o rango con protocolo opcional
+var exposePattern = regexp.MustCompile(`^\d+(-\d+)?(/(tcp|udp|sctp))?$`)
+
+// Expose declara puertos accesibles solo desde otros contenedores (expose),
+// sin publicarlos en el host como hace AddPort
+func (s *service) Expose(ports ...string) *service {
+	for _, port := range ports {
+		if !exposePattern.MatchString(port) {
+			s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "expose", Value: port, Err: ErrInvalidPort})
+			continue
+		}
+		s.expose = append(s.expose, port)
+	}
+	return s
+}
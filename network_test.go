@@ -0,0 +1,159 @@
+package compose_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtraHostsAndDNS(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api").
+		AddExtraHost("host.docker.internal", "host-gateway").
+		AddExtraHost("db.internal", "10.0.0.5").
+		SetDNS("10.0.0.2", "1.1.1.1").
+		SetDNSSearch("corp.internal")
+
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			ExtraHosts []string `yaml:"extra_hosts"`
+			DNS        []string `yaml:"dns"`
+			DNSSearch  []string `yaml:"dns_search"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["api"]
+	if !reflect.DeepEqual(got.ExtraHosts, []string{"host.docker.internal:host-gateway", "db.internal:10.0.0.5"}) {
+		t.Errorf("extra_hosts incorrecto: %q", got.ExtraHosts)
+	}
+	if !reflect.DeepEqual(got.DNS, []string{"10.0.0.2", "1.1.1.1"}) || !reflect.DeepEqual(got.DNSSearch, []string{"corp.internal"}) {
+		t.Errorf("dns incorrecto: %q %q", got.DNS, got.DNSSearch)
+	}
+}
+
+func TestNetworkMode(t *testing.T) {
+	agent := *compose.NewService("agent").SetImage("datadog/agent").SetNetworkMode("host")
+	debug := *compose.NewService("debug").SetImage("nicolaka/netshoot").SetNetworkMode("service:agent")
+
+	config, _ := compose.NewCompose("3.8", agent, debug)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			NetworkMode string `yaml:"network_mode"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+	if result.Services["agent"].NetworkMode != "host" || result.Services["debug"].NetworkMode != "service:agent" {
+		t.Errorf("network_mode incorrecto: %+v", result.Services)
+	}
+
+	for mode, want := range map[string]string{
+		"host":          "ports cannot be published",
+		"service:ghost": `unknown service "ghost"`,
+		"overlay":       "invalid network_mode",
+	} {
+		bad := *compose.NewService("bad").SetImage("x").AddPort("80", "80").SetNetworkMode(mode)
+		config, _ := compose.NewCompose("3.8", bad)
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("network_mode %q: se esperaba %q, obtenido %v", mode, want, err)
+		}
+	}
+}
+
+func TestAttachNetwork(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16").
+		AttachNetwork("backend", compose.NetworkAttachment{
+			Aliases:     []string{"database", "pg"},
+			IPv4Address: "172.28.0.10",
+			Priority:    1000,
+		}).
+		AttachNetwork("monitoring")
+
+	config, _ := compose.NewCompose("3.8", db)
+	config.DefineNetwork("backend", compose.NetworkConfig{Subnets: []string{"172.28.0.0/16"}})
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Networks map[string]struct {
+				Aliases     []string `yaml:"aliases"`
+				IPv4Address string   `yaml:"ipv4_address"`
+				Priority    int      `yaml:"priority"`
+			} `yaml:"networks"`
+		} `yaml:"services"`
+		Networks map[string]struct {
+			IPAM struct {
+				Config []struct {
+					Subnet string `yaml:"subnet"`
+				} `yaml:"config"`
+			} `yaml:"ipam"`
+		} `yaml:"networks"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+
+	backend := result.Services["db"].Networks["backend"]
+	if !reflect.DeepEqual(backend.Aliases, []string{"database", "pg"}) || backend.IPv4Address != "172.28.0.10" || backend.Priority != 1000 {
+		t.Errorf("Conexión a backend incorrecta: %+v", backend)
+	}
+	if _, ok := result.Services["db"].Networks["monitoring"]; !ok {
+		t.Error("Falta la red monitoring en el servicio")
+	}
+
+	if len(result.Networks) != 2 || result.Networks["backend"].IPAM.Config[0].Subnet != "172.28.0.0/16" {
+		t.Errorf("Redes superiores incorrectas: %+v", result.Networks)
+	}
+}
+
+func TestExpose(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api").AddPort("8080", "8080").Expose("9000", "7000-7010/udp")
+
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Ports  []string `yaml:"ports"`
+			Expose []string `yaml:"expose"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["api"]
+	if !reflect.DeepEqual(got.Expose, []string{"9000", "7000-7010/udp"}) || len(got.Ports) != 1 {
+		t.Errorf("expose incorrecto: %+v", got)
+	}
+
+	bad := *compose.NewService("bad").SetImage("x").Expose("80:80")
+	config, _ = compose.NewCompose("3.8", bad)
+	if err := config.Validate(); err == nil {
+		t.Error("Se esperaba un error por puerto expose inválido")
+	}
+}
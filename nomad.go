@@ -0,0 +1,272 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nomadJob es el jobspec en el formato JSON de la API de Nomad
+type nomadJob struct {
+	ID          string
+	Name        string
+	Type        string
+	Datacenters []string
+	TaskGroups  []nomadTaskGroup
+}
+
+type nomadTaskGroup struct {
+	Name          string
+	Count         int
+	Networks      []nomadNetwork      `json:",omitempty"`
+	Services      []nomadService      `json:",omitempty"`
+	RestartPolicy *nomadRestartPolicy `json:",omitempty"`
+	Tasks         []nomadTask
+}
+
+type nomadNetwork struct {
+	Mode          string
+	ReservedPorts []nomadPort `json:",omitempty"`
+	DynamicPorts  []nomadPort `json:",omitempty"`
+}
+
+type nomadPort struct {
+	Label string
+	Value int `json:",omitempty"`
+	To    int `json:",omitempty"`
+}
+
+type nomadService struct {
+	Name      string
+	PortLabel string `json:",omitempty"`
+	Provider  string
+	TaskName  string       `json:",omitempty"`
+	Checks    []nomadCheck `json:",omitempty"`
+}
+
+type nomadCheck struct {
+	Name     string
+	Type     string
+	Command  string
+	Args     []string `json:",omitempty"`
+	Interval int64
+	Timeout  int64
+}
+
+type nomadRestartPolicy struct {
+	Attempts int
+	Mode     string
+}
+
+type nomadTask struct {
+	Name   string
+	Driver string
+	Config map[string]any
+	Env    map[string]string `json:",omitempty"`
+}
+
+// Nomad usa estos valores cuando el healthcheck no los indica
+const (
+	nomadCheckInterval = 30 * time.Second
+	nomadCheckTimeout  = 5 * time.Second
+)
+
+// ToNomad genera un jobspec JSON de HashiCorp Nomad (para "nomad job run -json")
+// con un grupo por servicio: la imagen, el comando, el entorno y los volúmenes
+// pasan a una tarea docker, los puertos a la red del grupo y el healthcheck a
+// un check de tipo script. depends_on no tiene equivalente en Nomad y se omite.
+// Las referencias ${VAR} se resuelven con el .env
+func (c *composeConfig) ToNomad() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("configuración inválida: %w", err)
+	}
+
+	project := c.project()
+	job := nomadJob{
+		ID:          project,
+		Name:        project,
+		Type:        "service",
+		Datacenters: []string{"dc1"},
+	}
+
+	env, err := c.projectEnv()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range c.orderedServices() {
+		group, err := nomadGroup(project, s, env)
+		if err != nil {
+			return nil, err
+		}
+		job.TaskGroups = append(job.TaskGroups, group)
+	}
+
+	return json.MarshalIndent(map[string]any{"Job": job}, "", "  ")
+}
+
+// nomadGroup convierte un servicio en un grupo con una única tarea docker,
+// resolviendo las referencias ${VAR} con env
+func nomadGroup(project string, s service, env map[string]string) (nomadTaskGroup, error) {
+	if s.image == "" {
+		return nomadTaskGroup{}, fmt.Errorf("service %q: nomad export requires an image, build it first", s.name)
+	}
+
+	var errs []error
+	interpolate := func(value string) string {
+		out, err := interpolateEnv(value, env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %q: %w", s.name, err))
+		}
+		return out
+	}
+
+	group := nomadTaskGroup{Name: s.name, Count: 1}
+	if s.scale != nil {
+		group.Count = *s.scale
+	}
+
+	config := map[string]any{"image": interpolate(s.image)}
+	task := nomadTask{Name: s.name, Driver: "docker", Config: config}
+
+	// puertos
+	var labels []string
+	network := nomadNetwork{Mode: "bridge"}
+	for _, port := range s.ports {
+		spec, err := parsePort(interpolate(port))
+		if err != nil {
+			return group, fmt.Errorf("service %q: %w", s.name, err)
+		}
+		if spec.containerEnd != spec.containerStart {
+			return group, fmt.Errorf("service %q: nomad export does not support port ranges", s.name)
+		}
+		label := "port_" + strconv.Itoa(spec.containerStart)
+		labels = append(labels, label)
+		if spec.hostStart > 0 {
+			network.ReservedPorts = append(network.ReservedPorts, nomadPort{Label: label, Value: spec.hostStart, To: spec.containerStart})
+		} else {
+			network.DynamicPorts = append(network.DynamicPorts, nomadPort{Label: label, To: spec.containerStart})
+		}
+	}
+	if len(labels) > 0 {
+		group.Networks = []nomadNetwork{network}
+		config["ports"] = labels
+	}
+
+	if len(s.command) > 0 {
+		args := make([]string, len(s.command))
+		for i, arg := range s.command {
+			args[i] = interpolate(arg)
+		}
+		config["args"] = args
+	}
+	if value, ok := rawFieldValue(s.extensions, "entrypoint"); ok {
+		config["entrypoint"] = []string{fmt.Sprint(value)}
+	}
+	if len(s.volumes) > 0 {
+		var volumes []string
+		for _, vol := range s.volumes {
+			if vol.Source == "" {
+				return group, fmt.Errorf("service %q: nomad export does not support anonymous volumes", s.name)
+			}
+			volumes = append(volumes, interpolate(vol.Source)+":"+vol.Target)
+		}
+		config["volumes"] = volumes
+	}
+	if s.privileged {
+		config["privileged"] = true
+	}
+	if len(s.capAdd) > 0 {
+		config["cap_add"] = s.capAdd
+	}
+	if len(s.capDrop) > 0 {
+		config["cap_drop"] = s.capDrop
+	}
+	if len(s.extraHosts) > 0 {
+		config["extra_hosts"] = s.extraHosts
+	}
+	if len(s.dns) > 0 {
+		config["dns_servers"] = s.dns
+	}
+
+	if len(s.environment) > 0 {
+		task.Env = make(map[string]string, len(s.environment))
+		for key, value := range s.environment {
+			task.Env[key] = interpolate(value)
+		}
+	}
+
+	// servicio y healthcheck
+	if len(labels) > 0 || s.healthCheck != nil {
+		svc := nomadService{Name: project + "-" + s.name, Provider: "nomad"}
+		if len(labels) > 0 {
+			svc.PortLabel = labels[0]
+		}
+		if check, ok, err := nomadHealthCheck(s); err != nil {
+			return group, err
+		} else if ok {
+			svc.TaskName = s.name
+			svc.Checks = []nomadCheck{check}
+		}
+		group.Services = []nomadService{svc}
+	}
+
+	group.RestartPolicy = nomadRestart(s.restartPolicy)
+	group.Tasks = []nomadTask{task}
+
+	if len(errs) > 0 {
+		return group, errs[0]
+	}
+	return group, nil
+}
+
+// nomadHealthCheck convierte el healthcheck en un check de tipo script
+func nomadHealthCheck(s service) (nomadCheck, bool, error) {
+	hc := s.healthCheck
+	if hc == nil || len(hc.Test) < 2 {
+		return nomadCheck{}, false, nil
+	}
+
+	check := nomadCheck{Name: s.name + "-health", Type: "script"}
+	switch hc.Test[0] {
+	case "CMD":
+		check.Command, check.Args = hc.Test[1], hc.Test[2:]
+	case "CMD-SHELL":
+		check.Command, check.Args = "/bin/sh", []string{"-c", strings.Join(hc.Test[1:], " ")}
+	default:
+		return nomadCheck{}, false, nil
+	}
+
+	interval, timeout := nomadCheckInterval, nomadCheckTimeout
+	var err error
+	if hc.Interval != "" {
+		if interval, err = time.ParseDuration(hc.Interval); err != nil {
+			return check, false, fmt.Errorf("service %q: invalid healthcheck interval %q", s.name, hc.Interval)
+		}
+	}
+	if hc.Timeout != "" {
+		if timeout, err = time.ParseDuration(hc.Timeout); err != nil {
+			return check, false, fmt.Errorf("service %q: invalid healthcheck timeout %q", s.name, hc.Timeout)
+		}
+	}
+	check.Interval, check.Timeout = int64(interval), int64(timeout)
+	return check, true, nil
+}
+
+// nomadRestart traduce la política de reinicio de compose a la de Nomad
+func nomadRestart(policy string) *nomadRestartPolicy {
+	switch {
+	case policy == RestartNo:
+		return &nomadRestartPolicy{Attempts: 0, Mode: "fail"}
+	case strings.HasPrefix(policy, RestartOnFailure+":"):
+		attempts, _ := strconv.Atoi(strings.TrimPrefix(policy, RestartOnFailure+":"))
+		return &nomadRestartPolicy{Attempts: attempts, Mode: "fail"}
+	case policy == RestartAlways || policy == RestartUnlessStopped:
+		return &nomadRestartPolicy{Attempts: 2, Mode: "delay"}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package compose_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestToNomad(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	api := *compose.NewService("api").SetImage("ghcr.io/org/api:1.0").
+		AddPort("8080", "80").
+		AddPort("", "9090").
+		AddEnvironment("MODE", "prod").
+		SetCommand("serve", "--verbose").
+		SetHealthCheck([]string{"CMD-SHELL", "curl -f http://localhost/"}, "10s", "2s", 3).
+		SetRestartPolicy("on-failure:5").
+		SetScale(3)
+
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetProjectName("shop")
+
+	data, err := config.ToNomad()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var spec struct {
+		Job struct {
+			ID         string
+			Type       string
+			TaskGroups []struct {
+				Name     string
+				Count    int
+				Networks []struct {
+					Mode          string
+					ReservedPorts []struct {
+						Label     string
+						Value, To int
+					}
+					DynamicPorts []struct {
+						Label string
+						To    int
+					}
+				}
+				Services []struct {
+					Name      string
+					PortLabel string
+					Checks    []struct {
+						Type     string
+						Command  string
+						Args     []string
+						Interval int64
+					}
+				}
+				RestartPolicy struct {
+					Attempts int
+					Mode     string
+				}
+				Tasks []struct {
+					Driver string
+					Config map[string]any
+					Env    map[string]string
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("JSON inválido: %v\n%s", err, data)
+	}
+
+	job := spec.Job
+	if job.ID != "shop" || job.Type != "service" || len(job.TaskGroups) != 1 {
+		t.Fatalf("Job incorrecto:\n%s", data)
+	}
+	group := job.TaskGroups[0]
+	if group.Name != "api" || group.Count != 3 {
+		t.Errorf("Grupo incorrecto: %+v", group)
+	}
+	if n := group.Networks; len(n) != 1 || len(n[0].ReservedPorts) != 1 || n[0].ReservedPorts[0].Value != 8080 ||
+		n[0].ReservedPorts[0].To != 80 || len(n[0].DynamicPorts) != 1 || n[0].DynamicPorts[0].To != 9090 {
+		t.Errorf("Red incorrecta: %+v", n)
+	}
+	if s := group.Services; len(s) != 1 || s[0].Name != "shop-api" || len(s[0].Checks) != 1 ||
+		s[0].Checks[0].Command != "/bin/sh" || s[0].Checks[0].Interval != 10e9 {
+		t.Errorf("Servicio incorrecto: %+v", s)
+	}
+	if r := group.RestartPolicy; r.Attempts != 5 || r.Mode != "fail" {
+		t.Errorf("Política de reinicio incorrecta: %+v", r)
+	}
+	task := group.Tasks[0]
+	if task.Driver != "docker" || task.Config["image"] != "ghcr.io/org/api:1.0" || task.Env["MODE"] != "prod" {
+		t.Errorf("Tarea incorrecta: %+v", task)
+	}
+}
@@ -0,0 +1,81 @@
+package compose
+
+import "sort"
+
+// ServiceOrder indica en qué orden se escriben los servicios en el YAML
+type ServiceOrder int
+
+const (
+	OrderDeclared     ServiceOrder = iota // en el orden en que se añadieron (por defecto)
+	OrderAlphabetical                     // por nombre
+	OrderTopological                      // dependencias antes que dependientes, empates por nombre
+)
+
+// SetServiceOrder cambia el orden de los servicios en el YAML generado, útil
+// para que el archivo no cambie según qué parte del código añadió cada servicio
+func (c *composeConfig) SetServiceOrder(order ServiceOrder) *composeConfig {
+	c.serviceOrder = order
+	return c
+}
+
+// orderedServices devuelve los servicios en el orden configurado
+func (c composeConfig) orderedServices() []service {
+	switch c.serviceOrder {
+	case OrderAlphabetical:
+		out := append([]service(nil), c.services...)
+		sort.SliceStable(out, func(i, j int) bool { return out[i].name < out[j].name })
+		return out
+	case OrderTopological:
+		return c.topologicalServices()
+	}
+	return c.services
+}
+
+// topologicalServices ordena los servicios para que cada uno aparezca después
+// de sus dependencias. Los que forman un ciclo quedan al final en orden alfabético
+func (c composeConfig) topologicalServices() []service {
+	byName := make(map[string]service, len(c.services))
+	pending := make(map[string]int, len(c.services))
+	for _, s := range c.services {
+		byName[s.name] = s
+	}
+	for _, s := range c.services {
+		for _, dep := range s.serviceDependencies {
+			if _, ok := byName[dep]; ok {
+				pending[s.name]++
+			}
+		}
+	}
+
+	var out []service
+	done := make(map[string]bool, len(c.services))
+	for len(out) < len(byName) {
+		var ready []string
+		for name := range byName {
+			if !done[name] && pending[name] == 0 {
+				ready = append(ready, name)
+			}
+		}
+		if len(ready) == 0 {
+			// ciclo: el resto se emite por nombre
+			for name := range byName {
+				if !done[name] {
+					ready = append(ready, name)
+				}
+			}
+		}
+		sort.Strings(ready)
+
+		next := ready[0]
+		done[next] = true
+		out = append(out, byName[next])
+		for _, s := range c.services {
+			for _, dep := range s.serviceDependencies {
+				if dep == next {
+					pending[s.name]--
+				}
+			}
+		}
+	}
+	return out
+}
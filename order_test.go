@@ -0,0 +1,58 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestServiceOrder(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres")
+	cache := *compose.NewService("cache").SetImage("redis")
+	api := *compose.NewService("api").SetImage("myapi").DependsOn(db, cache)
+	web := *compose.NewService("web").SetImage("nginx").DependsOn(api)
+
+	cases := []struct {
+		order compose.ServiceOrder
+		want  string
+	}{
+		{compose.OrderDeclared, "web,api,db,cache"},
+		{compose.OrderAlphabetical, "api,cache,db,web"},
+		{compose.OrderTopological, "cache,db,api,web"},
+	}
+	for _, c := range cases {
+		config, _ := compose.NewCompose("3.8", web, api, db, cache)
+		data, err := config.SetServiceOrder(c.order).Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if got := serviceOrder(t, data); got != c.want {
+			t.Errorf("Orden %d: se esperaba %s, se obtuvo %s", c.order, c.want, got)
+		}
+	}
+}
+
+// serviceOrder devuelve los nombres de servicio en el orden en que aparecen
+func serviceOrder(t *testing.T, data []byte) string {
+	t.Helper()
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "services" {
+			continue
+		}
+		var names []string
+		services := root.Content[i+1]
+		for j := 0; j+1 < len(services.Content); j += 2 {
+			names = append(names, services.Content[j].Value)
+		}
+		return strings.Join(names, ",")
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envOverridePrefix es el prefijo de las variables de entorno leídas por ApplyEnvOverrides
+const envOverridePrefix = "COMPOSE_GEN_"
+
+// Overrides acumula valores "--set ruta=valor" y cumple flag.Value, de modo que
+// una herramienta de línea de comandos puede registrarlo con flag.Var(&o, "set", "...")
+type Overrides []string
+
+// String cumple flag.Value
+func (o *Overrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+// Set cumple flag.Value, validando que el valor tenga la forma ruta=valor
+func (o *Overrides) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("invalid override %q, expected path=value", value)
+	}
+	*o = append(*o, value)
+	return nil
+}
+
+// ApplyOverrides aplica sobre la configuración valores con la forma
+// "service.<nombre>.<campo>=valor", por ejemplo "service.api.image=foo:2.0".
+// Campos soportados: image, build, container_name, restart y environment.<VAR>.
+// También se acepta "version=<valor>"
+func (c *composeConfig) ApplyOverrides(overrides ...string) error {
+	var errs []error
+	for _, override := range overrides {
+		path, value, found := strings.Cut(override, "=")
+		if !found {
+			errs = append(errs, fmt.Errorf("invalid override %q, expected path=value", override))
+			continue
+		}
+		if err := c.Set(path, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Set modifica un campo de la configuración indicado por su ruta, ver
+// ApplyOverrides. La imagen y la política de reinicio se validan igual que
+// con SetImage y SetRestartPolicy, y un valor inválido se devuelve como error
+func (c *composeConfig) Set(path, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if path == "version" {
+		c.version = value
+		return nil
+	}
+
+	parts := strings.SplitN(path, ".", 4)
+	if len(parts) < 3 || parts[0] != "service" {
+		return fmt.Errorf("invalid override path %q", path)
+	}
+
+	s := c.serviceByName(parts[1])
+	if s == nil {
+		return fmt.Errorf("override %q: unknown service %q", path, parts[1])
+	}
+
+	switch field := parts[2]; {
+	case field == "image" && len(parts) == 3:
+		return s.applySetter(func(s *service) { s.SetImage(value) })
+	case field == "build" && len(parts) == 3:
+		s.build = value
+	case field == "container_name" && len(parts) == 3:
+		s.containerName = value
+	case field == "restart" && len(parts) == 3:
+		return s.applySetter(func(s *service) { s.SetRestartPolicy(value) })
+	case field == "environment" && len(parts) == 4:
+		s.environment[parts[3]] = value
+	default:
+		return fmt.Errorf("override %q: unsupported field", path)
+	}
+	return nil
+}
+
+// applySetter ejecuta set sobre el servicio y devuelve los errores que
+// registre en lugar de dejarlos acumulados en él
+func (s *service) applySetter(set func(*service)) error {
+	n := len(s.errors)
+	set(s)
+	err := errors.Join(s.errors[n:]...)
+	s.errors = s.errors[:n]
+	return err
+}
+
+// ApplyEnvOverrides aplica las variables de entorno COMPOSE_GEN_SERVICE_<NOMBRE>_<CAMPO>,
+// por ejemplo COMPOSE_GEN_SERVICE_API_IMAGE=foo:2.0. El nombre del servicio se
+// compara en mayúsculas y con "-" reemplazado por "_"
+func (c *composeConfig) ApplyEnvOverrides() error {
+	var errs []error
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		name, found := strings.CutPrefix(key, envOverridePrefix)
+		if !found {
+			continue
+		}
+
+		path, err := c.envOverridePath(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		if err := c.Set(path, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// envOverridePath traduce SERVICE_<NOMBRE>_<CAMPO> o VERSION a una ruta de Set
+func (c *composeConfig) envOverridePath(name string) (string, error) {
+	if name == "VERSION" {
+		return "version", nil
+	}
+
+	rest, found := strings.CutPrefix(name, "SERVICE_")
+	if !found {
+		return "", errors.New("expected SERVICE_<NAME>_<FIELD> or VERSION")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// con servicios como api y api-worker gana el prefijo más largo, para que
+	// SERVICE_API_WORKER_IMAGE no se lea como el campo worker_image de api
+	var match, field string
+	for _, s := range c.services {
+		prefix := strings.ToUpper(strings.ReplaceAll(s.name, "-", "_")) + "_"
+		rest, found := strings.CutPrefix(rest, prefix)
+		if found && len(s.name) > len(match) {
+			match, field = s.name, rest
+		}
+	}
+	if match == "" {
+		return "", errors.New("no service matches the variable name")
+	}
+
+	if env, found := strings.CutPrefix(field, "ENVIRONMENT_"); found {
+		return "service." + match + ".environment." + env, nil
+	}
+	return "service." + match + "." + strings.ToLower(field), nil
+}
+
+// serviceByName devuelve un puntero al servicio de la configuración con ese nombre
+func (c *composeConfig) serviceByName(name string) *service {
+	for i := range c.services {
+		if c.services[i].name == name {
+			return &c.services[i]
+		}
+	}
+	return nil
+}
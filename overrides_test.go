@@ -0,0 +1,86 @@
+package compose_test
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOverrides(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api:1.0")
+	worker := *compose.NewService("queue-worker").SetImage("worker:1.0")
+	config, _ := compose.NewCompose("3.8", api, worker)
+
+	var overrides compose.Overrides
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.Var(&overrides, "set", "override path=value")
+	if err := flags.Parse([]string{"--set", "service.api.image=api:2.0", "--set", "service.api.environment.MODE=ci"}); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if err := config.ApplyOverrides(overrides...); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	t.Setenv("COMPOSE_GEN_SERVICE_QUEUE_WORKER_IMAGE", "worker:2.0")
+	if err := config.ApplyEnvOverrides(); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Image       string            `yaml:"image"`
+			Environment map[string]string `yaml:"environment"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	if result.Services["api"].Image != "api:2.0" || result.Services["api"].Environment["MODE"] != "ci" {
+		t.Errorf("Override de api no aplicado: %+v", result.Services["api"])
+	}
+	if result.Services["queue-worker"].Image != "worker:2.0" {
+		t.Errorf("Override por entorno no aplicado: %+v", result.Services["queue-worker"])
+	}
+
+	err = config.ApplyOverrides("service.nope.image=x", "service.api.ports=80")
+	if err == nil || !strings.Contains(err.Error(), "unknown service") || !strings.Contains(err.Error(), "unsupported field") {
+		t.Errorf("Se esperaban errores de override: %v", err)
+	}
+}
+
+func TestEnvOverridesLongestServiceName(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api:1.0")
+	worker := *compose.NewService("api-worker").SetImage("worker:1.0")
+	config, _ := compose.NewCompose("3.8", api, worker)
+
+	t.Setenv("COMPOSE_GEN_SERVICE_API_WORKER_IMAGE", "worker:2.0")
+	if err := config.ApplyEnvOverrides(); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), `image: "worker:2.0"`) || !strings.Contains(string(data), `image: "api:1.0"`) {
+		t.Errorf("El override debía aplicarse a api-worker:\n%s", data)
+	}
+
+	err = config.ApplyOverrides("service.api.image=Not Valid", "service.api.restart=sometimes")
+	if err == nil || !strings.Contains(err.Error(), "image") || !strings.Contains(err.Error(), "restart") {
+		t.Errorf("Se esperaban errores de imagen y política de reinicio: %v", err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Un override rechazado no debía invalidar la configuración: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package compose
+
+import "os"
+
+const (
+	// defaultComposeFileMode es el modo de docker-compose.yml, legible por todos
+	defaultComposeFileMode os.FileMode = 0644
+	// defaultEnvFileMode es el modo del .env, que contiene secretos
+	defaultEnvFileMode os.FileMode = 0600
+)
+
+// fileOwner es el uid/gid a asignar a un archivo generado (solo Unix)
+type fileOwner struct {
+	uid, gid int
+}
+
+// FileMode cambia el modo con que Save escribe el archivo, por defecto 0644
+func FileMode(perm os.FileMode) SaveOption {
+	return func(o *saveOptions) {
+		o.perm = perm
+	}
+}
+
+// FileOwner hace que Save asigne uid/gid al archivo escrito. Solo en Unix y
+// normalmente requiere privilegios
+func FileOwner(uid, gid int) SaveOption {
+	return func(o *saveOptions) {
+		o.owner = []fileOwner{{uid, gid}}
+	}
+}
+
+// SetEnvFileMode cambia el modo de los .env que escribe la configuración, por
+// defecto 0600 ya que contienen secretos
+func (c *composeConfig) SetEnvFileMode(perm os.FileMode) *composeConfig {
+	c.files.envPerm = perm
+	return c
+}
+
+// SetEnvFileOwner asigna uid/gid a los .env que escribe la configuración (solo Unix)
+func (c *composeConfig) SetEnvFileOwner(uid, gid int) *composeConfig {
+	c.files.envOwner = []fileOwner{{uid, gid}}
+	return c
+}
+
+// envFileAttrs devuelve el modo y el dueño configurados para los .env
+func (f *fileSettings) envFileAttrs() (os.FileMode, []fileOwner) {
+	if f.envPerm == 0 {
+		return defaultEnvFileMode, f.envOwner
+	}
+	return f.envPerm, f.envOwner
+}
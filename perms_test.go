@@ -0,0 +1,61 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("los permisos Unix no aplican en Windows")
+	}
+	dir := t.TempDir()
+
+	t.Run(".env por defecto 0600", func(t *testing.T) {
+		envPath := filepath.Join(dir, ".env")
+		if err := compose.AddEnvToFile("SECRET", "value", envPath, filepath.Join(dir, ".gitignore")); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		checkMode(t, envPath, 0600)
+	})
+
+	t.Run("SetEnvFileMode", func(t *testing.T) {
+		chdir(t, t.TempDir())
+
+		web := *compose.NewService("web").SetImage("nginx").AddEnvironment("OTHER", "value")
+		config, _ := compose.NewCompose("3.8", web)
+		config.SetEnvFileMode(0640).SetEnvFileOwner(os.Getuid(), os.Getgid())
+		if _, err := config.Save(context.Background()); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		checkMode(t, ".env", 0640)
+	})
+
+	t.Run("FileMode y FileOwner en Save", func(t *testing.T) {
+		path := filepath.Join(dir, "docker-compose.yml")
+		config, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx"))
+
+		_, err := config.Save(context.Background(), compose.SaveTo(path),
+			compose.FileMode(0600), compose.FileOwner(os.Getuid(), os.Getgid()))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		checkMode(t, path, 0600)
+	})
+}
+
+func checkMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Error leyendo %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("Modo de %s: se esperaba %o, se obtuvo %o", path, want, got)
+	}
+}
@@ -0,0 +1,227 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// portSpec es un puerto publicado ya interpretado
+type portSpec struct {
+	hostIP                       string
+	hostStart, hostEnd           int // 0 si no se publica en el host
+	containerStart, containerEnd int
+	protocol                     string
+}
+
+// parsePort interpreta [ip:][host:]container[/protocolo] con rangos opcionales,
+// comprobando que los puertos estén entre 1 y 65535 y que los rangos cuadren.
+// Un rango del host puede apuntar a un solo puerto del contenedor, como en
+// "8000-8010:80", y docker elige uno libre del rango
+func parsePort(spec string) (portSpec, error) {
+	var p portSpec
+	if !portPattern.MatchString(spec) {
+		return p, ErrInvalidPort
+	}
+
+	rest, proto, _ := strings.Cut(spec, "/")
+	p.protocol = proto
+	if p.protocol == "" {
+		p.protocol = "tcp"
+	}
+
+	// la IP puede ser IPv6 entre corchetes, que contiene ":"
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		p.hostIP, rest = rest[1:end], strings.TrimPrefix(rest[end+1:], ":")
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) == 3 {
+		p.hostIP, parts = parts[0], parts[1:]
+	}
+
+	var err error
+	if p.containerStart, p.containerEnd, err = parsePortRange(parts[len(parts)-1]); err != nil {
+		return p, err
+	}
+	if len(parts) == 2 {
+		if p.hostStart, p.hostEnd, err = parsePortRange(parts[0]); err != nil {
+			return p, err
+		}
+		if p.containerStart != p.containerEnd && p.hostEnd-p.hostStart != p.containerEnd-p.containerStart {
+			return p, fmt.Errorf("%w: host and container ranges differ in size", ErrInvalidPort)
+		}
+	}
+	return p, nil
+}
+
+// validatePort revisa la sintaxis de una publicación de puertos. Las partes con
+// variables, como el puerto del host en "${PORT}:80" de Var, se resuelven al
+// arrancar y no se revisan, pero sí el resto: el puerto del contenedor, el
+// protocolo y la IP
+func validatePort(spec string) error {
+	if !strings.Contains(spec, "$") {
+		_, err := parsePort(spec)
+		return err
+	}
+
+	// cada referencia queda como "$" para que los ":" de ${PORT:-80} no la partan
+	masked := envReferencePattern.ReplaceAllLiteralString(spec, "$")
+	rest, proto, hasProto := strings.Cut(masked, "/")
+	if hasProto && !strings.Contains(proto, "$") && proto != "tcp" && proto != "udp" && proto != "sctp" {
+		return fmt.Errorf("%w: unknown protocol %s", ErrInvalidPort, proto)
+	}
+
+	var ip string
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return ErrInvalidPort
+		}
+		ip, rest = rest[1:end], strings.TrimPrefix(rest[end+1:], ":")
+	}
+	parts := strings.Split(rest, ":")
+	switch {
+	case len(parts) > 3 || len(parts) == 3 && ip != "":
+		return ErrInvalidPort
+	case len(parts) == 3:
+		ip, parts = parts[0], parts[1:]
+	}
+	if ip != "" && !strings.Contains(ip, "$") && net.ParseIP(ip) == nil {
+		return fmt.Errorf("%w: invalid host IP %s", ErrInvalidPort, ip)
+	}
+
+	for _, part := range parts {
+		if strings.Contains(part, "$") {
+			continue
+		}
+		if _, _, err := parsePortRange(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePortRange interpreta "n" o "n-m"
+func parsePortRange(s string) (int, int, error) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, ErrInvalidPort
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(endStr); err != nil {
+			return 0, 0, ErrInvalidPort
+		}
+	}
+	if start < 1 || end > 65535 || end < start {
+		return 0, 0, fmt.Errorf("%w: %s out of range", ErrInvalidPort, s)
+	}
+	return start, end, nil
+}
+
+// wildcardIP indica si la IP escucha en todas las interfaces
+func wildcardIP(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
+
+// publishedPort es un puerto de host ocupado por un servicio
+type publishedPort struct {
+	service string
+	hostIP  string
+}
+
+// portConflicts detecta dos servicios que publican el mismo puerto de host
+// (mismo protocolo, e IP igual o comodín) y devuelve un error por cada choque
+func (c *composeConfig) portConflicts() []error {
+	var errs []error
+	used := make(map[string][]publishedPort)
+
+	for _, s := range c.services {
+		for _, spec := range s.ports {
+			p, err := parsePort(spec)
+			if err != nil || p.hostStart == 0 {
+				continue
+			}
+
+			for port := p.hostStart; port <= p.hostEnd; port++ {
+				key := fmt.Sprintf("%d/%s", port, p.protocol)
+				for _, other := range used[key] {
+					if other.service == s.name {
+						continue
+					}
+					if other.hostIP == p.hostIP || wildcardIP(other.hostIP) || wildcardIP(p.hostIP) {
+						errs = append(errs, &ValidationError{
+							Service: s.name,
+							Field:   "ports",
+							Err:     fmt.Errorf("%w: %q uses host port %s, already published by service %q", ErrPortConflict, spec, key, other.service),
+						})
+						break
+					}
+				}
+				used[key] = append(used[key], publishedPort{service: s.name, hostIP: p.hostIP})
+			}
+		}
+	}
+	return errs
+}
+
+// CheckHostPorts intenta abrir cada puerto publicado en el host y devuelve un
+// error por cada uno que ya esté ocupado, indicando el servicio que lo publica.
+// Sirve como comprobación previa a Up. Los puertos sctp no se comprueban
+func (c *composeConfig) CheckHostPorts() error {
+	return c.CheckHostPortsContext(context.Background())
+}
+
+// CheckHostPortsContext es CheckHostPorts con un contexto que se comprueba
+// antes de cada puerto, útil con rangos grandes
+func (c *composeConfig) CheckHostPortsContext(ctx context.Context) error {
+	var errs []error
+	for _, s := range c.services {
+		for _, spec := range s.ports {
+			p, err := parsePort(spec)
+			if err != nil || p.hostStart == 0 {
+				continue
+			}
+
+			for port := p.hostStart; port <= p.hostEnd; port++ {
+				if err := ctx.Err(); err != nil {
+					return errors.Join(append(errs, err)...)
+				}
+				if err := probePort(p.hostIP, port, p.protocol); err != nil {
+					errs = append(errs, &ValidationError{
+						Service: s.name,
+						Field:   "ports",
+						Err:     fmt.Errorf("%w: host port %d/%s for %q: %v", ErrPortInUse, port, p.protocol, spec, err),
+					})
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// probePort abre y cierra el puerto para saber si está libre
+func probePort(ip string, port int, protocol string) error {
+	addr := net.JoinHostPort(ip, strconv.Itoa(port))
+	switch protocol {
+	case "tcp":
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		return l.Close()
+	case "udp":
+		l, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return err
+		}
+		return l.Close()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package compose_test
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestAddPortValidation(t *testing.T) {
+	valid := [][2]string{
+		{"8080", "80"},
+		{"127.0.0.1:8080", "80"},
+		{"[::1]:8080", "80"},
+		{"8000-8010", "8000-8010"},
+		{"5353", "53/udp"},
+		{"", "9000"},
+		{"8000-8010", "80"},
+		{compose.Var("PORT"), "80"},
+		{compose.VarDefault("PORT", "8080"), "80"},
+		{"127.0.0.1:" + compose.Var("PORT"), "80/udp"},
+		{"8080", compose.Var("APP_PORT")},
+	}
+	for _, p := range valid {
+		if err := compose.NewService("x").AddPort(p[0], p[1]).Err(); err != nil {
+			t.Errorf("%s:%s rechazado: %v", p[0], p[1], err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"70000", "80"},
+		{"8080", "0"},
+		{"8000-8010", "9000-9001"},
+		{"8010-8000", "80"},
+		{"8080", "80-81"},
+		{"8080", "80/http"},
+		{compose.Var("PORT"), "80abc"},
+		{compose.Var("PORT"), "0"},
+		{compose.VarDefault("PORT", "8080"), "80/http"},
+		{"999.0.0.1:" + compose.Var("PORT"), "80"},
+	}
+	for _, p := range invalid {
+		if err := compose.NewService("x").AddPort(p[0], p[1]).Err(); !errors.Is(err, compose.ErrInvalidPort) {
+			t.Errorf("%s:%s debería rechazarse: %v", p[0], p[1], err)
+		}
+	}
+}
+
+func TestPortConflicts(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx").AddPort("8080", "80")
+	admin := *compose.NewService("admin").SetImage("nginx").AddPort("127.0.0.1:8080", "80")
+	dns := *compose.NewService("dns").SetImage("coredns").AddPort("8080", "8080/udp")
+	other := *compose.NewService("other").SetImage("nginx").AddPort("127.0.0.2:9090", "80")
+	local := *compose.NewService("local").SetImage("nginx").AddPort("127.0.0.1:9090", "80")
+
+	config, _ := compose.NewCompose("3.8", web, admin, dns, other, local)
+	err := config.Validate()
+	if !errors.Is(err, compose.ErrPortConflict) {
+		t.Fatalf("Se esperaba un conflicto de puertos: %v", err)
+	}
+	if !strings.Contains(err.Error(), `service "admin"`) || !strings.Contains(err.Error(), `published by service "web"`) {
+		t.Errorf("El error debe nombrar ambos servicios: %v", err)
+	}
+	if strings.Count(err.Error(), "port conflict") != 1 {
+		t.Errorf("Solo web y admin chocan (udp e IPs distintas no): %v", err)
+	}
+}
+
+func TestCheckHostPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	taken := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	freePort := strconv.Itoa(free.Addr().(*net.TCPAddr).Port)
+	free.Close()
+
+	web := *compose.NewService("web").SetImage("nginx").AddPort("127.0.0.1:"+taken, "80")
+	api := *compose.NewService("api").SetImage("myapi").AddPort("127.0.0.1:"+freePort, "80")
+
+	config, _ := compose.NewCompose("3.8", web, api)
+	err = config.CheckHostPorts()
+	if !errors.Is(err, compose.ErrPortInUse) {
+		t.Fatalf("Se esperaba un puerto ocupado: %v", err)
+	}
+	if !strings.Contains(err.Error(), `service "web"`) || strings.Contains(err.Error(), `service "api"`) {
+		t.Errorf("Solo web debe informarse: %v", err)
+	}
+}
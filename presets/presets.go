@@ -0,0 +1,75 @@
+// Package presets ofrece servicios predefinidos para imágenes comunes
+// (Postgres, MySQL, Redis, Nginx) con puertos, volúmenes, healthchecks y
+// variables de entorno razonables, listos para añadir a compose.NewCompose
+package presets
+
+import "github.com/cdvelop/compose"
+
+// Options personaliza los servicios predefinidos
+type Options struct {
+	Name     string // nombre del servicio, por defecto el de la imagen
+	HostPort string // puerto publicado en el host, por defecto el estándar de la imagen
+	DataDir  string // directorio del host para los datos, por defecto ./data/<Name>
+}
+
+// preset crea la base común de los servicios predefinidos
+func preset(image, version, defaultName, port, dataTarget string, opts []Options) *compose.Service {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Name == "" {
+		o.Name = defaultName
+	}
+	if o.HostPort == "" {
+		o.HostPort = port
+	}
+	if o.DataDir == "" {
+		o.DataDir = "./data/" + o.Name
+	}
+	if version == "" {
+		version = "latest"
+	}
+
+	s := compose.NewService(o.Name).
+		SetImage(image+":"+version).
+		AddPort(o.HostPort, port).
+		SetRestartPolicy(compose.RestartUnlessStopped)
+
+	if dataTarget != "" {
+		s.AddVolume(compose.Volume{Source: o.DataDir, Target: dataTarget})
+	}
+	return s
+}
+
+// Postgres devuelve un servicio PostgreSQL con datos persistentes, healthcheck
+// y POSTGRES_PASSWORD generado aleatoriamente en .env al guardar
+func Postgres(version string, opts ...Options) *compose.Service {
+	return preset("postgres", version, "postgres", "5432", "/var/lib/postgresql/data", opts).
+		AddEnvironment("POSTGRES_USER", "postgres").
+		AddEnvironment("POSTGRES_DB", "app").
+		AddSecretEnvironment("POSTGRES_PASSWORD").
+		SetHealthCheckConfig(compose.HealthCheckPostgres())
+}
+
+// MySQL devuelve un servicio MySQL con datos persistentes, healthcheck
+// y MYSQL_ROOT_PASSWORD generado aleatoriamente en .env al guardar
+func MySQL(version string, opts ...Options) *compose.Service {
+	return preset("mysql", version, "mysql", "3306", "/var/lib/mysql", opts).
+		AddEnvironment("MYSQL_DATABASE", "app").
+		AddSecretEnvironment("MYSQL_ROOT_PASSWORD").
+		SetHealthCheckConfig(compose.HealthCheckMySQL())
+}
+
+// Redis devuelve un servicio Redis con persistencia AOF y healthcheck
+func Redis(version string, opts ...Options) *compose.Service {
+	return preset("redis", version, "redis", "6379", "/data", opts).
+		SetCommand("redis-server", "--appendonly", "yes").
+		SetHealthCheckConfig(compose.HealthCheckRedis())
+}
+
+// Nginx devuelve un servicio Nginx publicado en el puerto 80
+func Nginx(version string, opts ...Options) *compose.Service {
+	return preset("nginx", version, "nginx", "80", "", opts).
+		SetHealthCheckConfig(compose.HealthCheckHTTP("http://localhost/"))
+}
@@ -0,0 +1,89 @@
+package presets_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"github.com/cdvelop/compose/presets"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPresets(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *presets.Postgres("16")
+	cache := *presets.Redis("7", presets.Options{Name: "cache", HostPort: "16379"})
+	mysql := *presets.MySQL("8")
+	proxy := *presets.Nginx("")
+
+	config, _ := compose.NewCompose("3.8", db, cache, mysql, proxy)
+	config.SetEnvStrictness(compose.EnvCheckStrict)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Image       string            `yaml:"image"`
+			Ports       []string          `yaml:"ports"`
+			Volumes     []string          `yaml:"volumes"`
+			Environment map[string]string `yaml:"environment"`
+			Healthcheck map[string]any    `yaml:"healthcheck"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	pg := result.Services["postgres"]
+	if pg.Image != "postgres:16" || pg.Ports[0] != "5432:5432" || pg.Volumes[0] != "./data/postgres:/var/lib/postgresql/data" {
+		t.Errorf("Servicio postgres incorrecto: %+v", pg)
+	}
+	if pg.Environment["POSTGRES_PASSWORD"] != "${POSTGRES_PASSWORD}" || pg.Healthcheck == nil {
+		t.Errorf("Entorno o healthcheck de postgres incorrecto: %+v", pg)
+	}
+
+	redis := result.Services["cache"]
+	if redis.Image != "redis:7" || redis.Ports[0] != "16379:6379" || redis.Volumes[0] != "./data/cache:/data" {
+		t.Errorf("Servicio redis incorrecto: %+v", redis)
+	}
+
+	if result.Services["nginx"].Image != "nginx:latest" {
+		t.Errorf("Imagen nginx incorrecta: %q", result.Services["nginx"].Image)
+	}
+
+	// construir los presets no escribe nada; los secretos se generan al guardar
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Fatal("Construir los presets no debe escribir el .env")
+	}
+	if _, err := config.Save(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	env, err := compose.ListEnvFromFile()
+	if err != nil || strings.Join(env, ",") != "POSTGRES_USER,POSTGRES_DB,POSTGRES_PASSWORD,MYSQL_DATABASE,MYSQL_ROOT_PASSWORD" {
+		t.Errorf("Variables del .env incorrectas: %v %v", env, err)
+	}
+}
+
+// chdir cambia el directorio de trabajo durante el test y lo restaura al terminar
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Error leyendo el directorio de trabajo: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Error cambiando de directorio: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("Error restaurando el directorio de trabajo: %v", err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// validProjectName es el formato de nombre de proyecto aceptado por docker compose
+var validProjectName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// SetProjectName fija el nombre del proyecto: se emite como clave "name:" en el
+// YAML y se pasa con -p a los comandos docker compose
+func (c *composeConfig) SetProjectName(name string) *composeConfig {
+	c.projectName = name
+	return c
+}
+
+// SetPrefixNames antepone el nombre del proyecto a container_name para que dos
+// stacks en el mismo host no choquen. Volúmenes y redes no externas ya quedan
+// aislados por docker compose, que les antepone el proyecto automáticamente
+func (c *composeConfig) SetPrefixNames(prefix bool) *composeConfig {
+	c.prefixNames = prefix
+	return c
+}
+
+// containerNameFor devuelve el container_name a emitir para el servicio
+func (c *composeConfig) containerNameFor(s service) string {
+	if s.scaled() {
+		return ""
+	}
+	if c.prefixNames && s.containerName != "" {
+		return c.project() + "-" + s.containerName
+	}
+	return s.containerName
+}
+
+// validateProjectName revisa el nombre fijado con SetProjectName
+func (c *composeConfig) validateProjectName() error {
+	if c.projectName != "" && !validProjectName.MatchString(c.projectName) {
+		return fmt.Errorf("invalid project name %q: must match [a-z0-9][a-z0-9_-]*", c.projectName)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestProjectName(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx")
+
+	config, _ := compose.NewCompose("3.8", web)
+	config.SetProjectName("shop").SetPrefixNames(true)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	for _, want := range []string{"\nname: \"shop\"\n", `container_name: "shop-web"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Falta %q en:\n%s", want, data)
+		}
+	}
+
+	config.SetProjectName("My Shop")
+	if _, err := config.Bytes(); err == nil {
+		t.Error("Se esperaba un error por nombre de proyecto inválido")
+	}
+}
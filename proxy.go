@@ -0,0 +1,207 @@
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReverseProxy es el proxy inverso que sintetiza WithReverseProxy
+type ReverseProxy string
+
+const (
+	ProxyNginx   ReverseProxy = "nginx"
+	ProxyCaddy   ReverseProxy = "caddy"
+	ProxyTraefik ReverseProxy = "traefik"
+)
+
+// proxyImages son las imágenes por defecto de cada proxy
+var proxyImages = map[ReverseProxy]string{
+	ProxyNginx:   "nginx:1.27-alpine",
+	ProxyCaddy:   "caddy:2.8-alpine",
+	ProxyTraefik: "traefik:v3.1",
+}
+
+// ReverseProxyOptions personaliza el servicio generado por WithReverseProxy
+type ReverseProxyOptions struct {
+	Name      string // nombre del servicio, por defecto "proxy"
+	Image     string // imagen, por defecto una versión estable del proxy elegido
+	HTTPPort  string // puerto HTTP del host, por defecto "80"
+	HTTPSPort string // puerto HTTPS del host, por defecto "443" (caddy y traefik)
+	ACMEEmail string // email de Let's Encrypt; caddy lo usa como contacto y traefik activa TLS con él
+	DataDir   string // directorio del host para certificados, por defecto ./data/<Name>
+}
+
+// proxyRoute es un servicio publicado por el proxy
+type proxyRoute struct {
+	service string
+	host    string
+	port    int
+}
+
+// SetProxyHost establece el nombre de host con el que WithReverseProxy publica
+// el servicio, por defecto "<servicio>.localhost"
+func (s *service) SetProxyHost(host string) *service {
+	if host == "" || strings.ContainsAny(host, " /`{}") {
+		s.errors = append(s.errors, invalidField(s.name, "proxy_host", host))
+		return s
+	}
+	s.proxyHost = host
+	return s
+}
+
+// WithReverseProxy añade un servicio de proxy inverso que publica en HTTP/HTTPS
+// cada servicio con puertos expose, usando el primer puerto TCP y el host de
+// SetProxyHost. Para nginx y caddy genera su archivo de configuración como
+// config embebido; para traefik añade a cada servicio las etiquetas de
+// ExposeViaTraefik. Caddy obtiene certificados automáticamente y traefik lo hace
+// cuando se indica ACMEEmail; nginx queda en HTTP. Los servicios añadidos
+// después de la llamada no se publican
+func (c *composeConfig) WithReverseProxy(kind ReverseProxy, opts ...ReverseProxyOptions) *composeConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var o ReverseProxyOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Name == "" {
+		o.Name = "proxy"
+	}
+	if o.Image == "" {
+		o.Image = proxyImages[kind]
+	}
+	if o.HTTPPort == "" {
+		o.HTTPPort = "80"
+	}
+	if o.HTTPSPort == "" {
+		o.HTTPSPort = "443"
+	}
+	if o.DataDir == "" {
+		o.DataDir = "./data/" + o.Name
+	}
+
+	proxy := NewService(o.Name).SetRestartPolicy(RestartUnlessStopped)
+	if _, known := proxyImages[kind]; !known {
+		proxy.errors = append(proxy.errors, invalidField(o.Name, "reverse_proxy", string(kind)))
+		c.services = append(c.services, cloneService(*proxy))
+		return c
+	}
+	proxy.SetImage(o.Image).AddPort(o.HTTPPort, "80")
+
+	routes := c.proxyRoutes(o.Name)
+	if len(routes) == 0 {
+		proxy.errors = append(proxy.errors, fmt.Errorf("service %q: reverse proxy has no service with expose ports to publish", o.Name))
+	}
+
+	switch kind {
+	case ProxyNginx:
+		proxy.AddConfig(InlineConfig{Name: o.Name + "-nginx", Target: "/etc/nginx/conf.d/default.conf", Content: nginxProxyConfig(routes)})
+	case ProxyCaddy:
+		proxy.AddPort(o.HTTPSPort, "443").
+			AddVolume(Volume{Source: o.DataDir, Target: "/data"}).
+			AddConfig(InlineConfig{Name: o.Name + "-caddy", Target: "/etc/caddy/Caddyfile", Content: caddyProxyConfig(routes, o.ACMEEmail)})
+	case ProxyTraefik:
+		c.traefikProxy(proxy, routes, o)
+	}
+
+	if kind != ProxyTraefik {
+		for _, r := range routes {
+			proxy.serviceDependencies = appendUnique(proxy.serviceDependencies, []string{r.service})
+		}
+	}
+
+	c.services = append(c.services, cloneService(*proxy))
+	return c
+}
+
+// proxyRoutes reúne los servicios con un puerto TCP en expose
+func (c *composeConfig) proxyRoutes(proxyName string) []proxyRoute {
+	var routes []proxyRoute
+	for _, s := range c.services {
+		if s.name == proxyName {
+			continue
+		}
+		for _, expose := range s.expose {
+			port, proto, _ := strings.Cut(expose, "/")
+			n, err := strconv.Atoi(port)
+			if err != nil || (proto != "" && proto != "tcp") {
+				continue
+			}
+			host := s.proxyHost
+			if host == "" {
+				host = s.name + ".localhost"
+			}
+			routes = append(routes, proxyRoute{service: s.name, host: host, port: n})
+			break
+		}
+	}
+	return routes
+}
+
+// nginxProxyConfig genera un server por ruta. Los $ de nginx se escapan para
+// que docker compose no los interpole
+func nginxProxyConfig(routes []proxyRoute) string {
+	var b strings.Builder
+	for i, r := range routes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "server {\n")
+		fmt.Fprintf(&b, "    listen 80;\n")
+		fmt.Fprintf(&b, "    server_name %s;\n\n", r.host)
+		fmt.Fprintf(&b, "    location / {\n")
+		fmt.Fprintf(&b, "        proxy_pass http://%s:%d;\n", r.service, r.port)
+		b.WriteString(Literal("        proxy_set_header Host $host;\n"))
+		b.WriteString(Literal("        proxy_set_header X-Real-IP $remote_addr;\n"))
+		b.WriteString(Literal("        proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n"))
+		b.WriteString(Literal("        proxy_set_header X-Forwarded-Proto $scheme;\n"))
+		fmt.Fprintf(&b, "    }\n")
+		fmt.Fprintf(&b, "}\n")
+	}
+	return b.String()
+}
+
+// caddyProxyConfig genera un sitio por ruta; caddy activa HTTPS automáticamente
+func caddyProxyConfig(routes []proxyRoute, email string) string {
+	var b strings.Builder
+	if email != "" {
+		fmt.Fprintf(&b, "{\n\temail %s\n}\n\n", email)
+	}
+	for i, r := range routes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s {\n\treverse_proxy %s:%d\n}\n", r.host, r.service, r.port)
+	}
+	return b.String()
+}
+
+// traefikProxy configura traefik con el proveedor docker y etiqueta cada ruta
+func (c *composeConfig) traefikProxy(proxy *service, routes []proxyRoute, o ReverseProxyOptions) {
+	proxy.SetCommand(
+		"--providers.docker=true",
+		"--providers.docker.exposedbydefault=false",
+		"--entrypoints.web.address=:80",
+	).
+		AddVolume(Volume{Source: "/var/run/docker.sock", Target: "/var/run/docker.sock:ro"}).
+		AttachNetwork(defaultTraefikNetwork)
+
+	var traefikOpts []TraefikOption
+	if o.ACMEEmail != "" {
+		proxy.command = append(proxy.command,
+			"--entrypoints.websecure.address=:443",
+			"--entrypoints.web.http.redirections.entrypoint.to=websecure",
+			"--certificatesresolvers.letsencrypt.acme.email="+o.ACMEEmail,
+			"--certificatesresolvers.letsencrypt.acme.storage=/letsencrypt/acme.json",
+			"--certificatesresolvers.letsencrypt.acme.httpchallenge.entrypoint=web",
+		)
+		proxy.AddPort(o.HTTPSPort, "443").
+			AddVolume(Volume{Source: o.DataDir, Target: "/letsencrypt"})
+		traefikOpts = append(traefikOpts, TraefikTLS("letsencrypt"))
+	}
+
+	for _, r := range routes {
+		c.serviceByName(r.service).ExposeViaTraefik(r.host, r.port, traefikOpts...)
+	}
+}
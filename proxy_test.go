@@ -0,0 +1,115 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithReverseProxy(t *testing.T) {
+	type proxyYAML struct {
+		Services map[string]struct {
+			Image     string            `yaml:"image"`
+			Ports     []string          `yaml:"ports"`
+			Command   []string          `yaml:"command"`
+			DependsOn []string          `yaml:"depends_on"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"services"`
+		Configs map[string]struct {
+			Content string `yaml:"content"`
+		} `yaml:"configs"`
+		Networks map[string]struct {
+			Name string `yaml:"name"`
+		} `yaml:"networks"`
+	}
+
+	build := func(t *testing.T, kind compose.ReverseProxy, opts ...compose.ReverseProxyOptions) (proxyYAML, []byte) {
+		t.Helper()
+		web := *compose.NewService("web").SetImage("web:1.0").Expose("3000")
+		api := *compose.NewService("api").SetImage("api:1.0").Expose("53/udp", "8080").SetProxyHost("api.example.com")
+		db := *compose.NewService("db").SetImage("postgres:16")
+
+		config, _ := compose.NewCompose("3.8", web, api, db)
+		config.WithReverseProxy(kind, opts...)
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		var result proxyYAML
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Error parseando YAML: %v", err)
+		}
+		if err := config.Validate(compose.SchemaStrict); err != nil {
+			t.Errorf("Se esperaba un archivo válido según el schema: %v", err)
+		}
+		return result, data
+	}
+
+	t.Run("Nginx", func(t *testing.T) {
+		result, data := build(t, compose.ProxyNginx)
+
+		proxy := result.Services["proxy"]
+		if proxy.Image != "nginx:1.27-alpine" || strings.Join(proxy.Ports, ",") != "80:80" {
+			t.Errorf("Servicio proxy incorrecto: %+v", proxy)
+		}
+		if strings.Join(proxy.DependsOn, ",") != "web,api" {
+			t.Errorf("depends_on incorrecto: %q", proxy.DependsOn)
+		}
+		conf := result.Configs["proxy-nginx"].Content
+		for _, want := range []string{"server_name web.localhost;", "proxy_pass http://web:3000;", "server_name api.example.com;", "proxy_pass http://api:8080;", "proxy_set_header Host $$host;"} {
+			if !strings.Contains(conf, want) {
+				t.Errorf("Falta %q en la configuración de nginx:\n%s", want, data)
+			}
+		}
+		if strings.Contains(conf, "db") {
+			t.Errorf("db no expone puertos y no debería publicarse:\n%s", conf)
+		}
+	})
+
+	t.Run("Caddy con HTTPS", func(t *testing.T) {
+		result, _ := build(t, compose.ProxyCaddy, compose.ReverseProxyOptions{ACMEEmail: "ops@example.com"})
+
+		proxy := result.Services["proxy"]
+		if strings.Join(proxy.Ports, ",") != "80:80,443:443" {
+			t.Errorf("Puertos incorrectos: %q", proxy.Ports)
+		}
+		want := "{\n\temail ops@example.com\n}\n\nweb.localhost {\n\treverse_proxy web:3000\n}\n\napi.example.com {\n\treverse_proxy api:8080\n}\n"
+		if got := result.Configs["proxy-caddy"].Content; got != want {
+			t.Errorf("Caddyfile incorrecto:\n%s", got)
+		}
+	})
+
+	t.Run("Traefik con etiquetas", func(t *testing.T) {
+		result, _ := build(t, compose.ProxyTraefik, compose.ReverseProxyOptions{Name: "ingress", ACMEEmail: "ops@example.com"})
+
+		proxy := result.Services["ingress"]
+		if !strings.Contains(strings.Join(proxy.Command, " "), "--certificatesresolvers.letsencrypt.acme.email=ops@example.com") {
+			t.Errorf("Comando de traefik incorrecto: %q", proxy.Command)
+		}
+		labels := result.Services["api"].Labels
+		if labels["traefik.http.routers.api.rule"] != "Host(`api.example.com`)" ||
+			labels["traefik.http.routers.api.tls.certresolver"] != "letsencrypt" ||
+			labels["traefik.http.services.api.loadbalancer.server.port"] != "8080" {
+			t.Errorf("Etiquetas incorrectas: %v", labels)
+		}
+		if result.Networks["traefik"].Name != "traefik" {
+			t.Errorf("La red de traefik debe llamarse como indica traefik.docker.network: %v", result.Networks)
+		}
+		if len(result.Services["db"].Labels) != 0 {
+			t.Errorf("db no debería tener etiquetas: %v", result.Services["db"].Labels)
+		}
+	})
+
+	t.Run("Sin servicios que publicar", func(t *testing.T) {
+		db := *compose.NewService("db").SetImage("postgres:16")
+		config, _ := compose.NewCompose("3.8", db)
+		config.WithReverseProxy(compose.ProxyCaddy)
+
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "no service with expose ports") {
+			t.Errorf("Se esperaba un error sin rutas: %v", err)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// ImageOption configura el comportamiento de Pull y Build
+type ImageOption func(*imageOptions)
+
+type imageOptions struct {
+	services []string
+	parallel bool
+	noCache  bool
+	progress func(line string)
+}
+
+// WithServices limita Pull o Build a los servicios indicados
+func WithServices(services ...string) ImageOption {
+	return func(o *imageOptions) {
+		o.services = append(o.services, services...)
+	}
+}
+
+// WithParallel añade --parallel para descargar o construir las imágenes en paralelo
+func WithParallel() ImageOption {
+	return func(o *imageOptions) {
+		o.parallel = true
+	}
+}
+
+// WithNoCache construye las imágenes sin usar la caché (--no-cache). Pull lo ignora
+func WithNoCache() ImageOption {
+	return func(o *imageOptions) {
+		o.noCache = true
+	}
+}
+
+// WithProgress llama a fn con cada línea de progreso que docker compose
+// escribe mientras descarga o construye, por ejemplo para mostrarla en un log de CI
+func WithProgress(fn func(line string)) ImageOption {
+	return func(o *imageOptions) {
+		o.progress = fn
+	}
+}
+
+// Pull descarga las imágenes del stack con "docker compose pull", para
+// tenerlas listas antes de Up en pipelines de CI
+func (c *composeConfig) Pull(ctx context.Context, opts ...ImageOption) error {
+	o := imageOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	args := []string{"pull"}
+	if o.parallel {
+		args = append(args, "--parallel")
+	}
+	return c.runImageCommand(ctx, o, args)
+}
+
+// Build construye las imágenes de los servicios con build mediante
+// "docker compose build"
+func (c *composeConfig) Build(ctx context.Context, opts ...ImageOption) error {
+	o := imageOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	args := []string{"build"}
+	if o.parallel {
+		args = append(args, "--parallel")
+	}
+	if o.noCache {
+		args = append(args, "--no-cache")
+	}
+	return c.runImageCommand(ctx, o, args)
+}
+
+// runImageCommand ejecuta pull o build sobre los servicios de o, enviando la
+// salida línea a línea al callback de progreso cuando se indicó uno
+func (c *composeConfig) runImageCommand(ctx context.Context, o imageOptions, args []string) error {
+	if o.progress == nil {
+		_, err := c.runCompose(ctx, append(args, o.services...)...)
+		return err
+	}
+
+	args = c.composeArgs(append(args, o.services...)...)
+	start := time.Now()
+	// cada salida tiene su propio búfer para que una línea incompleta de
+	// stdout no se mezcle con las de stderr
+	var mu sync.Mutex
+	stdout := &lineWriter{mu: &mu, fn: o.progress}
+	stderr := &lineWriter{mu: &mu, fn: o.progress}
+	err := streamDockerOutput(ctx, stdout, stderr, args...)
+	stderr.flush()
+	stdout.flush()
+	c.logCommand(args, start, err)
+	return err
+}
+
+// lineWriter es un io.Writer que entrega a fn cada línea completa recibida.
+// mu se comparte entre los writers de stdout y stderr para no llamar a fn en paralelo
+type lineWriter struct {
+	mu  *sync.Mutex
+	fn  func(line string)
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		if line := string(w.buf[:i]); line != "" {
+			w.fn(line)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush entrega la última línea si no terminaba en salto de línea
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.fn(string(w.buf))
+		w.buf = nil
+	}
+}
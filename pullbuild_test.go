@@ -0,0 +1,65 @@
+package compose_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestPullAndBuild(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	api := *compose.NewService("api").SetImage("api:1.0")
+	config, _ := compose.NewCompose("3.8", db, api)
+	config.SetProjectName("shop")
+
+	t.Run("Pull", func(t *testing.T) {
+		log := fakeDocker(t, "")
+
+		if err := config.Pull(context.Background(), compose.WithParallel()); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if calls := dockerCalls(t, log); calls[0] != "compose -f docker-compose.yml -p shop pull --parallel" {
+			t.Errorf("Comando incorrecto: %q", calls[0])
+		}
+	})
+
+	t.Run("Build con opciones y servicios", func(t *testing.T) {
+		log := fakeDocker(t, "")
+
+		err := config.Build(context.Background(), compose.WithNoCache(), compose.WithServices("api"))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if calls := dockerCalls(t, log); calls[0] != "compose -f docker-compose.yml -p shop build --no-cache api" {
+			t.Errorf("Comando incorrecto: %q", calls[0])
+		}
+	})
+
+	t.Run("Progreso de stdout y stderr", func(t *testing.T) {
+		fakeDocker(t, `echo " db Pulling" >&2; echo " db Pulled" >&2; printf "done"`)
+
+		var lines []string
+		err := config.Pull(context.Background(), compose.WithProgress(func(line string) {
+			lines = append(lines, line)
+		}))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		want := []string{" db Pulling", " db Pulled", "done"}
+		if !reflect.DeepEqual(lines, want) {
+			t.Errorf("Progreso incorrecto: %q", lines)
+		}
+	})
+
+	t.Run("Error incluye stderr", func(t *testing.T) {
+		fakeDocker(t, `echo "pull access denied" >&2; exit 1`)
+
+		err := config.Pull(context.Background(), compose.WithProgress(func(string) {}))
+		if err == nil || !strings.Contains(err.Error(), "pull access denied") {
+			t.Errorf("Se esperaba el error de docker, se obtuvo %v", err)
+		}
+	})
+}
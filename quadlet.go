@@ -0,0 +1,373 @@
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Quadlet convierte la configuración en unidades Quadlet de podman-systemd:
+// un .container por servicio, un .network por red y un .volume por volumen con
+// nombre, para desplegar el mismo stack con podman sin docker. Las claves del
+// mapa son los nombres de archivo, prefijados con el nombre del proyecto. Las
+// referencias ${VAR} se resuelven con el .env porque podman no las interpola
+func (c *composeConfig) Quadlet() (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("configuración inválida: %w", err)
+	}
+
+	env, err := c.projectEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	q := quadletExport{config: c, project: c.project(), env: env, files: make(map[string][]byte)}
+	for _, s := range c.orderedServices() {
+		if err := q.container(s); err != nil {
+			return nil, err
+		}
+	}
+	q.networkUnits()
+	q.volumeUnits()
+	return q.files, nil
+}
+
+// SaveQuadlet escribe las unidades de Quadlet en dir, por ejemplo
+// ~/.config/containers/systemd para podman sin root, y devuelve las rutas escritas
+func (c *composeConfig) SaveQuadlet(dir string) ([]string, error) {
+	return c.SaveQuadletContext(context.Background(), dir)
+}
+
+// SaveQuadletContext es SaveQuadlet con un contexto que se comprueba antes de
+// escribir cada unidad. Tras validar la configuración escribe el .env de los
+// servicios, del que se resuelven las referencias ${VAR}. Los .container
+// llevan esos valores en Environment=, así que se escriben con el modo y el
+// dueño del .env (0600 por defecto)
+func (c *composeConfig) SaveQuadletContext(ctx context.Context, dir string) ([]string, error) {
+	// la primera generación solo valida: las referencias al .env aún sin
+	// escribir quedan vacías
+	if _, err := c.Quadlet(); err != nil {
+		return nil, err
+	}
+	if err := c.resolveDeferredEnv(); err != nil {
+		return nil, err
+	}
+
+	files, err := c.Quadlet()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.files.mkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	envPerm, envOwner := c.files.envFileAttrs()
+	var written []string
+	for _, name := range sortedKeys(files) {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, name)
+		perm, owner := defaultComposeFileMode, []fileOwner(nil)
+		if strings.HasSuffix(name, ".container") {
+			perm, owner = envPerm, envOwner
+		}
+		if err := c.files.writeFile(path, files[name], perm, owner...); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// quadletExport acumula las unidades generadas y las redes y volúmenes usados
+type quadletExport struct {
+	config   *composeConfig
+	project  string
+	env      map[string]string // variables del .env para resolver ${VAR}
+	files    map[string][]byte
+	networks map[string]bool
+	volumes  map[string]bool
+}
+
+// unitName devuelve el nombre de la unidad de un servicio, red o volumen
+func (q *quadletExport) unitName(name string) string {
+	return q.project + "-" + name
+}
+
+// container genera el .container de un servicio
+func (q *quadletExport) container(s service) error {
+	if s.image == "" {
+		return fmt.Errorf("service %q: quadlet export requires an image, build it first", s.name)
+	}
+	if s.scaled() {
+		return fmt.Errorf("service %q: quadlet export does not support scale", s.name)
+	}
+
+	var errs []error
+	interpolate := func(value string) string {
+		out, err := interpolateEnv(value, q.env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %q: %w", s.name, err))
+		}
+		return out
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s service of %s\n", s.name, q.project)
+	for _, dep := range s.serviceDependencies {
+		fmt.Fprintf(&b, "Requires=%s.service\n", q.unitName(dep))
+		fmt.Fprintf(&b, "After=%s.service\n", q.unitName(dep))
+	}
+
+	b.WriteString("\n[Container]\n")
+	fmt.Fprintf(&b, "Image=%s\n", systemdEscape(interpolate(s.image)))
+	if name := q.config.containerNameFor(s); name != "" {
+		fmt.Fprintf(&b, "ContainerName=%s\n", systemdEscape(name))
+	}
+	for _, port := range s.ports {
+		fmt.Fprintf(&b, "PublishPort=%s\n", systemdEscape(interpolate(port)))
+	}
+	for _, port := range s.expose {
+		fmt.Fprintf(&b, "ExposeHostPort=%s\n", port)
+	}
+	for _, key := range sortedKeys(s.labels) {
+		fmt.Fprintf(&b, "Label=%s\n", systemdQuote(key+"="+interpolate(s.labels[key])))
+	}
+	for _, key := range sortedKeys(s.environment) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(key+"="+interpolate(s.environment[key])))
+	}
+	for _, vol := range s.volumes {
+		fmt.Fprintf(&b, "Volume=%s\n", systemdQuote(q.volume(interpolate(vol.Source), vol.Target)))
+	}
+	for _, n := range q.serviceNetworks(s) {
+		fmt.Fprintf(&b, "Network=%s.network\n", q.unitName(n.name))
+		for _, alias := range n.Aliases {
+			fmt.Fprintf(&b, "NetworkAlias=%s\n", alias)
+		}
+	}
+	if s.networkMode != "" {
+		fmt.Fprintf(&b, "Network=%s\n", s.networkMode)
+	}
+	for _, host := range s.extraHosts {
+		fmt.Fprintf(&b, "AddHost=%s\n", host)
+	}
+	for _, server := range s.dns {
+		fmt.Fprintf(&b, "DNS=%s\n", server)
+	}
+	for _, domain := range s.dnsSearch {
+		fmt.Fprintf(&b, "DNSSearch=%s\n", domain)
+	}
+	for _, c := range s.capAdd {
+		fmt.Fprintf(&b, "AddCapability=%s\n", c)
+	}
+	for _, c := range s.capDrop {
+		fmt.Fprintf(&b, "DropCapability=%s\n", c)
+	}
+	if s.readOnly {
+		b.WriteString("ReadOnly=true\n")
+	}
+	for _, mount := range s.tmpfs {
+		fmt.Fprintf(&b, "Tmpfs=%s\n", mount)
+	}
+	for _, u := range s.ulimits {
+		fmt.Fprintf(&b, "Ulimit=%s=%d:%d\n", u.name, u.soft, u.hard)
+	}
+	for _, kv := range s.sysctls {
+		fmt.Fprintf(&b, "Sysctl=%s=%s\n", kv[0], systemdEscape(kv[1]))
+	}
+	for _, device := range s.devices {
+		fmt.Fprintf(&b, "AddDevice=%s\n", device)
+	}
+	for _, r := range s.deviceReservations {
+		if r.Driver == "nvidia" {
+			// podman expone las GPU con CDI
+			b.WriteString("AddDevice=nvidia.com/gpu=all\n")
+		}
+	}
+	for _, key := range []string{"user", "working_dir", "hostname", "entrypoint"} {
+		if value, ok := rawFieldValue(s.extensions, key); ok {
+			fmt.Fprintf(&b, "%s=%s\n", quadletRawKeys[key], systemdEscape(fmt.Sprint(value)))
+		}
+	}
+	if hc := s.healthCheck; hc != nil {
+		if cmd := healthCommand(hc.Test); cmd != "" {
+			fmt.Fprintf(&b, "HealthCmd=%s\n", systemdEscape(cmd))
+		}
+		if hc.Interval != "" {
+			fmt.Fprintf(&b, "HealthInterval=%s\n", hc.Interval)
+		}
+		if hc.Timeout != "" {
+			fmt.Fprintf(&b, "HealthTimeout=%s\n", hc.Timeout)
+		}
+		if hc.Retries > 0 {
+			fmt.Fprintf(&b, "HealthRetries=%d\n", hc.Retries)
+		}
+		if hc.StartPeriod != "" {
+			fmt.Fprintf(&b, "HealthStartPeriod=%s\n", hc.StartPeriod)
+		}
+	}
+	if s.privileged {
+		b.WriteString("PodmanArgs=--privileged\n")
+	}
+	if s.shmSize != "" {
+		fmt.Fprintf(&b, "PodmanArgs=--shm-size=%s\n", s.shmSize)
+	}
+	for _, opt := range s.securityOpt {
+		fmt.Fprintf(&b, "PodmanArgs=%s\n", systemdQuote("--security-opt="+opt))
+	}
+	if len(s.command) > 0 {
+		args := make([]string, len(s.command))
+		for i, arg := range s.command {
+			args[i] = systemdQuote(strings.ReplaceAll(interpolate(arg), "$", "$$"))
+		}
+		fmt.Fprintf(&b, "Exec=%s\n", strings.Join(args, " "))
+	}
+
+	b.WriteString("\n[Service]\n")
+	if restart := quadletRestart(s.restartPolicy); restart != "" {
+		fmt.Fprintf(&b, "Restart=%s\n", restart)
+	}
+
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	q.files[q.unitName(s.name)+".container"] = []byte(b.String())
+	return nil
+}
+
+// quadletRawKeys traduce los campos añadidos con SetRaw a claves de [Container]
+var quadletRawKeys = map[string]string{
+	"user":        "User",
+	"working_dir": "WorkingDir",
+	"hostname":    "HostName",
+	"entrypoint":  "Entrypoint",
+}
+
+// serviceNetworks devuelve las redes del servicio; sin redes propias ni
+// network_mode se usa la red por defecto del proyecto, como en docker compose
+func (q *quadletExport) serviceNetworks(s service) []networkAttachment {
+	networks := s.networks
+	if len(networks) == 0 && s.networkMode == "" {
+		networks = []networkAttachment{{name: defaultNetwork}}
+	}
+	if q.networks == nil {
+		q.networks = make(map[string]bool)
+	}
+	for _, n := range networks {
+		q.networks[n.name] = true
+	}
+	return networks
+}
+
+// volume devuelve el valor de Volume=: los volúmenes con nombre apuntan a su
+// unidad .volume y las rutas del host se montan tal cual
+func (q *quadletExport) volume(source, target string) string {
+	if source == "" {
+		return target
+	}
+	if isHostPath(source) {
+		return source + ":" + target
+	}
+	if q.volumes == nil {
+		q.volumes = make(map[string]bool)
+	}
+	q.volumes[source] = true
+	return q.unitName(source) + ".volume:" + target
+}
+
+// networkUnits genera un .network por cada red usada
+func (q *quadletExport) networkUnits() {
+	names := make([]string, 0, len(q.networks))
+	for name := range q.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		config := q.config.networks[name]
+
+		var b strings.Builder
+		b.WriteString("[Network]\n")
+		if config.External {
+			fmt.Fprintf(&b, "NetworkName=%s\n", name)
+		} else {
+			fmt.Fprintf(&b, "NetworkName=%s_%s\n", q.project, name)
+		}
+		if config.Driver != "" {
+			fmt.Fprintf(&b, "Driver=%s\n", config.Driver)
+		}
+		for _, subnet := range config.Subnets {
+			fmt.Fprintf(&b, "Subnet=%s\n", subnet)
+		}
+		q.files[q.unitName(name)+".network"] = []byte(b.String())
+	}
+}
+
+// volumeUnits genera un .volume por cada volumen con nombre
+func (q *quadletExport) volumeUnits() {
+	names := make([]string, 0, len(q.volumes))
+	for name := range q.volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content := fmt.Sprintf("[Volume]\nVolumeName=%s_%s\n", q.project, name)
+		q.files[q.unitName(name)+".volume"] = []byte(content)
+	}
+}
+
+// quadletRestart traduce la política de reinicio de compose a la de systemd
+func quadletRestart(policy string) string {
+	switch {
+	case policy == RestartAlways || policy == RestartUnlessStopped:
+		return "always"
+	case strings.HasPrefix(policy, RestartOnFailure):
+		return "on-failure"
+	case policy == RestartNo:
+		return "no"
+	}
+	return ""
+}
+
+// healthCommand convierte el test de un healthcheck en el comando a ejecutar
+func healthCommand(test []string) string {
+	if len(test) == 0 || test[0] == "NONE" {
+		return ""
+	}
+	switch test[0] {
+	case "CMD-SHELL":
+		return strings.Join(test[1:], " ")
+	case "CMD":
+		// la forma exec se pasa como arreglo JSON para conservar los argumentos
+		data, _ := json.Marshal(test[1:])
+		return string(data)
+	}
+	return strings.Join(test, " ")
+}
+
+// systemdEscape escapa los especificadores "%" de systemd
+func systemdEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// systemdQuote entrecomilla s para systemd si contiene espacios o comillas
+func systemdQuote(s string) string {
+	s = systemdEscape(s)
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
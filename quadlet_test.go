@@ -0,0 +1,105 @@
+package compose_test
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestQuadlet(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *compose.NewService("db").SetImage("postgres:16").
+		AddEnvironment("POSTGRES_PASSWORD", "s3cr3t 100%").
+		AddVolume(compose.Volume{Source: "pgdata", Target: "/var/lib/postgresql/data"}).
+		SetHealthCheck([]string{"CMD", "pg_isready", "-U", "postgres"}, "10s", "5s", 5).
+		AttachNetwork("backend")
+	api := *compose.NewService("api").SetImage("ghcr.io/org/api:1.0").
+		AddPort("8080", "80").
+		AddVolume(compose.Volume{Source: "./config", Target: "/etc/api"}).
+		SetCommand("serve", "--motd", "hello world").
+		SetRestartPolicy(compose.RestartUnlessStopped).
+		DependsOn(db).
+		SetRaw("user", "1000")
+
+	config, _ := compose.NewCompose("3.8", db, api)
+	config.SetProjectName("shop")
+
+	files, err := config.Quadlet()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	want := "shop-api.container,shop-backend.network,shop-db.container,shop-default.network,shop-pgdata.volume"
+	sort.Strings(names)
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("Archivos incorrectos: %s", got)
+	}
+
+	apiUnit := string(files["shop-api.container"])
+	for _, line := range []string{
+		"Requires=shop-db.service",
+		"After=shop-db.service",
+		"Image=ghcr.io/org/api:1.0",
+		"PublishPort=8080:80",
+		"Volume=./config:/etc/api",
+		"Network=shop-default.network",
+		"User=1000",
+		`Exec=serve --motd "hello world"`,
+		"Restart=always",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(apiUnit, line+"\n") {
+			t.Errorf("Falta %q en api:\n%s", line, apiUnit)
+		}
+	}
+
+	dbUnit := string(files["shop-db.container"])
+	for _, line := range []string{
+		`Environment="POSTGRES_PASSWORD=s3cr3t 100%%"`,
+		"Volume=shop-pgdata.volume:/var/lib/postgresql/data",
+		"Network=shop-backend.network",
+		`HealthCmd=["pg_isready","-U","postgres"]`,
+		"HealthRetries=5",
+	} {
+		if !strings.Contains(dbUnit, line+"\n") {
+			t.Errorf("Falta %q en db:\n%s", line, dbUnit)
+		}
+	}
+
+	if got := string(files["shop-backend.network"]); got != "[Network]\nNetworkName=shop_backend\n" {
+		t.Errorf("Red incorrecta:\n%s", got)
+	}
+
+	dir := filepath.Join(t.TempDir(), "systemd")
+	written, err := config.SaveQuadlet(dir)
+	if err != nil || len(written) != len(files) {
+		t.Fatalf("SaveQuadlet: %v %v", written, err)
+	}
+	checkMode(t, filepath.Join(dir, "shop-db.container"), 0600)
+	checkMode(t, filepath.Join(dir, "shop-backend.network"), 0644)
+
+	built := *compose.NewService("built").SetBuild(".")
+	config, _ = compose.NewCompose("3.8", built)
+	if _, err := config.Quadlet(); err == nil {
+		t.Error("Se esperaba un error por servicio sin imagen")
+	}
+
+	secret := *compose.NewService("secret").SetBuild(".").AddSecretEnvironment("API_KEY", 16)
+	config, _ = compose.NewCompose("3.8", secret)
+	mem := compose.NewMemFS()
+	config.SetFS(mem)
+	if _, err := config.SaveQuadlet("systemd"); err == nil {
+		t.Error("Se esperaba un error por servicio sin imagen")
+	}
+	if files := mem.Files(); len(files) != 0 {
+		t.Errorf("No debía escribirse nada si la exportación falla: %v", files)
+	}
+}
@@ -0,0 +1,27 @@
+package compose
+
+import "fmt"
+
+// builderKeys son las claves de servicio que ya emite el builder; SetRaw no
+// puede usarlas porque el YAML quedaría con claves duplicadas
+var builderKeys = map[string]bool{
+	"build": true, "cap_add": true, "cap_drop": true, "command": true, "configs": true,
+	"container_name": true, "credential_spec": true, "depends_on": true, "deploy": true, "devices": true,
+	"develop": true, "dns": true, "dns_search": true, "environment": true, "expose": true,
+	"extra_hosts": true, "healthcheck": true, "image": true, "isolation": true, "labels": true, "network_mode": true,
+	"networks": true, "platform": true, "ports": true, "privileged": true,
+	"pull_policy": true, "read_only": true, "restart": true, "scale": true, "security_opt": true,
+	"shm_size": true, "sysctls": true, "tmpfs": true, "ulimits": true, "volumes": true,
+}
+
+// SetRaw añade bajo el servicio un campo del spec que el builder aún no cubre,
+// serializando value como YAML, por ejemplo SetRaw("stop_grace_period", "30s")
+// o SetRaw("logging", map[string]any{"driver": "json-file"})
+func (s *service) SetRaw(key string, value any) *service {
+	if builderKeys[key] {
+		s.errors = append(s.errors, fmt.Errorf("service %q: %s is managed by the builder, use its setter instead of SetRaw", s.name, key))
+		return s
+	}
+	s.extensions = setRawField(s.extensions, key, value)
+	return s
+}
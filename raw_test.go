@@ -0,0 +1,50 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetRaw(t *testing.T) {
+	web := *compose.NewService("web").
+		SetImage("nginx").
+		SetRaw("stop_grace_period", "30s").
+		SetRaw("logging", map[string]any{
+			"driver":  "json-file",
+			"options": map[string]string{"max-size": "10m"},
+		})
+
+	config, _ := compose.NewCompose("3.8", web)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			StopGracePeriod string `yaml:"stop_grace_period"`
+			Logging         struct {
+				Driver  string            `yaml:"driver"`
+				Options map[string]string `yaml:"options"`
+			} `yaml:"logging"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+
+	got := result.Services["web"]
+	if got.StopGracePeriod != "30s" || got.Logging.Driver != "json-file" || got.Logging.Options["max-size"] != "10m" {
+		t.Errorf("Campos raw incorrectos:\n%s", data)
+	}
+
+	for _, key := range []string{"image", "scale"} {
+		dup := *compose.NewService("web").SetImage("nginx").SetScale(2).SetRaw(key, "other")
+		config, _ = compose.NewCompose("3.8", dup)
+		if _, err := config.Bytes(); err == nil {
+			t.Errorf("%s: se esperaba un error por clave gestionada por el builder", key)
+		}
+	}
+}
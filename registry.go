@@ -0,0 +1,309 @@
+package compose
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// manifestMediaTypes son los tipos aceptados al consultar un manifiesto
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageRef es una referencia de imagen separada en sus partes
+type imageRef struct {
+	Registry   string // host del registro, por ejemplo registry-1.docker.io
+	Repository string // por ejemplo library/postgres
+	Tag        string
+	Digest     string
+}
+
+// parseImageRef separa una referencia [registro/]repositorio[:tag][@digest]
+func parseImageRef(image string) (imageRef, error) {
+	var ref imageRef
+	if image == "" {
+		return ref, errors.New("empty image reference")
+	}
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		ref.Repository = rest
+	} else {
+		ref.Registry = "docker.io"
+		ref.Repository = name
+	}
+
+	if ref.Registry == "docker.io" {
+		ref.Registry = "registry-1.docker.io"
+		if !strings.Contains(ref.Repository, "/") {
+			ref.Repository = "library/" + ref.Repository
+		}
+	}
+	return ref, nil
+}
+
+// reference devuelve el tag o el digest con el que se consulta el manifiesto
+func (r imageRef) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// registryCredentials son usuario y contraseña para un registro
+type registryCredentials struct {
+	username string
+	password string
+}
+
+// SetRegistryCredentials establece las credenciales usadas por VerifyImages para
+// un registro (por ejemplo "ghcr.io"). Si no se indican se leen de ~/.docker/config.json
+func (c *composeConfig) SetRegistryCredentials(registry, username, password string) *composeConfig {
+	if c.registryAuth == nil {
+		c.registryAuth = make(map[string]registryCredentials)
+	}
+	c.registryAuth[registryHost(registry)] = registryCredentials{username, password}
+	return c
+}
+
+// registryHost normaliza el nombre del registro de Docker Hub
+func registryHost(registry string) string {
+	switch registry {
+	case "docker.io", "index.docker.io", "https://index.docker.io/v1/":
+		return "registry-1.docker.io"
+	}
+	return registry
+}
+
+// credentialsFor busca credenciales explícitas o en la configuración del cliente docker
+func (c *composeConfig) credentialsFor(registry string) (registryCredentials, bool) {
+	if creds, ok := c.registryAuth[registry]; ok {
+		return creds, true
+	}
+
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return registryCredentials{}, false
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return registryCredentials{}, false
+	}
+
+	var dockerConfig struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return registryCredentials{}, false
+	}
+
+	for host, entry := range dockerConfig.Auths {
+		if registryHost(host) != registry {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			continue
+		}
+		user, pass, found := strings.Cut(string(decoded), ":")
+		if found {
+			return registryCredentials{user, pass}, true
+		}
+	}
+	return registryCredentials{}, false
+}
+
+// registryScheme usa http para registros locales, como hace docker
+func registryScheme(registry string) string {
+	host := registry
+	if h, _, found := strings.Cut(registry, ":"); found {
+		host = h
+	}
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http"
+	}
+	return "https"
+}
+
+// resolveImage consulta el manifiesto de la imagen con una petición HEAD y
+// devuelve su digest. Si el registro pide autenticación obtiene un token bearer
+func (c *composeConfig) resolveImage(ctx context.Context, image string) (string, error) {
+	ref, err := parseImageRef(image)
+	if err != nil {
+		return "", err
+	}
+
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", registryScheme(ref.Registry), ref.Registry, ref.Repository, ref.reference())
+
+	resp, err := c.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.registryToken(ctx, ref, resp.Header.Get("Www-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		if resp, err = c.headManifest(ctx, manifestURL, token); err != nil {
+			return "", err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("Docker-Content-Digest"), nil
+	case http.StatusNotFound:
+		return "", fmt.Errorf("image %s not found in %s", image, ref.Registry)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("image %s: access denied by %s", image, ref.Registry)
+	default:
+		return "", fmt.Errorf("image %s: unexpected registry response %s", image, resp.Status)
+	}
+}
+
+// headManifest hace la petición HEAD al manifiesto
+func (c *composeConfig) headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// registryToken obtiene un token siguiendo el desafío WWW-Authenticate: Bearer
+func (c *composeConfig) registryToken(ctx context.Context, ref imageRef, challenge string) (string, error) {
+	params, found := strings.CutPrefix(challenge, "Bearer ")
+	if !found {
+		return "", fmt.Errorf("registry %s: unsupported authentication %q", ref.Registry, challenge)
+	}
+
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		values[key] = strings.Trim(value, `"`)
+	}
+
+	query := url.Values{}
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, values["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if creds, ok := c.credentialsFor(ref.Registry); ok {
+		req.SetBasicAuth(creds.username, creds.password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s: token request failed: %s", ref.Registry, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("registry %s: invalid token response: %w", ref.Registry, err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// VerifyImagesOnSave hace que Save compruebe con VerifyImages que cada imagen
+// existe en su registro antes de escribir, por ejemplo para detectar postgres:41
+func VerifyImagesOnSave() SaveOption {
+	return func(o *saveOptions) {
+		o.verifyImages = true
+	}
+}
+
+// VerifyImages comprueba en su registro que cada imagen referenciada exista,
+// para detectar tags inexistentes o mal escritos antes de escribir o levantar nada.
+// Se omiten los servicios con build (su imagen se construye localmente) y las
+// imágenes con interpolación, que solo docker puede resolver
+func (c *composeConfig) VerifyImages(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	c.mu.Lock()
+	services := cloneServices(c.services)
+	c.mu.Unlock()
+
+	errs := make([]error, len(services))
+	checked := make(map[string]bool)
+	for i, s := range services {
+		if s.image == "" || s.build != "" || strings.Contains(s.image, "$") || checked[s.image] {
+			continue
+		}
+		checked[s.image] = true
+
+		wg.Add(1)
+		go func(i int, name, image string) {
+			defer wg.Done()
+			if _, err := c.resolveImage(ctx, image); err != nil {
+				errs[i] = fmt.Errorf("service %q: %w", name, err)
+			}
+		}(i, s.name, s.image)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
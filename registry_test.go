@@ -0,0 +1,147 @@
+package compose_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+// testDigest es el digest que devuelve fakeRegistry para team/app:1.0
+const testDigest = "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+// fakeRegistry simula un registro que exige token bearer y solo tiene team/app:1.0
+func fakeRegistry(t *testing.T) string {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "pw" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"token":"abc"}`))
+		case r.Header.Get("Authorization") != "Bearer abc":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/team/app/manifests/1.0":
+			w.Header().Set("Docker-Content-Digest", testDigest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestVerifyImages(t *testing.T) {
+	registry := fakeRegistry(t)
+
+	good := *compose.NewService("good").SetImage(registry + "/team/app:1.0")
+	typo := *compose.NewService("typo").SetImage(registry + "/team/app:9.9")
+	local := *compose.NewService("local").SetBuild(".")
+
+	config, _ := compose.NewCompose("3.8", good, typo, local)
+	config.SetRegistryCredentials(registry, "bot", "pw")
+
+	err := config.VerifyImages(context.Background())
+	if err == nil {
+		t.Fatal("Se esperaba un error por imagen inexistente")
+	}
+	if !strings.Contains(err.Error(), `service "typo"`) || strings.Contains(err.Error(), `service "good"`) {
+		t.Errorf("Error inesperado: %v", err)
+	}
+}
+
+func TestPinImages(t *testing.T) {
+	registry := fakeRegistry(t)
+
+	app := *compose.NewService("app").SetImage(registry + "/team/app:1.0")
+	tagged := *compose.NewService("tagged").SetImage("${APP_IMAGE}")
+
+	config, _ := compose.NewCompose("3.8", app, tagged)
+	config.SetRegistryCredentials(registry, "bot", "pw")
+
+	if err := config.PinImages(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), registry+"/team/app:1.0@"+testDigest) {
+		t.Errorf("La imagen no quedó fijada por digest:\n%s", data)
+	}
+	if !strings.Contains(string(data), "${APP_IMAGE}") {
+		t.Errorf("La imagen interpolada no debe modificarse:\n%s", data)
+	}
+}
+
+func TestImageReferenceValidation(t *testing.T) {
+	valid := []string{"nginx", "nginx:1.25-alpine", "ghcr.io/org/app:v1", "localhost:5000/app", "app@" + testDigest}
+	for _, image := range valid {
+		s := compose.NewService("s").SetImage(image)
+		if err := s.Err(); err != nil {
+			t.Errorf("%s: error inesperado: %v", image, err)
+		}
+	}
+
+	invalid := []string{"Nginx", "app:", "app:tag:extra", "app@sha256:123", "-app"}
+	for _, image := range invalid {
+		s := compose.NewService("s").SetImage(image)
+		if !errors.Is(s.Err(), compose.ErrInvalidValue) {
+			t.Errorf("%s: se esperaba ErrInvalidValue, se obtuvo %v", image, s.Err())
+		}
+	}
+
+	pinned := *compose.NewService("app").SetImage("nginx:1.25").SetImageDigest(testDigest)
+	config, _ := compose.NewCompose("3.8", pinned)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), `"nginx:1.25@`+testDigest+`"`) {
+		t.Errorf("SetImageDigest no aplicó el digest:\n%s", data)
+	}
+	if err := compose.NewService("app").SetImage("nginx").SetImageDigest("latest").Err(); err == nil {
+		t.Error("Se esperaba un error por digest inválido")
+	}
+}
+
+func TestVerifyImagesOnSave(t *testing.T) {
+	registry := fakeRegistry(t)
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	db := *compose.NewService("db").SetImage(registry + "/team/app:41")
+	built := *compose.NewService("built").SetImage("local/built:dev").SetBuild(".")
+
+	config, _ := compose.NewCompose("3.8", db, built)
+	config.SetRegistryCredentials(registry, "bot", "pw")
+
+	_, err := config.Save(context.Background(), compose.SaveTo(path), compose.VerifyImagesOnSave())
+	if err == nil || !strings.Contains(err.Error(), `service "db"`) {
+		t.Fatalf("Se esperaba un error por tag inexistente, se obtuvo %v", err)
+	}
+	if strings.Contains(err.Error(), `service "built"`) {
+		t.Errorf("Las imágenes construidas localmente no deben verificarse: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("No debe escribirse el archivo si una imagen no existe")
+	}
+
+	db.SetImage(registry + "/team/app:1.0")
+	config, _ = compose.NewCompose("3.8", db, built)
+	config.SetRegistryCredentials(registry, "bot", "pw")
+	if _, err := config.Save(context.Background(), compose.SaveTo(path), compose.VerifyImagesOnSave()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ulimit representa un límite de recursos del contenedor
+type ulimit struct {
+	name string
+	soft int
+	hard int
+}
+
+// SetUlimit establece un límite de recursos (ulimits), por ejemplo nofile o memlock.
+// Cuando soft y hard coinciden se emite la forma corta "nofile: 65535".
+// Usar -1 para ilimitado
+func (s *service) SetUlimit(name string, soft, hard int) *service {
+	for i, u := range s.ulimits {
+		if u.name == name {
+			s.ulimits[i] = ulimit{name, soft, hard}
+			return s
+		}
+	}
+	s.ulimits = append(s.ulimits, ulimit{name, soft, hard})
+	return s
+}
+
+// AddTmpfs monta un tmpfs en path con opciones opcionales de docker,
+// por ejemplo AddTmpfs("/run", "size=64m", "mode=1777")
+func (s *service) AddTmpfs(path string, opts ...string) *service {
+	mount := path
+	if len(opts) > 0 {
+		mount += ":" + strings.Join(opts, ",")
+	}
+	s.addTmpfs(mount)
+	return s
+}
+
+// SetShmSize establece el tamaño de /dev/shm, por ejemplo "2gb" para navegadores o "256m" para Postgres
+func (s *service) SetShmSize(size string) *service {
+	s.shmSize = size
+	return s
+}
+
+// SetSysctl establece un parámetro del kernel en el namespace del contenedor,
+// por ejemplo SetSysctl("net.core.somaxconn", "1024")
+func (s *service) SetSysctl(key, value string) *service {
+	for i, kv := range s.sysctls {
+		if kv[0] == key {
+			s.sysctls[i][1] = value
+			return s
+		}
+	}
+	s.sysctls = append(s.sysctls, [2]string{key, value})
+	return s
+}
+
+// AllDevices reserva todos los dispositivos disponibles (count: all)
+const AllDevices = -1
+
+// DeviceReservation representa una reserva de dispositivos en deploy.resources.reservations.devices
+type DeviceReservation struct {
+	Driver       string            // por ejemplo "nvidia"
+	Count        int               // cantidad de dispositivos, AllDevices para todos y 0 para omitirlo
+	DeviceIDs    []string          // ids concretos, excluyente con Count
+	Capabilities []string          // por ejemplo "gpu", "compute", "utility"
+	Options      map[string]string // opciones específicas del driver
+}
+
+// AddDeviceReservation añade una reserva de dispositivos al servicio
+func (s *service) AddDeviceReservation(r DeviceReservation) *service {
+	if r.Count != 0 && len(r.DeviceIDs) > 0 {
+		s.errors = append(s.errors, fmt.Errorf("service %q: device reservation cannot set both count and device ids", s.name))
+		return s
+	}
+	if len(r.Capabilities) == 0 {
+		s.errors = append(s.errors, fmt.Errorf("service %q: device reservation requires at least one capability", s.name))
+		return s
+	}
+	s.deviceReservations = append(s.deviceReservations, r)
+	return s
+}
+
+// RequestGPU reserva count GPUs NVIDIA (AllDevices para todas) con las capacidades
+// indicadas, por defecto "gpu"
+func (s *service) RequestGPU(count int, capabilities ...string) *service {
+	if len(capabilities) == 0 {
+		capabilities = []string{"gpu"}
+	}
+	return s.AddDeviceReservation(DeviceReservation{
+		Driver:       "nvidia",
+		Count:        count,
+		Capabilities: capabilities,
+	})
+}
+
+// writeDeviceReservations escribe dentro de deploy las reservas de dispositivos
+func writeDeviceReservations(b *strings.Builder, reservations []DeviceReservation) {
+	b.WriteString("      resources:\n")
+	b.WriteString("        reservations:\n")
+	b.WriteString("          devices:\n")
+	for _, r := range reservations {
+		prefix := "            - "
+		if r.Driver != "" {
+			fmt.Fprintf(b, "%sdriver: %s\n", prefix, yamlQuote(r.Driver))
+			prefix = "              "
+		}
+		switch {
+		case r.Count == AllDevices:
+			fmt.Fprintf(b, "%scount: all\n", prefix)
+			prefix = "              "
+		case r.Count > 0:
+			fmt.Fprintf(b, "%scount: %d\n", prefix, r.Count)
+			prefix = "              "
+		}
+		if len(r.DeviceIDs) > 0 {
+			fmt.Fprintf(b, "%sdevice_ids:\n", prefix)
+			for _, id := range r.DeviceIDs {
+				fmt.Fprintf(b, "                - %s\n", yamlQuote(id))
+			}
+			prefix = "              "
+		}
+		fmt.Fprintf(b, "%scapabilities:\n", prefix)
+		for _, c := range r.Capabilities {
+			fmt.Fprintf(b, "                - %s\n", yamlQuote(c))
+		}
+		if len(r.Options) > 0 {
+			b.WriteString("              options:\n")
+			for _, k := range sortedKeys(r.Options) {
+				fmt.Fprintf(b, "                %s: %s\n", yamlPlain(k), yamlQuote(r.Options[k]))
+			}
+		}
+	}
+}
+
+// AddDevice expone un dispositivo del host en el contenedor (devices).
+// containerPath vacío usa la misma ruta del host y permissions ("rwm" o un
+// subconjunto) se omite si está vacío
+func (s *service) AddDevice(hostPath, containerPath, permissions string) *service {
+	if containerPath == "" {
+		containerPath = hostPath
+	}
+	if strings.Trim(permissions, "rwm") != "" {
+		s.errors = append(s.errors, invalidField(s.name, "device_permissions", permissions))
+		return s
+	}
+
+	device := hostPath + ":" + containerPath
+	if permissions != "" {
+		device += ":" + permissions
+	}
+	s.devices = append(s.devices, device)
+	return s
+}
@@ -0,0 +1,185 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUlimits(t *testing.T) {
+	es := *compose.NewService("es").SetImage("elasticsearch:8.13.0").
+		SetUlimit("nofile", 65535, 65535).
+		SetUlimit("memlock", -1, -1).
+		SetUlimit("nproc", 4096, 8192)
+
+	config, _ := compose.NewCompose("3.8", es)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Ulimits map[string]any `yaml:"ulimits"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	ulimits := result.Services["es"].Ulimits
+	if ulimits["nofile"] != 65535 || ulimits["memlock"] != -1 {
+		t.Errorf("Forma corta incorrecta: %v", ulimits)
+	}
+
+	nproc, ok := ulimits["nproc"].(map[string]any)
+	if !ok || nproc["soft"] != 4096 || nproc["hard"] != 8192 {
+		t.Errorf("Forma soft/hard incorrecta: %v", ulimits["nproc"])
+	}
+}
+
+func TestTmpfsAndShmSize(t *testing.T) {
+	browser := *compose.NewService("chrome").SetImage("selenium/standalone-chrome").
+		SetShmSize("2gb").
+		AddTmpfs("/run", "size=64m", "mode=1777").
+		AddTmpfs("/tmp")
+
+	config, _ := compose.NewCompose("3.8", browser)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			ShmSize string   `yaml:"shm_size"`
+			Tmpfs   []string `yaml:"tmpfs"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got := result.Services["chrome"]
+	if got.ShmSize != "2gb" {
+		t.Errorf("shm_size incorrecto: %q", got.ShmSize)
+	}
+	if len(got.Tmpfs) != 2 || got.Tmpfs[0] != "/run:size=64m,mode=1777" || got.Tmpfs[1] != "/tmp" {
+		t.Errorf("tmpfs incorrecto: %q", got.Tmpfs)
+	}
+}
+
+func TestSysctls(t *testing.T) {
+	proxy := *compose.NewService("proxy").SetImage("haproxy").
+		SetSysctl("net.core.somaxconn", "1024").
+		SetSysctl("net.ipv6.conf.all.disable_ipv6", "0").
+		SetSysctl("net.core.somaxconn", "4096")
+
+	config, _ := compose.NewCompose("3.8", proxy)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Sysctls map[string]string `yaml:"sysctls"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	sysctls := result.Services["proxy"].Sysctls
+	if len(sysctls) != 2 || sysctls["net.core.somaxconn"] != "4096" || sysctls["net.ipv6.conf.all.disable_ipv6"] != "0" {
+		t.Errorf("sysctls incorrecto: %v", sysctls)
+	}
+}
+
+func TestDeviceReservations(t *testing.T) {
+	inference := *compose.NewService("inference").SetImage("vllm/vllm-openai").
+		RequestGPU(compose.AllDevices).
+		AddDeviceReservation(compose.DeviceReservation{
+			Driver:       "nvidia",
+			DeviceIDs:    []string{"0", "3"},
+			Capabilities: []string{"gpu", "utility"},
+		})
+
+	config, _ := compose.NewCompose("3.8", inference)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	type device struct {
+		Driver       string   `yaml:"driver"`
+		Count        any      `yaml:"count"`
+		DeviceIDs    []string `yaml:"device_ids"`
+		Capabilities []string `yaml:"capabilities"`
+	}
+	var result struct {
+		Services map[string]struct {
+			Deploy struct {
+				Resources struct {
+					Reservations struct {
+						Devices []device `yaml:"devices"`
+					} `yaml:"reservations"`
+				} `yaml:"resources"`
+			} `yaml:"deploy"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v\n%s", err, data)
+	}
+
+	devices := result.Services["inference"].Deploy.Resources.Reservations.Devices
+	if len(devices) != 2 {
+		t.Fatalf("Cantidad de reservas incorrecta: %+v", devices)
+	}
+	if devices[0].Count != "all" || devices[0].Capabilities[0] != "gpu" {
+		t.Errorf("Reserva de GPU incorrecta: %+v", devices[0])
+	}
+	if devices[1].Count != nil || len(devices[1].DeviceIDs) != 2 || devices[1].Capabilities[1] != "utility" {
+		t.Errorf("Reserva por ids incorrecta: %+v", devices[1])
+	}
+
+	invalid := *compose.NewService("bad").SetImage("x").
+		AddDeviceReservation(compose.DeviceReservation{Count: 1, DeviceIDs: []string{"0"}, Capabilities: []string{"gpu"}})
+	config, _ = compose.NewCompose("3.8", invalid)
+	if err := config.Validate(); err == nil {
+		t.Error("Se esperaba un error por count y device_ids simultáneos")
+	}
+}
+
+func TestDevices(t *testing.T) {
+	iot := *compose.NewService("iot").SetImage("zigbee2mqtt").
+		AddDevice("/dev/ttyUSB0", "/dev/ttyACM0", "rw").
+		AddDevice("/dev/dri", "", "")
+
+	config, _ := compose.NewCompose("3.8", iot)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Devices []string `yaml:"devices"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	devices := result.Services["iot"].Devices
+	if len(devices) != 2 || devices[0] != "/dev/ttyUSB0:/dev/ttyACM0:rw" || devices[1] != "/dev/dri:/dev/dri" {
+		t.Errorf("devices incorrecto: %q", devices)
+	}
+
+	bad := *compose.NewService("bad").SetImage("x").AddDevice("/dev/null", "", "rwx")
+	config, _ = compose.NewCompose("3.8", bad)
+	if err := config.Validate(); err == nil {
+		t.Error("Se esperaba un error por permisos inválidos")
+	}
+}
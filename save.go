@@ -0,0 +1,183 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// SaveOption configura el comportamiento de Save
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	path     string
+	dryRun   bool
+	maxBytes int
+	perm     os.FileMode
+	owner    []fileOwner
+
+	overwriteEdits bool
+	envExample     bool
+	verifyImages   bool
+
+	preserveComments bool
+}
+
+// SaveTo indica la ruta del archivo, por defecto docker-compose.yml
+func SaveTo(path string) SaveOption {
+	return func(o *saveOptions) {
+		o.path = path
+	}
+}
+
+// DryRun hace que Save solo informe si escribiría y con qué diferencias, sin escribir
+func DryRun() SaveOption {
+	return func(o *saveOptions) {
+		o.dryRun = true
+	}
+}
+
+// MaxBytes rechaza escribir un YAML generado más grande que n bytes
+func MaxBytes(n int) SaveOption {
+	return func(o *saveOptions) {
+		o.maxBytes = n
+	}
+}
+
+// SaveStatus indica qué hizo Save con el archivo
+type SaveStatus int
+
+const (
+	SaveUnchanged SaveStatus = iota // el archivo ya tenía el contenido generado
+	SaveCreated                     // el archivo no existía
+	SaveUpdated                     // el archivo existía con otro contenido
+)
+
+func (s SaveStatus) String() string {
+	switch s {
+	case SaveCreated:
+		return "created"
+	case SaveUpdated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
+
+// SaveResult describe el resultado de Save
+type SaveResult struct {
+	Path    string
+	Status  SaveStatus
+	Written bool   // el archivo se escribió, o se escribiría en modo DryRun
+	DryRun  bool   // no se escribió nada por DryRun
+	Diff    string // diff unificado entre el archivo en disco y el generado
+}
+
+// Save genera el YAML y lo escribe solo si difiere del archivo existente.
+// Respeta la cancelación del contexto antes de escribir
+func (c *composeConfig) Save(ctx context.Context, opts ...SaveOption) (SaveResult, error) {
+	o := saveOptions{path: defaultComposeFile, perm: defaultComposeFileMode}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	result := SaveResult{Path: o.path, DryRun: o.dryRun}
+	if !o.dryRun {
+		c.file = o.path
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	yamlData, err := c.Bytes()
+	if err != nil {
+		return result, err
+	}
+
+	if o.maxBytes > 0 && len(yamlData) > o.maxBytes {
+		return result, fmt.Errorf("generated YAML is %d bytes, exceeds limit of %d", len(yamlData), o.maxBytes)
+	}
+
+	if o.verifyImages {
+		if err := c.VerifyImages(ctx); err != nil {
+			return result, err
+		}
+	}
+
+	// Verificar si existe archivo actual
+	currentData, err := c.files.readFile(o.path)
+	if err != nil && !os.IsNotExist(err) {
+		return result, fmt.Errorf("error al leer archivo: %v", err)
+	}
+
+	// Trasladar los comentarios hechos a mano al contenido generado
+	if err == nil && o.preserveComments {
+		merged, errMerge := c.mergeComments(yamlData, currentData)
+		if errMerge != nil {
+			return result, errMerge
+		}
+		yamlData = merged
+	}
+
+	yamlData, errHook := c.beforeSaveHooks(o.path, yamlData)
+	if errHook != nil {
+		return result, errHook
+	}
+
+	// Las variables de los servicios y el .env.example se escriben solo
+	// cuando pasaron todas las comprobaciones
+	saveEnv := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.resolveDeferredEnv(); err != nil {
+			return err
+		}
+		if o.envExample {
+			return c.saveEnvExample()
+		}
+		return nil
+	}
+
+	// Si el contenido es igual, no hacer nada
+	if err == nil && string(currentData) == string(yamlData) {
+		if !o.dryRun {
+			if err := saveEnv(); err != nil {
+				return result, err
+			}
+		}
+		c.logSave(o.path, SaveUnchanged)
+		return result, c.afterSaveHooks(result, yamlData)
+	}
+
+	result.Written = true
+	result.Status = SaveUpdated
+	if os.IsNotExist(err) {
+		result.Status = SaveCreated
+	}
+	result.Diff = unifiedDiff(o.path, o.path, string(currentData), string(yamlData))
+
+	if o.dryRun {
+		c.log(slog.LevelInfo, "dry run", "path", o.path, "status", result.Status.String())
+		return result, nil
+	}
+
+	// No pisar cambios hechos a mano sobre un archivo generado
+	if err == nil {
+		if err := c.checkManualEdits(o.path, currentData, o.overwriteEdits || o.preserveComments); err != nil {
+			return SaveResult{Path: o.path}, err
+		}
+	}
+
+	if err := saveEnv(); err != nil {
+		return SaveResult{Path: o.path}, err
+	}
+
+	if err := c.files.writeFile(o.path, yamlData, o.perm, o.owner...); err != nil {
+		return SaveResult{Path: o.path}, err
+	}
+	c.logSave(o.path, result.Status)
+	return result, c.afterSaveHooks(result, yamlData)
+}
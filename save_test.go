@@ -0,0 +1,133 @@
+package compose_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	ctx := context.Background()
+
+	web := *compose.NewService("web").SetImage("nginx:1.25")
+	config, _ := compose.NewCompose("3.8", web)
+	if _, err := config.Save(ctx, compose.SaveTo(path)); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	t.Run("DryRun informa el diff sin escribir", func(t *testing.T) {
+		before := readFile(t, path)
+
+		updated, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.27"))
+		result, err := updated.Save(ctx, compose.SaveTo(path), compose.DryRun())
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		if !result.Written || !result.DryRun {
+			t.Errorf("Resultado incorrecto: %+v", result)
+		}
+		if !strings.Contains(result.Diff, "-    image: \"nginx:1.25\"\n+    image: \"nginx:1.27\"\n") {
+			t.Errorf("Diff incorrecto:\n%s", result.Diff)
+		}
+		if string(readFile(t, path)) != string(before) {
+			t.Error("DryRun no debe modificar el archivo")
+		}
+	})
+
+	t.Run("Sin cambios no escribe", func(t *testing.T) {
+		result, err := config.Save(ctx, compose.SaveTo(path))
+		if err != nil || result.Written || result.Diff != "" {
+			t.Errorf("No se esperaban cambios: %+v %v", result, err)
+		}
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "docker-compose.yml")
+		if _, err := config.Save(ctx, compose.SaveTo(other), compose.MaxBytes(10)); err == nil {
+			t.Error("Se esperaba un error por tamaño")
+		}
+		if _, err := os.Stat(other); !os.IsNotExist(err) {
+			t.Error("No debe escribirse el archivo")
+		}
+	})
+
+	t.Run("Contexto cancelado", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if _, err := config.Save(canceled, compose.SaveTo(path)); !errors.Is(err, context.Canceled) {
+			t.Errorf("Se esperaba context.Canceled: %v", err)
+		}
+	})
+}
+
+func TestSaveRejectedWritesNothing(t *testing.T) {
+	chdir(t, t.TempDir())
+	ctx := context.Background()
+
+	db := *compose.NewService("db").SetImage("postgres:16").AddSecretEnvironment("PW")
+	config, _ := compose.NewCompose("3.8", db)
+
+	if _, err := config.Save(ctx, compose.MaxBytes(10), compose.WithEnvExample()); err == nil {
+		t.Fatal("Se esperaba un error por tamaño")
+	}
+	for _, name := range []string{".env", ".env.example", ".gitignore", "docker-compose.yml"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("Un guardado rechazado no debe escribir %s", name)
+		}
+	}
+
+	// un archivo editado a mano también rechaza el guardado antes de tocar el .env
+	if _, err := config.Save(ctx); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	edited := strings.Replace(string(readFile(t, "docker-compose.yml")), "postgres:16", "postgres:manual", 1)
+	if err := os.WriteFile("docker-compose.yml", []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(".env"); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := compose.NewCompose("3.8", *compose.NewService("db").SetImage("postgres:17").AddSecretEnvironment("PW"))
+	if _, err := updated.Save(ctx); err == nil {
+		t.Fatal("Se esperaba un error por edición manual")
+	}
+	if _, err := os.Stat(".env"); !os.IsNotExist(err) {
+		t.Error("Un guardado rechazado no debe escribir el .env")
+	}
+}
+
+func TestSaveIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+
+	config, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.25"))
+	updated, _ := compose.NewCompose("3.8", *compose.NewService("web").SetImage("nginx:1.27"))
+
+	steps := []struct {
+		config interface {
+			SaveIfChanged(...string) (compose.SaveStatus, error)
+		}
+		want compose.SaveStatus
+	}{
+		{config, compose.SaveCreated},
+		{config, compose.SaveUnchanged},
+		{updated, compose.SaveUpdated},
+	}
+
+	for _, step := range steps {
+		status, err := step.config.SaveIfChanged(path)
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if status != step.want {
+			t.Errorf("Se esperaba %v, se obtuvo %v", step.want, status)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// ManifestEntry es un archivo escrito por SaveAll
+type ManifestEntry struct {
+	Path   string
+	Status SaveStatus
+}
+
+// Changed indica si el archivo se creó o se actualizó
+func (e ManifestEntry) Changed() bool {
+	return e.Status != SaveUnchanged
+}
+
+// Manifest lista los archivos de SaveAll en el orden en que se escribieron
+type Manifest []ManifestEntry
+
+// Changed devuelve las rutas de los archivos creados o actualizados
+func (m Manifest) Changed() []string {
+	var paths []string
+	for _, e := range m {
+		if e.Changed() {
+			paths = append(paths, e.Path)
+		}
+	}
+	return paths
+}
+
+// SaveAll escribe en dir todo lo necesario para ejecutar el proyecto desde ahí:
+// docker-compose.yml, el override de cada entorno (docker-compose.<env>.yml),
+// el .env y los .env.<env> que el compose referencia y .env.example. Cada
+// archivo se escribe solo si cambió y el manifiesto indica cuáles cambiaron.
+// Los .env se escriben en dir, donde docker compose los busca
+func (c *composeConfig) SaveAll(ctx context.Context, dir string) (Manifest, error) {
+	var manifest Manifest
+
+	if err := c.files.mkdirAll(dir, 0755); err != nil {
+		return manifest, err
+	}
+
+	// el contenido previo de los .env permite informar si Save los cambió
+	envFiles := []string{filepath.Join(dir, defaultEnvFile)}
+	for _, env := range c.Environments() {
+		envFiles = append(envFiles, filepath.Join(dir, env.EnvFile()))
+	}
+	before := make([][]byte, len(envFiles))
+	for i, path := range envFiles {
+		data, err := c.files.readEnvData(path)
+		if err != nil {
+			return manifest, err
+		}
+		before[i] = data
+	}
+
+	result, err := c.Save(ctx, SaveTo(filepath.Join(dir, defaultComposeFile)))
+	if err != nil {
+		return manifest, err
+	}
+	manifest = append(manifest, ManifestEntry{result.Path, result.Status})
+
+	for _, env := range c.Environments() {
+		if err := ctx.Err(); err != nil {
+			return manifest, err
+		}
+
+		data, err := c.EnvironmentBytes(env)
+		if err != nil {
+			return manifest, err
+		}
+		path := c.overrideFile(env)
+		status, err := c.files.saveFileIfChanged(path, data)
+		if err != nil {
+			return manifest, err
+		}
+		manifest = append(manifest, ManifestEntry{path, status})
+		c.logSave(path, status)
+	}
+
+	for i, path := range envFiles {
+		after, err := c.files.readEnvData(path)
+		if err != nil {
+			return manifest, err
+		}
+		if after == nil {
+			continue
+		}
+		status := SaveUnchanged
+		switch {
+		case before[i] == nil:
+			status = SaveCreated
+		case string(before[i]) != string(after):
+			status = SaveUpdated
+		}
+		manifest = append(manifest, ManifestEntry{path, status})
+		c.logSave(path, status)
+	}
+
+	data, err := c.EnvExampleBytes()
+	if err != nil {
+		return manifest, err
+	}
+	path := filepath.Join(dir, envExampleFile)
+	status, err := c.files.saveFileIfChanged(path, data)
+	if err != nil {
+		return manifest, err
+	}
+	c.logSave(path, status)
+	return append(manifest, ManifestEntry{path, status}), nil
+}
+
+// saveFileIfChanged escribe data en path solo si su contenido difiere
+func (f *fileSettings) saveFileIfChanged(path string, data []byte) (SaveStatus, error) {
+	current, err := f.readFile(path)
+	switch {
+	case err == nil && string(current) == string(data):
+		return SaveUnchanged, nil
+	case err != nil && !os.IsNotExist(err):
+		return SaveUnchanged, err
+	}
+
+	if err := f.writeFile(path, data, defaultComposeFileMode); err != nil {
+		return SaveUnchanged, err
+	}
+	if err != nil {
+		return SaveCreated, nil
+	}
+	return SaveUpdated, nil
+}
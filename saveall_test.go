@@ -0,0 +1,81 @@
+package compose_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestSaveAll(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DEBUG_TOKEN", "debug-secret")
+
+	api := *compose.NewService("api").SetImage("api:1.0").
+		AddEnvironment("DB_PASSWORD").
+		AddEnvironmentFor("debug", "LOG_LEVEL", "debug").
+		AddEnvironmentFor("debug", "DEBUG_TOKEN")
+	config, _ := compose.NewCompose("3.8", api)
+
+	manifest, err := config.SaveAll(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	want := compose.Manifest{
+		{Path: filepath.Join("deploy", "docker-compose.yml"), Status: compose.SaveCreated},
+		{Path: filepath.Join("deploy", "docker-compose.debug.yml"), Status: compose.SaveCreated},
+		{Path: filepath.Join("deploy", ".env"), Status: compose.SaveCreated},
+		{Path: filepath.Join("deploy", ".env.debug"), Status: compose.SaveCreated},
+		{Path: filepath.Join("deploy", ".env.example"), Status: compose.SaveCreated},
+	}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Fatalf("Manifiesto incorrecto:\n got: %+v\nwant: %+v", manifest, want)
+	}
+
+	// el compose y el .env copiado se referencian entre sí
+	if data := readFile(t, "deploy/docker-compose.yml"); !strings.Contains(string(data), "${DB_PASSWORD}") {
+		t.Errorf("Se esperaba la referencia ${DB_PASSWORD}:\n%s", data)
+	}
+	if data := readFile(t, "deploy/.env"); !strings.Contains(string(data), "DB_PASSWORD=secret") {
+		t.Errorf(".env incorrecto:\n%s", data)
+	}
+	if data := readFile(t, "deploy/.env.debug"); !strings.Contains(string(data), "DEBUG_TOKEN=debug-secret") {
+		t.Errorf(".env.debug incorrecto:\n%s", data)
+	}
+	if data := readFile(t, "deploy/.env.example"); !strings.Contains(string(data), "DB_PASSWORD=\n") {
+		t.Errorf(".env.example incorrecto:\n%s", data)
+	}
+	if data := readFile(t, "deploy/.gitignore"); !strings.Contains(string(data), ".env") {
+		t.Errorf("Se esperaba .env en el .gitignore de deploy:\n%s", data)
+	}
+
+	// una segunda llamada no cambia nada
+	manifest, err = config.SaveAll(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if changed := manifest.Changed(); len(changed) != 0 {
+		t.Errorf("No se esperaban cambios: %q", changed)
+	}
+
+	// cambiar un secreto y volver a generar solo actualiza el .env
+	t.Setenv("DB_PASSWORD", "rotated")
+	api = *compose.NewService("api").SetImage("api:1.0").
+		AddEnvironment("DB_PASSWORD").
+		AddEnvironmentFor("debug", "LOG_LEVEL", "debug").
+		AddEnvironmentFor("debug", "DEBUG_TOKEN")
+	config, _ = compose.NewCompose("3.8", api)
+	manifest, err = config.SaveAll(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if changed := manifest.Changed(); len(changed) != 1 || changed[0] != filepath.Join("deploy", ".env") {
+		t.Errorf("Se esperaba solo el .env actualizado: %q", changed)
+	}
+}
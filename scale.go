@@ -0,0 +1,26 @@
+package compose
+
+import "strconv"
+
+// SetScale fija la cantidad de réplicas del servicio (scale:). Con más de una
+// réplica se omite container_name, ya que un nombre fijo chocaría entre ellas
+func (s *service) SetScale(n int) *service {
+	if n < 0 {
+		s.errors = append(s.errors, invalidField(s.name, "scale", strconv.Itoa(n)))
+		return s
+	}
+	s.scale = &n
+	return s
+}
+
+// UseDeployReplicas emite las réplicas de SetScale como deploy.replicas, la
+// forma del compose spec que también entiende swarm, en lugar de scale:
+func (c *composeConfig) UseDeployReplicas(enabled bool) *composeConfig {
+	c.deployReplicas = enabled
+	return c
+}
+
+// scaled indica si el servicio tiene más de una réplica
+func (s service) scaled() bool {
+	return s.scale != nil && *s.scale > 1
+}
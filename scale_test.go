@@ -0,0 +1,40 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestSetScale(t *testing.T) {
+	worker := *compose.NewService("worker").SetImage("myworker").SetScale(3)
+	single := *compose.NewService("api").SetImage("myapi").SetScale(1)
+
+	config, _ := compose.NewCompose("3.8", worker, single)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	for _, want := range []string{"    scale: 3\n", "    scale: 1\n", `container_name: "api"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Falta %q en:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), `container_name: "worker"`) {
+		t.Errorf("container_name debe omitirse con varias réplicas:\n%s", data)
+	}
+
+	data, err = config.UseDeployReplicas(true).Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !strings.Contains(string(data), "    deploy:\n      replicas: 3\n") || strings.Contains(string(data), "scale:") {
+		t.Errorf("Se esperaba deploy.replicas:\n%s", data)
+	}
+
+	bad, _ := compose.NewCompose("3.8", *compose.NewService("x").SetImage("x").SetScale(-1))
+	if _, err := bad.Bytes(); err == nil {
+		t.Error("Se esperaba un error por scale negativo")
+	}
+}
@@ -0,0 +1,113 @@
+package compose
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// composeSpecSchema es el esquema JSON oficial de compose-spec
+//
+//go:embed schema/compose-spec.json
+var composeSpecSchema string
+
+// ValidationMode amplía las comprobaciones que hace Validate
+type ValidationMode int
+
+const (
+	// SchemaStrict valida además el YAML generado contra el esquema compose-spec,
+	// para detectar claves que docker rechazaría o ignoraría
+	SchemaStrict ValidationMode = iota + 1
+	// CheckBindMounts comprueba además que los orígenes de los bind mounts
+	// existan en disco, resolviendo las rutas relativas desde el archivo compose
+	CheckBindMounts
+)
+
+var (
+	schemaOnce     sync.Once
+	compiledSchema *jsonschema.Schema
+	schemaErr      error
+)
+
+// loadSchema compila el esquema embebido una sola vez
+func loadSchema() (*jsonschema.Schema, error) {
+	schemaOnce.Do(func() {
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource("compose-spec.json", strings.NewReader(composeSpecSchema)); err != nil {
+			schemaErr = err
+			return
+		}
+		compiledSchema, schemaErr = compiler.Compile("compose-spec.json")
+	})
+	return compiledSchema, schemaErr
+}
+
+// validateSchema comprueba un documento YAML contra el esquema compose-spec y
+// devuelve cada violación como un error propio
+func validateSchema(data []byte) error {
+	schema, err := loadSchema()
+	if err != nil {
+		return fmt.Errorf("error compiling compose schema: %w", err)
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("error parsing generated YAML: %w", err)
+	}
+
+	// el validador trabaja sobre tipos JSON: pasar por encoding/json normaliza números y mapas
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("error converting generated YAML: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var instance any
+	if err := dec.Decode(&instance); err != nil {
+		return err
+	}
+
+	err = schema.Validate(instance)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	// quedarse con las causas más específicas, una por ubicación
+	seen := make(map[string]string)
+	for _, e := range verr.BasicOutput().Errors {
+		if e.InstanceLocation == "" || strings.HasPrefix(e.Error, "doesn't validate with") {
+			continue
+		}
+		if _, ok := seen[e.InstanceLocation]; !ok {
+			seen[e.InstanceLocation] = e.Error
+		}
+	}
+	if len(seen) == 0 {
+		seen[verr.InstanceLocation] = verr.Message
+	}
+
+	locations := make([]string, 0, len(seen))
+	for loc := range seen {
+		locations = append(locations, loc)
+	}
+	sort.Strings(locations)
+
+	errs := make([]error, 0, len(locations))
+	for _, loc := range locations {
+		var service string
+		if rest, ok := strings.CutPrefix(loc, "/services/"); ok {
+			service, _, _ = strings.Cut(rest, "/")
+		}
+		errs = append(errs, &ValidationError{Service: service, Field: loc, Err: fmt.Errorf("%w: %s: %s", ErrSchemaViolation, loc, seen[loc])})
+	}
+	return errors.Join(errs...)
+}
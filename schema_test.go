@@ -0,0 +1,48 @@
+package compose_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestValidateSchemaStrict(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *compose.NewService("db").SetImage("postgres:16").
+		AddPort("5432", "5432").
+		AddEnvironment("POSTGRES_DB", "app").
+		AddVolume(compose.Volume{Source: "./data", Target: "/var/lib/postgresql/data"}).
+		SetHealthCheck([]string{"CMD", "pg_isready"}, "10s", "5s", 5).
+		SetShmSize("256m")
+	api := *compose.NewService("api").SetBuild(".").
+		SetRestartPolicy(compose.RestartUnlessStopped).
+		SetUlimit("nofile", 65535, 65535).
+		SetSysctl("net.core.somaxconn", "1024").
+		AddTmpfs("/run", "size=64m").
+		RequestGPU(1).
+		AttachNetwork("backend", compose.NetworkAttachment{Aliases: []string{"api.internal"}}).
+		DependsOn(db)
+
+	config, _ := compose.NewCompose("3.8", db, api)
+	config.SetProjectName("shop").SetExtension("x-owner", "platform")
+	if err := config.Validate(compose.SchemaStrict); err != nil {
+		t.Fatalf("El YAML generado no cumple el esquema: %v", err)
+	}
+
+	bad := *compose.NewService("bad").SetImage("nginx").SetRaw("restart_policy", "always")
+	config, _ = compose.NewCompose("3.8", bad)
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Sin SchemaStrict no debe validarse el esquema: %v", err)
+	}
+
+	err := config.Validate(compose.SchemaStrict)
+	if !errors.Is(err, compose.ErrSchemaViolation) {
+		t.Fatalf("Se esperaba ErrSchemaViolation, se obtuvo %v", err)
+	}
+	if !strings.Contains(err.Error(), "/services/bad") || !strings.Contains(err.Error(), "restart_policy") {
+		t.Errorf("El error debe indicar la ubicación y la clave: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package compose
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Charsets predefinidos para RandomSecret
+const (
+	CharsetAlphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	CharsetHex          = "0123456789abcdef"
+	CharsetURLSafe      = CharsetAlphanumeric + "-_"
+)
+
+// DefaultSecretLength es la longitud usada cuando no se indica otra
+const DefaultSecretLength = 32
+
+// RandomSecret genera un valor aleatorio criptográficamente seguro con la
+// longitud y el charset indicados (CharsetAlphanumeric si charset está vacío)
+func RandomSecret(length int, charset string) (string, error) {
+	if length <= 0 {
+		return "", errors.New("secret length must be greater than zero")
+	}
+	if charset == "" {
+		charset = CharsetAlphanumeric
+	}
+
+	max := big.NewInt(int64(len(charset)))
+	out := make([]byte, length)
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("error generating secret: %w", err)
+		}
+		out[i] = charset[n.Int64()]
+	}
+	return string(out), nil
+}
+
+// EnsureEnvSecret devuelve el valor de key en el archivo .env y, si no existe,
+// genera uno aleatorio y lo guarda. Así los secretos se crean una sola vez.
+// paths funciona igual que en AddEnvToFile
+func EnsureEnvSecret(key string, length int, charset string, paths ...string) (string, error) {
+	envVars, err := osFiles.readEnvFile(envPathFrom(paths))
+	if err != nil {
+		return "", err
+	}
+	if value, exists := envVars[key]; exists && value != "" {
+		return value, nil
+	}
+
+	value, err := RandomSecret(length, charset)
+	if err != nil {
+		return "", err
+	}
+	if err := AddEnvToFile(key, value, paths...); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// AddSecretEnvironment añade al servicio una variable referenciada como ${key}
+// cuyo valor es un secreto aleatorio guardado en .env al guardar la
+// configuración (solo se genera si aún no existe).
+// Sin length se usa DefaultSecretLength con CharsetAlphanumeric
+func (s *service) AddSecretEnvironment(key string, length ...int) *service {
+	size := DefaultSecretLength
+	if len(length) > 0 {
+		size = length[0]
+	}
+	if size <= 0 {
+		s.errors = append(s.errors, invalidField(s.name, "secret_length", fmt.Sprint(size)))
+		return s
+	}
+
+	s.storeEnv(deferredEnv{key: key, secret: size})
+	s.environment[key] = fmt.Sprintf("${%s}", key)
+	return s
+}
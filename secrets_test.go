@@ -0,0 +1,51 @@
+package compose_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestRandomSecret(t *testing.T) {
+	secret, err := compose.RandomSecret(24, compose.CharsetHex)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if len(secret) != 24 {
+		t.Errorf("Longitud incorrecta: %d", len(secret))
+	}
+	if strings.Trim(secret, compose.CharsetHex) != "" {
+		t.Errorf("Caracteres fuera del charset: %q", secret)
+	}
+
+	if _, err := compose.RandomSecret(0, ""); err == nil {
+		t.Error("Se esperaba un error con longitud cero")
+	}
+}
+
+func TestEnsureEnvSecret(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	gitignorePath := filepath.Join(dir, ".gitignore")
+
+	first, err := compose.EnsureEnvSecret("POSTGRES_PASSWORD", 16, "", envPath, gitignorePath)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	second, err := compose.EnsureEnvSecret("POSTGRES_PASSWORD", 16, "", envPath, gitignorePath)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("El secreto no debe regenerarse: %q != %q", first, second)
+	}
+
+	if content := string(readFile(t, envPath)); content != "POSTGRES_PASSWORD="+first+"\n" {
+		t.Errorf("Contenido inesperado de .env: %q", content)
+	}
+}
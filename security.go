@@ -0,0 +1,64 @@
+package compose
+
+import "strings"
+
+// AddCapability añade capacidades del kernel al contenedor (cap_add), por ejemplo NET_ADMIN
+func (s *service) AddCapability(caps ...string) *service {
+	for _, c := range caps {
+		s.capAdd = append(s.capAdd, normalizeCapability(c))
+	}
+	return s
+}
+
+// DropCapability quita capacidades del kernel al contenedor (cap_drop), por ejemplo ALL
+func (s *service) DropCapability(caps ...string) *service {
+	for _, c := range caps {
+		s.capDrop = append(s.capDrop, normalizeCapability(c))
+	}
+	return s
+}
+
+// SetPrivileged ejecuta el contenedor en modo privilegiado
+func (s *service) SetPrivileged(privileged bool) *service {
+	s.privileged = privileged
+	return s
+}
+
+// normalizeCapability escribe la capacidad en mayúsculas y sin el prefijo CAP_
+func normalizeCapability(c string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(c)), "CAP_")
+}
+
+// HardenReadOnly monta el sistema de archivos raíz en solo lectura y añade
+// tmpfs escribibles en /tmp, /run y en las rutas indicadas
+func (s *service) HardenReadOnly(writablePaths ...string) *service {
+	s.readOnly = true
+	for _, path := range append([]string{"/tmp", "/run"}, writablePaths...) {
+		s.addTmpfs(path)
+	}
+	return s
+}
+
+// addTmpfs añade un montaje tmpfs si la ruta aún no está montada
+func (s *service) addTmpfs(mount string) {
+	path, _, _ := strings.Cut(mount, ":")
+	for _, existing := range s.tmpfs {
+		if p, _, _ := strings.Cut(existing, ":"); p == path {
+			return
+		}
+	}
+	s.tmpfs = append(s.tmpfs, mount)
+}
+
+// SetReadOnly monta el sistema de archivos raíz del contenedor en solo lectura
+func (s *service) SetReadOnly(readOnly bool) *service {
+	s.readOnly = readOnly
+	return s
+}
+
+// AddSecurityOpt añade opciones de seguridad (security_opt),
+// por ejemplo "no-new-privileges:true" o "seccomp:profile.json"
+func (s *service) AddSecurityOpt(opts ...string) *service {
+	s.securityOpt = append(s.securityOpt, opts...)
+	return s
+}
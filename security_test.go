@@ -0,0 +1,82 @@
+package compose_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+type securityResult struct {
+	Services map[string]struct {
+		CapAdd     []string `yaml:"cap_add"`
+		CapDrop    []string `yaml:"cap_drop"`
+		Privileged bool     `yaml:"privileged"`
+		ReadOnly   bool     `yaml:"read_only"`
+		Tmpfs      []string `yaml:"tmpfs"`
+		SecOpt     []string `yaml:"security_opt"`
+	} `yaml:"services"`
+}
+
+func parseSecurity(t *testing.T, config interface{ Bytes() ([]byte, error) }) securityResult {
+	t.Helper()
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result securityResult
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+	return result
+}
+
+func TestCapabilities(t *testing.T) {
+	vpn := *compose.NewService("vpn").SetImage("wireguard").
+		AddCapability("net_admin", "CAP_SYS_MODULE").
+		DropCapability("ALL").
+		SetPrivileged(true)
+
+	config, _ := compose.NewCompose("3.8", vpn)
+	got := parseSecurity(t, config).Services["vpn"]
+
+	if !reflect.DeepEqual(got.CapAdd, []string{"NET_ADMIN", "SYS_MODULE"}) || !reflect.DeepEqual(got.CapDrop, []string{"ALL"}) {
+		t.Errorf("Capacidades incorrectas: %+v", got)
+	}
+	if !got.Privileged {
+		t.Error("Se esperaba privileged: true")
+	}
+}
+
+func TestHardenReadOnly(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api").HardenReadOnly("/var/cache/app", "/tmp")
+
+	config, _ := compose.NewCompose("3.8", api)
+	got := parseSecurity(t, config).Services["api"]
+
+	if !got.ReadOnly {
+		t.Error("Se esperaba read_only: true")
+	}
+	if !reflect.DeepEqual(got.Tmpfs, []string{"/tmp", "/run", "/var/cache/app"}) {
+		t.Errorf("tmpfs incorrecto: %q", got.Tmpfs)
+	}
+}
+
+func TestReadOnlyAndSecurityOpt(t *testing.T) {
+	api := *compose.NewService("api").SetImage("api").
+		SetReadOnly(true).
+		AddSecurityOpt("no-new-privileges:true", "apparmor:docker-default")
+
+	config, _ := compose.NewCompose("3.8", api)
+	got := parseSecurity(t, config).Services["api"]
+
+	if !got.ReadOnly {
+		t.Error("Se esperaba read_only: true")
+	}
+	if !reflect.DeepEqual(got.SecOpt, []string{"no-new-privileges:true", "apparmor:docker-default"}) {
+		t.Errorf("security_opt incorrecto: %q", got.SecOpt)
+	}
+}
@@ -0,0 +1,56 @@
+package compose
+
+import "fmt"
+
+// AddService añade copias de los servicios (y sus sidecars) a una configuración
+// ya creada, para armarla de forma incremental desde distintos módulos o
+// goroutines. Los nombres repetidos se informan al validar
+func (c *composeConfig) AddService(services ...service) *composeConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.services = append(c.services, cloneServices(expandSidecars(services))...)
+	return c
+}
+
+// ReplaceService reemplaza el servicio con el mismo nombre conservando su posición
+func (c *composeConfig) ReplaceService(s service) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := c.serviceIndex(s.name)
+	if i < 0 {
+		return fmt.Errorf("service %q not found", s.name)
+	}
+	c.services[i] = cloneService(s)
+	return nil
+}
+
+// GetService devuelve una copia del servicio name. Los cambios sobre la copia
+// no afectan a la configuración hasta aplicarlos con ReplaceService, así un
+// AddService o RemoveService posterior no puede dejarla apuntando a otro servicio
+func (c *composeConfig) GetService(name string) (*service, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.serviceByName(name)
+	if s == nil {
+		return nil, false
+	}
+	out := cloneService(*s)
+	return &out, true
+}
+
+// RemoveService elimina el servicio name e informa si existía. Las dependencias
+// de otros servicios hacia él no se tocan y se informan al validar
+func (c *composeConfig) RemoveService(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := c.serviceIndex(name)
+	if i < 0 {
+		return false
+	}
+	c.services = append(c.services[:i], c.services[i+1:]...)
+	return true
+}
@@ -0,0 +1,58 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestServiceManagement(t *testing.T) {
+	config, _ := compose.NewCompose("3.8")
+
+	config.AddService(*compose.NewService("db").SetImage("postgres:15"))
+	config.AddService(*compose.NewService("api").SetImage("myapi"), *compose.NewService("web").SetImage("nginx"))
+
+	db, ok := config.GetService("db")
+	if !ok {
+		t.Fatal("Se esperaba el servicio db")
+	}
+	db.SetImage("postgres:16")
+
+	// la copia es independiente: otros cambios no la mueven y ella no cambia
+	// la configuración hasta ReplaceService
+	web, _ := config.GetService("web")
+	web.SetImage("httpd")
+	config.AddService(*compose.NewService("cache").SetImage("redis"))
+	config.RemoveService("cache")
+	if err := config.ReplaceService(*db); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if err := config.ReplaceService(*compose.NewService("web").SetImage("caddy")); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if err := config.ReplaceService(*compose.NewService("missing").SetImage("x")); err == nil {
+		t.Error("Se esperaba un error al reemplazar un servicio inexistente")
+	}
+
+	if !config.RemoveService("api") || config.RemoveService("api") {
+		t.Error("RemoveService debe informar si el servicio existía")
+	}
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got := serviceOrder(t, data); got != "db,web" {
+		t.Errorf("Servicios incorrectos: %s", got)
+	}
+	for _, want := range []string{`image: "postgres:16"`, `image: "caddy"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Falta %s en:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "httpd") {
+		t.Errorf("Una copia sin ReplaceService no debe cambiar la configuración:\n%s", data)
+	}
+}
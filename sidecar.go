@@ -0,0 +1,40 @@
+package compose
+
+import "strings"
+
+// AddSidecar añade un servicio acompañante que comparte el namespace de red del
+// servicio (network_mode: service:<nombre>), depende de él y recibe su nombre
+// como prefijo (por ejemplo "api-envoy"). Como el sidecar no puede publicar
+// puertos propios, sus puertos se publican en el servicio principal.
+// Los sidecars se incorporan a la configuración en NewCompose, justo después del servicio.
+func (s *service) AddSidecar(sidecar *service) *service {
+	prefix := s.name + "-"
+	if !strings.HasPrefix(sidecar.name, prefix) {
+		if sidecar.containerName == sidecar.name {
+			sidecar.containerName = prefix + sidecar.name
+		}
+		sidecar.name = prefix + sidecar.name
+	}
+
+	s.ports = append(s.ports, sidecar.ports...)
+	sidecar.ports = nil
+
+	sidecar.networks = nil
+	sidecar.networkMode = "service:" + s.name
+	sidecar.serviceDependencies = append(sidecar.serviceDependencies, s.name)
+
+	s.sidecars = append(s.sidecars, sidecar)
+	return s
+}
+
+// expandSidecars inserta los sidecars de cada servicio a continuación de este
+func expandSidecars(services []service) []service {
+	var out []service
+	for _, s := range services {
+		out = append(out, s)
+		for _, sidecar := range s.sidecars {
+			out = append(out, expandSidecars([]service{*sidecar})...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAddSidecar(t *testing.T) {
+	proxy := compose.NewService("envoy").SetImage("envoyproxy/envoy:v1.30").AddPort("9901", "9901")
+	api := *compose.NewService("api").SetImage("api").AddPort("8080", "8080").AddSidecar(proxy)
+
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			ContainerName string   `yaml:"container_name"`
+			Ports         []string `yaml:"ports"`
+			NetworkMode   string   `yaml:"network_mode"`
+			DependsOn     []string `yaml:"depends_on"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	sidecar, ok := result.Services["api-envoy"]
+	if !ok {
+		t.Fatalf("Falta el sidecar api-envoy: %s", data)
+	}
+	if sidecar.ContainerName != "api-envoy" || sidecar.NetworkMode != "service:api" {
+		t.Errorf("Sidecar incorrecto: %+v", sidecar)
+	}
+	if len(sidecar.DependsOn) != 1 || sidecar.DependsOn[0] != "api" || len(sidecar.Ports) != 0 {
+		t.Errorf("Dependencias o puertos del sidecar incorrectos: %+v", sidecar)
+	}
+
+	if ports := result.Services["api"].Ports; len(ports) != 2 || ports[1] != "9901:9901" {
+		t.Errorf("Los puertos del sidecar deben publicarse en api: %v", ports)
+	}
+}
@@ -0,0 +1,92 @@
+package compose
+
+import "strings"
+
+// stack es un grupo reutilizable de servicios, por ejemplo "observability" con
+// prometheus, grafana y loki, que se añade a una configuración con AddStack
+type stack struct {
+	name     string
+	services []service
+}
+
+// NewStack define un grupo de servicios. Los servicios se copian como en
+// NewCompose, de modo que el mismo grupo puede añadirse a varios proyectos o
+// varias veces con distinto prefijo
+func NewStack(name string, services ...service) *stack {
+	return &stack{name: name, services: cloneServices(expandSidecars(services))}
+}
+
+// AddStack añade los servicios del grupo con el espacio de nombres prefix:
+// "prometheus" pasa a "<prefix>-prometheus", al igual que sus container_name,
+// depends_on, network_mode "service:", configs y volúmenes con nombre, así dos
+// copias del grupo no chocan. Cada servicio se conecta a una red propia del
+// grupo (prefix, o el nombre del grupo sin prefijo) con su nombre original como
+// alias, por lo que las referencias internas como "http://prometheus:9090"
+// siguen funcionando, y conserva la red por defecto para alcanzar al resto del stack
+func (c *composeConfig) AddStack(st *stack, prefix string) *composeConfig {
+	network := prefix
+	if network == "" {
+		network = st.name
+	}
+
+	rename := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "-" + name
+	}
+	names := make(map[string]string, len(st.services))
+	for _, s := range st.services {
+		names[s.name] = rename(s.name)
+	}
+
+	services := cloneServices(st.services)
+	for i := range services {
+		s := &services[i]
+		original := s.name
+
+		s.name = names[original]
+		if s.containerName == original {
+			s.containerName = s.name
+		}
+		for j, dep := range s.serviceDependencies {
+			if renamed, ok := names[dep]; ok {
+				s.serviceDependencies[j] = renamed
+			}
+		}
+		if target, ok := strings.CutPrefix(s.networkMode, "service:"); ok {
+			if renamed, ok := names[target]; ok {
+				s.networkMode = "service:" + renamed
+			}
+		}
+		for j, config := range s.configs {
+			if config.Target == "" {
+				s.configs[j].Target = "/" + config.Name
+			}
+			s.configs[j].Name = rename(config.Name)
+		}
+		for j, vol := range s.volumes {
+			if namedVolume(vol.Source) {
+				s.volumes[j].Source = rename(vol.Source)
+			}
+		}
+
+		if s.networkMode != "" {
+			continue
+		}
+		if len(s.networks) == 0 {
+			s.AttachNetwork(defaultNetwork)
+		}
+		var attachment NetworkAttachment
+		if s.name != original {
+			attachment.Aliases = []string{original}
+		}
+		s.AttachNetwork(network, attachment)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.services = append(c.services, services...)
+	return c
+}
@@ -0,0 +1,91 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAddStack(t *testing.T) {
+	prometheus := *compose.NewService("prometheus").SetImage("prom/prometheus").
+		AddVolume(compose.Volume{Source: "prom-data", Target: "/prometheus"}).
+		AddConfig(compose.InlineConfig{Name: "prometheus.yml", Target: "/etc/prometheus/prometheus.yml", Content: "scrape_configs: []\n"})
+	grafana := *compose.NewService("grafana").SetImage("grafana/grafana").
+		AddVolume(compose.Volume{Source: "./dashboards", Target: "/var/lib/grafana/dashboards"}).
+		DependsOn(prometheus)
+	observability := compose.NewStack("observability", prometheus, grafana)
+
+	app := *compose.NewService("app").SetImage("app:1.0")
+	config, _ := compose.NewCompose("3.8", app)
+	config.AddStack(observability, "obs").AddStack(observability, "obs2")
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			ContainerName string   `yaml:"container_name"`
+			Volumes       []string `yaml:"volumes"`
+			DependsOn     []string `yaml:"depends_on"`
+			Configs       []struct {
+				Source string `yaml:"source"`
+				Target string `yaml:"target"`
+			} `yaml:"configs"`
+			Networks map[string]struct {
+				Aliases []string `yaml:"aliases"`
+			} `yaml:"networks"`
+		} `yaml:"services"`
+		Networks map[string]any `yaml:"networks"`
+		Configs  map[string]any `yaml:"configs"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	for _, name := range []string{"app", "obs-prometheus", "obs-grafana", "obs2-prometheus", "obs2-grafana"} {
+		if _, ok := result.Services[name]; !ok {
+			t.Errorf("Falta el servicio %s:\n%s", name, data)
+		}
+	}
+
+	prom := result.Services["obs-prometheus"]
+	if prom.ContainerName != "obs-prometheus" {
+		t.Errorf("container_name incorrecto: %q", prom.ContainerName)
+	}
+	if strings.Join(prom.Volumes, ",") != "obs-prom-data:/prometheus" {
+		t.Errorf("Volúmenes incorrectos: %q", prom.Volumes)
+	}
+	if len(prom.Configs) != 1 || prom.Configs[0].Source != "obs-prometheus.yml" || prom.Configs[0].Target != "/etc/prometheus/prometheus.yml" {
+		t.Errorf("Configs incorrectos: %+v", prom.Configs)
+	}
+	if aliases := prom.Networks["obs"].Aliases; len(aliases) != 1 || aliases[0] != "prometheus" {
+		t.Errorf("Se esperaba el alias prometheus en la red obs: %+v", prom.Networks)
+	}
+	if _, ok := prom.Networks["default"]; !ok {
+		t.Errorf("Se esperaba la red default: %+v", prom.Networks)
+	}
+
+	grafanaOut := result.Services["obs2-grafana"]
+	if strings.Join(grafanaOut.DependsOn, ",") != "obs2-prometheus" {
+		t.Errorf("depends_on incorrecto: %q", grafanaOut.DependsOn)
+	}
+	if strings.Join(grafanaOut.Volumes, ",") != "./dashboards:/var/lib/grafana/dashboards" {
+		t.Errorf("Las rutas del host no deben llevar prefijo: %q", grafanaOut.Volumes)
+	}
+
+	for _, name := range []string{"obs", "obs2"} {
+		if _, ok := result.Networks[name]; !ok {
+			t.Errorf("Falta la red %s: %v", name, result.Networks)
+		}
+	}
+	if _, ok := result.Configs["obs2-prometheus.yml"]; !ok {
+		t.Errorf("Falta el config con prefijo: %v", result.Configs)
+	}
+	if _, ok := result.Services["app"].Networks["obs"]; ok {
+		t.Error("Los servicios fuera del grupo no deben cambiar")
+	}
+}
@@ -0,0 +1,120 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PublishedPort es un puerto del contenedor publicado en el host
+type PublishedPort struct {
+	HostIP        string
+	HostPort      int
+	ContainerPort int
+	Protocol      string
+}
+
+// ServiceStatus es el estado de un contenedor del stack según "docker compose ps"
+type ServiceStatus struct {
+	Name           string // servicio
+	Container      string // nombre del contenedor, vacío si no fue creado
+	State          string // running, exited, restarting... o "not created"
+	Health         string // healthy, unhealthy, starting o vacío sin healthcheck
+	ExitCode       int
+	PublishedPorts []PublishedPort
+}
+
+// psEntry es una línea de "docker compose ps --format json"
+type psEntry struct {
+	Name       string
+	Service    string
+	State      string
+	Health     string
+	ExitCode   int
+	Publishers []struct {
+		URL           string
+		TargetPort    int
+		PublishedPort int
+		Protocol      string
+	}
+}
+
+// Status devuelve el estado de cada contenedor del stack a partir de
+// "docker compose ps --all --format json". Los servicios de la configuración
+// sin contenedor se incluyen con State "not created", de modo que el resultado
+// sirve como puerta de readiness o fuente de datos de un dashboard
+func (c *composeConfig) Status(ctx context.Context) ([]ServiceStatus, error) {
+	out, err := c.runCompose(ctx, "ps", "--all", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parsePsOutput(out)
+	if err != nil {
+		return nil, err
+	}
+
+	byService := make(map[string][]ServiceStatus)
+	for _, e := range entries {
+		status := ServiceStatus{Name: e.Service, Container: e.Name, State: e.State, Health: e.Health, ExitCode: e.ExitCode}
+		for _, p := range e.Publishers {
+			if p.PublishedPort == 0 {
+				continue
+			}
+			status.PublishedPorts = append(status.PublishedPorts, PublishedPort{
+				HostIP:        p.URL,
+				HostPort:      p.PublishedPort,
+				ContainerPort: p.TargetPort,
+				Protocol:      p.Protocol,
+			})
+		}
+		byService[e.Service] = append(byService[e.Service], status)
+	}
+
+	// en el orden de la configuración, y al final los contenedores de otros servicios
+	c.mu.Lock()
+	services := c.orderedServices()
+	c.mu.Unlock()
+
+	var statuses []ServiceStatus
+	for _, s := range services {
+		containers, ok := byService[s.name]
+		if !ok {
+			containers = []ServiceStatus{{Name: s.name, State: "not created"}}
+		}
+		statuses = append(statuses, containers...)
+		delete(byService, s.name)
+	}
+	for _, name := range sortedKeys(byService) {
+		statuses = append(statuses, byService[name]...)
+	}
+	return statuses, nil
+}
+
+// parsePsOutput admite los dos formatos de docker compose: un arreglo JSON
+// (versiones anteriores a 2.21) o un objeto JSON por línea
+func parsePsOutput(out []byte) ([]psEntry, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	var entries []psEntry
+	if out[0] == '[' {
+		if err := json.Unmarshal(out, &entries); err != nil {
+			return nil, fmt.Errorf("error parsing docker compose ps output: %w", err)
+		}
+		return entries, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var e psEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("error parsing docker compose ps output: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
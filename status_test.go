@@ -0,0 +1,46 @@
+package compose_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestStatus(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16").AddPort("5432", "5432")
+	api := *compose.NewService("api").SetImage("api:1.0")
+	worker := *compose.NewService("worker").SetImage("worker:1.0")
+	config, _ := compose.NewCompose("3.8", db, api, worker)
+
+	want := []compose.ServiceStatus{
+		{Name: "db", Container: "shop-db-1", State: "running", Health: "healthy",
+			PublishedPorts: []compose.PublishedPort{{HostIP: "0.0.0.0", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"}}},
+		{Name: "api", Container: "shop-api-1", State: "exited", ExitCode: 2},
+		{Name: "worker", State: "not created"},
+	}
+
+	outputs := map[string]string{
+		"Un objeto por línea": `{"Name":"shop-api-1","Service":"api","State":"exited","Health":"","ExitCode":2,"Publishers":[]}
+{"Name":"shop-db-1","Service":"db","State":"running","Health":"healthy","ExitCode":0,"Publishers":[{"URL":"0.0.0.0","TargetPort":5432,"PublishedPort":5432,"Protocol":"tcp"},{"URL":"","TargetPort":8008,"PublishedPort":0,"Protocol":"tcp"}]}`,
+		"Arreglo JSON": `[{"Name":"shop-db-1","Service":"db","State":"running","Health":"healthy","Publishers":[{"URL":"0.0.0.0","TargetPort":5432,"PublishedPort":5432,"Protocol":"tcp"}]},{"Name":"shop-api-1","Service":"api","State":"exited","ExitCode":2}]`,
+	}
+
+	for name, output := range outputs {
+		t.Run(name, func(t *testing.T) {
+			log := fakeDocker(t, "cat <<'EOF'\n"+output+"\nEOF")
+
+			statuses, err := config.Status(context.Background())
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			if !reflect.DeepEqual(statuses, want) {
+				t.Errorf("Estado incorrecto:\n got: %+v\nwant: %+v", statuses, want)
+			}
+			if calls := dockerCalls(t, log); calls[0] != "compose -f docker-compose.yml ps --all --format json" {
+				t.Errorf("Comando incorrecto: %q", calls[0])
+			}
+		})
+	}
+}
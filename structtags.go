@@ -0,0 +1,109 @@
+package compose
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ServiceFromStruct deriva un servicio de las etiquetas del struct de
+// configuración de una aplicación, para mantener ambos sincronizados.
+//
+// Etiquetas soportadas en cualquier campo (incluidos structs anidados):
+//
+//	env:"DB_HOST"                    variable de entorno con el valor del campo,
+//	                                 o ${DB_HOST} si el campo está vacío
+//	compose:"port"                   publica el puerto indicado por el valor del campo
+//	compose:"port=8080"              publica el puerto 8080 (o "host:contenedor")
+//	compose:"name=api,image=app:1"   nombre e imagen del servicio
+//	compose:"build=."                contexto de build
+//	compose:"volume=./data:/data"    volumen del servicio
+//
+// Sin name se usa el nombre del tipo en minúsculas
+func ServiceFromStruct(v any) *service {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			s := NewService("")
+			s.errors = append(s.errors, fmt.Errorf("ServiceFromStruct: nil pointer"))
+			return s
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		s := NewService("")
+		s.errors = append(s.errors, fmt.Errorf("ServiceFromStruct: expected struct, got %s", rv.Kind()))
+		return s
+	}
+
+	s := NewService(strings.ToLower(rv.Type().Name()))
+	applyStructTags(s, rv)
+	return s
+}
+
+// applyStructTags recorre los campos del struct aplicando sus etiquetas al servicio
+func applyStructTags(s *service, rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+
+		if key, ok := field.Tag.Lookup("env"); ok && key != "" && key != "-" {
+			if field.IsExported() && !value.IsZero() {
+				s.AddEnvironment(key, fmt.Sprint(value.Interface()))
+			} else {
+				s.environment[key] = fmt.Sprintf("${%s}", key)
+			}
+		}
+
+		if tag, ok := field.Tag.Lookup("compose"); ok {
+			applyComposeTag(s, field, value, tag)
+		}
+
+		if field.IsExported() && value.Kind() == reflect.Struct && field.Type.PkgPath() != "time" {
+			applyStructTags(s, value)
+		}
+	}
+}
+
+// applyComposeTag interpreta las directivas separadas por comas de compose:"..."
+func applyComposeTag(s *service, field reflect.StructField, value reflect.Value, tag string) {
+	for _, directive := range strings.Split(tag, ",") {
+		key, arg, hasArg := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch key {
+		case "":
+		case "name":
+			if s.containerName == s.name {
+				s.containerName = arg
+			}
+			s.name = arg
+		case "image":
+			s.SetImage(arg)
+		case "build":
+			s.SetBuild(arg)
+		case "port":
+			if !hasArg {
+				if !field.IsExported() || value.IsZero() {
+					s.errors = append(s.errors, fmt.Errorf("field %s: port tag requires a non-zero value", field.Name))
+					continue
+				}
+				arg = fmt.Sprint(value.Interface())
+			}
+			host, container, found := strings.Cut(arg, ":")
+			if !found {
+				container = host
+			}
+			s.AddPort(host, container)
+		case "volume":
+			source, target, found := strings.Cut(arg, ":")
+			if !found {
+				s.errors = append(s.errors, fmt.Errorf("field %s: volume tag must be source:target", field.Name))
+				continue
+			}
+			s.AddVolume(Volume{Source: source, Target: target})
+		default:
+			s.errors = append(s.errors, fmt.Errorf("field %s: unknown compose tag %q", field.Name, key))
+		}
+	}
+}
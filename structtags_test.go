@@ -0,0 +1,65 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+type appDatabase struct {
+	Host     string `env:"DB_HOST"`
+	Password string `env:"DB_PASSWORD"`
+}
+
+type appConfig struct {
+	_        struct{} `compose:"name=api,image=myorg/api:1.4"`
+	HTTPPort int      `compose:"port" env:"HTTP_PORT"`
+	Metrics  bool     `compose:"port=9090"`
+	Database appDatabase
+}
+
+func TestServiceFromStruct(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	cfg := appConfig{HTTPPort: 8080, Database: appDatabase{Host: "db"}}
+
+	api := *compose.ServiceFromStruct(&cfg)
+	config, _ := compose.NewCompose("3.8", api)
+	config.SetEnvStrictness(compose.EnvCheckOff)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Image       string            `yaml:"image"`
+			Ports       []string          `yaml:"ports"`
+			Environment map[string]string `yaml:"environment"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	got, ok := result.Services["api"]
+	if !ok {
+		t.Fatalf("Falta el servicio api:\n%s", data)
+	}
+	if got.Image != "myorg/api:1.4" {
+		t.Errorf("Imagen incorrecta: %q", got.Image)
+	}
+	if len(got.Ports) != 2 || got.Ports[0] != "8080:8080" || got.Ports[1] != "9090:9090" {
+		t.Errorf("Puertos incorrectos: %q", got.Ports)
+	}
+	if got.Environment["HTTP_PORT"] != "8080" || got.Environment["DB_HOST"] != "db" || got.Environment["DB_PASSWORD"] != "${DB_PASSWORD}" {
+		t.Errorf("Entorno incorrecto: %v", got.Environment)
+	}
+
+	invalid, _ := compose.NewCompose("3.8", *compose.ServiceFromStruct(42))
+	if err := invalid.Validate(); err == nil {
+		t.Error("Se esperaba un error con un valor que no es struct")
+	}
+}
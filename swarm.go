@@ -0,0 +1,115 @@
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Target indica para qué herramienta se genera el archivo
+type Target int
+
+const (
+	TargetCompose Target = iota // docker compose (por defecto)
+	TargetSwarm                 // docker stack deploy
+)
+
+// SetTarget elige la herramienta de destino. Con TargetSwarm se omiten los
+// campos que docker stack deploy ignora (build, container_name, depends_on,
+// restart...), cada servicio lleva su bloque deploy con replicas y
+// restart_policy, y Warnings lista lo que se descartó
+func (c *composeConfig) SetTarget(target Target) *composeConfig {
+	c.target = target
+	return c
+}
+
+// swarm indica si el archivo se genera para docker stack deploy
+func (c composeConfig) swarm() bool {
+	return c.target == TargetSwarm
+}
+
+// validateSwarm revisa lo que docker stack deploy exige: un formato 3.x y una
+// imagen en cada servicio, ya que no construye imágenes
+func (c *composeConfig) validateSwarm() []error {
+	if !c.swarm() {
+		return nil
+	}
+
+	var errs []error
+	if !strings.HasPrefix(c.version, "3") {
+		errs = append(errs, fmt.Errorf("swarm stacks require compose file format 3.x, got %q", c.version))
+	}
+	for _, s := range c.services {
+		if s.image == "" {
+			errs = append(errs, &ValidationError{Service: s.name, Field: "image", Err: fmt.Errorf("%w: swarm stacks cannot build images", ErrMissingImage)})
+		}
+	}
+	return errs
+}
+
+// swarmDropped lista los campos que se omiten al generar para swarm
+func (c composeConfig) swarmDropped() []string {
+	if !c.swarm() {
+		return nil
+	}
+
+	var dropped []string
+	for _, s := range c.orderedServices() {
+		fields := []struct {
+			name string
+			set  bool
+		}{
+			{"build", s.build != ""},
+			{"container_name", s.containerName != "" && s.containerName != s.name},
+			{"depends_on", len(s.serviceDependencies) > 0},
+			{"deploy.resources.reservations.devices", len(s.deviceReservations) > 0},
+			{"develop", len(s.developWatch) > 0},
+			{"devices", len(s.devices) > 0},
+			{"network_mode", s.networkMode != ""},
+			{"privileged", s.privileged},
+			{"security_opt", len(s.securityOpt) > 0},
+		}
+		for _, f := range fields {
+			if f.set {
+				dropped = append(dropped, fmt.Sprintf("service %q: %s is ignored by docker stack deploy, dropped", s.name, f.name))
+			}
+		}
+	}
+	return dropped
+}
+
+// writeSwarmDeploy escribe el bloque deploy de swarm: replicas (scale o 1) y la
+// política de reinicio traducida desde restart
+func writeSwarmDeploy(b *strings.Builder, s service) {
+	replicas := 1
+	if s.scale != nil {
+		replicas = *s.scale
+	}
+
+	b.WriteString("    deploy:\n")
+	fmt.Fprintf(b, "      replicas: %d\n", replicas)
+
+	condition, attempts := swarmRestartPolicy(s.restartPolicy)
+	if condition == "" {
+		return
+	}
+	b.WriteString("      restart_policy:\n")
+	fmt.Fprintf(b, "        condition: %s\n", yamlQuote(condition))
+	if attempts > 0 {
+		fmt.Fprintf(b, "        max_attempts: %d\n", attempts)
+	}
+}
+
+// swarmRestartPolicy traduce restart a deploy.restart_policy.condition
+func swarmRestartPolicy(policy string) (string, int) {
+	switch {
+	case policy == RestartAlways || policy == RestartUnlessStopped:
+		return "any", 0
+	case policy == RestartNo:
+		return "none", 0
+	case strings.HasPrefix(policy, RestartOnFailure):
+		attempts, _ := strconv.Atoi(strings.TrimPrefix(policy, RestartOnFailure+":"))
+		return "on-failure", attempts
+	}
+	return "", 0
+}
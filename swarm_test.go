@@ -0,0 +1,72 @@
+package compose_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSwarmTarget(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	db := *compose.NewService("db").SetImage("postgres:16").SetRestartPolicy("on-failure:3")
+	api := *compose.NewService("api").SetImage("api:1.0").
+		SetContainerName("my-api").
+		SetRestartPolicy(compose.RestartUnlessStopped).
+		SetPrivileged(true).
+		SetScale(3).
+		DependsOn(db)
+
+	config, _ := compose.NewCompose("3.8", db, api)
+	config.SetTarget(compose.TargetSwarm)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]map[string]any `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+
+	for name, s := range result.Services {
+		for _, key := range []string{"container_name", "depends_on", "restart", "privileged", "scale"} {
+			if _, ok := s[key]; ok {
+				t.Errorf("%s: %s no debe emitirse para swarm", name, key)
+			}
+		}
+		if _, ok := s["deploy"]; !ok {
+			t.Errorf("%s: falta el bloque deploy", name)
+		}
+	}
+
+	if !strings.Contains(string(data), "      replicas: 3\n      restart_policy:\n        condition: \"any\"\n") {
+		t.Errorf("deploy de api incorrecto:\n%s", data)
+	}
+	if !strings.Contains(string(data), "        condition: \"on-failure\"\n        max_attempts: 3\n") {
+		t.Errorf("deploy de db incorrecto:\n%s", data)
+	}
+
+	warnings := strings.Join(config.Warnings(), "\n")
+	for _, want := range []string{`service "api": container_name`, `service "api": depends_on`, `service "api": privileged`} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Falta el aviso %q en:\n%s", want, warnings)
+		}
+	}
+	if strings.Contains(warnings, `service "db": container_name`) {
+		t.Errorf("El container_name por defecto no debe avisarse:\n%s", warnings)
+	}
+
+	built := *compose.NewService("built").SetBuild(".")
+	config, _ = compose.NewCompose("2.4", built)
+	config.SetTarget(compose.TargetSwarm)
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "3.x") || !strings.Contains(err.Error(), "cannot build") {
+		t.Errorf("Se esperaban errores de versión e imagen, se obtuvo %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultTraefikNetwork es la red compartida con Traefik cuando no se indica otra
+const defaultTraefikNetwork = "traefik"
+
+// TraefikOption configura las etiquetas generadas por ExposeViaTraefik
+type TraefikOption func(*traefikOptions)
+
+type traefikOptions struct {
+	router       string
+	entrypoints  []string
+	certResolver string
+	tls          bool
+	pathPrefix   string
+	middlewares  []string
+	network      string
+}
+
+// TraefikRouter cambia el nombre del router y del servicio de Traefik, por
+// defecto el nombre del servicio. Necesario al exponer varios puertos o hosts
+func TraefikRouter(name string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.router = name
+	}
+}
+
+// TraefikEntrypoints establece los entrypoints del router, por defecto "web"
+// o "websecure" con TLS
+func TraefikEntrypoints(entrypoints ...string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.entrypoints = entrypoints
+	}
+}
+
+// TraefikTLS activa TLS en el router con el certresolver indicado, por ejemplo
+// "letsencrypt". Con certResolver vacío usa los certificados por defecto de Traefik
+func TraefikTLS(certResolver string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.tls = true
+		o.certResolver = certResolver
+	}
+}
+
+// TraefikPathPrefix limita el router a las rutas que empiezan por prefix
+func TraefikPathPrefix(prefix string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.pathPrefix = prefix
+	}
+}
+
+// TraefikMiddlewares aplica middlewares ya definidos en Traefik, por ejemplo "auth@file"
+func TraefikMiddlewares(middlewares ...string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// TraefikNetwork cambia la red compartida con Traefik, por defecto "traefik".
+// La red se declara con name para que compose no le añada el prefijo del
+// proyecto. Si Traefik corre en otro stack hay que declararla con
+// DefineNetwork(name, NetworkConfig{External: true})
+func TraefikNetwork(name string) TraefikOption {
+	return func(o *traefikOptions) {
+		o.network = name
+	}
+}
+
+// ExposeViaTraefik publica el puerto del contenedor en host a través de
+// Traefik: genera las etiquetas traefik.http.routers y traefik.http.services,
+// fija traefik.docker.network y conecta el servicio a esa red, manteniendo la
+// red por defecto para que siga alcanzando al resto del stack
+func (s *service) ExposeViaTraefik(host string, port int, opts ...TraefikOption) *service {
+	o := traefikOptions{router: s.name, network: defaultTraefikNetwork}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if host == "" || strings.ContainsAny(host, "`") {
+		s.errors = append(s.errors, invalidField(s.name, "traefik_host", host))
+		return s
+	}
+	if port < 1 || port > 65535 {
+		s.errors = append(s.errors, &ValidationError{Service: s.name, Field: "traefik_port", Value: fmt.Sprint(port), Err: ErrInvalidPort})
+		return s
+	}
+	if len(o.entrypoints) == 0 {
+		o.entrypoints = []string{"web"}
+		if o.tls {
+			o.entrypoints = []string{"websecure"}
+		}
+	}
+
+	// los puntos separan niveles en las etiquetas de Traefik
+	router := strings.ReplaceAll(o.router, ".", "-")
+	rule := fmt.Sprintf("Host(`%s`)", host)
+	if o.pathPrefix != "" {
+		rule += fmt.Sprintf(" && PathPrefix(`%s`)", o.pathPrefix)
+	}
+
+	routerKey := "traefik.http.routers." + router
+	s.SetLabel("traefik.enable", "true")
+	s.SetLabel(routerKey+".rule", rule)
+	s.SetLabel(routerKey+".entrypoints", strings.Join(o.entrypoints, ","))
+	s.SetLabel(routerKey+".service", router)
+	if o.tls {
+		s.SetLabel(routerKey+".tls", "true")
+		if o.certResolver != "" {
+			s.SetLabel(routerKey+".tls.certresolver", o.certResolver)
+		}
+	}
+	if len(o.middlewares) > 0 {
+		s.SetLabel(routerKey+".middlewares", strings.Join(o.middlewares, ","))
+	}
+	s.SetLabel("traefik.http.services."+router+".loadbalancer.server.port", fmt.Sprint(port))
+	s.SetLabel("traefik.docker.network", o.network)
+
+	if len(s.networks) == 0 && s.networkMode == "" {
+		s.AttachNetwork(defaultNetwork)
+	}
+	if !s.attachedTo(o.network) {
+		s.AttachNetwork(o.network)
+	}
+	return s
+}
+
+// attachedTo indica si el servicio está conectado a la red
+func (s *service) attachedTo(network string) bool {
+	for _, n := range s.networks {
+		if n.name == network {
+			return true
+		}
+	}
+	return false
+}
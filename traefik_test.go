@@ -0,0 +1,112 @@
+package compose_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExposeViaTraefik(t *testing.T) {
+	type serviceYAML struct {
+		Labels   map[string]string   `yaml:"labels"`
+		Networks map[string]struct{} `yaml:"networks"`
+	}
+	parse := func(t *testing.T, data []byte) map[string]serviceYAML {
+		t.Helper()
+		var result struct {
+			Services map[string]serviceYAML `yaml:"services"`
+			Networks map[string]struct {
+				Name string `yaml:"name"`
+			} `yaml:"networks"`
+		}
+		if err := yaml.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Error parseando YAML: %v", err)
+		}
+		// sin name: compose crearía <proyecto>_traefik y la etiqueta no coincidiría
+		if network, ok := result.Networks["traefik"]; !ok || network.Name != "traefik" {
+			t.Errorf("Se esperaba la red traefik declarada con name: traefik:\n%s", data)
+		}
+		return result.Services
+	}
+
+	t.Run("HTTP por defecto", func(t *testing.T) {
+		web := *compose.NewService("web").SetImage("nginx").SetLabel("team", "front").
+			ExposeViaTraefik("app.example.com", 80)
+		config, _ := compose.NewCompose("3.8", web)
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		got := parse(t, data)["web"]
+
+		want := map[string]string{
+			"team":                                               "front",
+			"traefik.enable":                                     "true",
+			"traefik.http.routers.web.rule":                      "Host(`app.example.com`)",
+			"traefik.http.routers.web.entrypoints":               "web",
+			"traefik.http.routers.web.service":                   "web",
+			"traefik.http.services.web.loadbalancer.server.port": "80",
+			"traefik.docker.network":                             "traefik",
+		}
+		if len(got.Labels) != len(want) {
+			t.Errorf("Etiquetas incorrectas: %v", got.Labels)
+		}
+		for k, v := range want {
+			if got.Labels[k] != v {
+				t.Errorf("Etiqueta %s: se esperaba %q, se obtuvo %q", k, v, got.Labels[k])
+			}
+		}
+
+		// conserva la red por defecto para alcanzar al resto del stack
+		if _, ok := got.Networks["default"]; !ok {
+			t.Errorf("Se esperaba la red default: %v", got.Networks)
+		}
+		if _, ok := got.Networks["traefik"]; !ok {
+			t.Errorf("Se esperaba la red traefik: %v", got.Networks)
+		}
+		if err := config.Validate(compose.SchemaStrict); err != nil {
+			t.Errorf("Se esperaba un archivo válido según el schema: %v", err)
+		}
+	})
+
+	t.Run("TLS, ruta y middlewares", func(t *testing.T) {
+		api := *compose.NewService("api.v2").SetImage("api:1.0").
+			ExposeViaTraefik("example.com", 8080,
+				compose.TraefikTLS("letsencrypt"),
+				compose.TraefikPathPrefix("/api"),
+				compose.TraefikMiddlewares("auth@file", "gzip@file"))
+		config, _ := compose.NewCompose("3.8", api)
+
+		data, err := config.Bytes()
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		labels := parse(t, data)["api.v2"].Labels
+
+		checks := map[string]string{
+			"traefik.http.routers.api-v2.rule":                      "Host(`example.com`) && PathPrefix(`/api`)",
+			"traefik.http.routers.api-v2.entrypoints":               "websecure",
+			"traefik.http.routers.api-v2.tls":                       "true",
+			"traefik.http.routers.api-v2.tls.certresolver":          "letsencrypt",
+			"traefik.http.routers.api-v2.middlewares":               "auth@file,gzip@file",
+			"traefik.http.services.api-v2.loadbalancer.server.port": "8080",
+		}
+		for k, v := range checks {
+			if labels[k] != v {
+				t.Errorf("Etiqueta %s: se esperaba %q, se obtuvo %q", k, v, labels[k])
+			}
+		}
+	})
+
+	t.Run("Puerto inválido", func(t *testing.T) {
+		web := *compose.NewService("web").SetImage("nginx").ExposeViaTraefik("app.example.com", 0)
+		config, _ := compose.NewCompose("3.8", web)
+
+		if err := config.Validate(); !errors.Is(err, compose.ErrInvalidPort) {
+			t.Errorf("Se esperaba un error por puerto inválido: %v", err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UpOption configura el comportamiento de Up
+type UpOption func(*upOptions)
+
+type upOptions struct {
+	wait         bool
+	waitFor      []string
+	waitTimeout  time.Duration
+	pollInterval time.Duration
+}
+
+// WithWaitFor hace que Up espere a que los servicios indicados estén sanos
+// (o en ejecución si no tienen healthcheck). Sin argumentos espera a todos.
+func WithWaitFor(services ...string) UpOption {
+	return func(o *upOptions) {
+		o.wait = true
+		o.waitFor = append(o.waitFor, services...)
+	}
+}
+
+// WithWaitTimeout limita el tiempo de espera de WithWaitFor
+func WithWaitTimeout(timeout time.Duration) UpOption {
+	return func(o *upOptions) {
+		o.waitTimeout = timeout
+	}
+}
+
+// WithPollInterval establece cada cuánto se consulta el estado cuando
+// docker compose no soporta --wait y se usa el sondeo propio
+func WithPollInterval(interval time.Duration) UpOption {
+	return func(o *upOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// Up levanta el stack en segundo plano con "docker compose up -d".
+// Con WithWaitFor usa "--wait" cuando la versión de docker compose lo soporta
+// y si no, sondea el estado de los contenedores hasta que estén listos.
+func (c *composeConfig) Up(ctx context.Context, opts ...UpOption) error {
+	o := upOptions{pollInterval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if !o.wait {
+		_, err := c.runCompose(ctx, "up", "-d")
+		return err
+	}
+
+	waitFor := o.waitFor
+	if len(waitFor) == 0 {
+		c.mu.Lock()
+		for _, s := range c.services {
+			waitFor = append(waitFor, s.name)
+		}
+		c.mu.Unlock()
+	}
+
+	if c.supportsUpWait(ctx) {
+		args := []string{"up", "-d", "--wait"}
+		if o.waitTimeout > 0 {
+			args = append(args, "--wait-timeout", fmt.Sprint(int(o.waitTimeout.Seconds())))
+		}
+		// solo se limita el arranque cuando se espera un subconjunto del stack
+		if len(o.waitFor) > 0 {
+			args = append(args, waitFor...)
+		}
+		_, err := c.runCompose(ctx, args...)
+		return err
+	}
+
+	if _, err := c.runCompose(ctx, "up", "-d"); err != nil {
+		return err
+	}
+
+	if o.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.waitTimeout)
+		defer cancel()
+	}
+
+	_, err := c.pollReady(ctx, o.pollInterval, waitFor)
+	return err
+}
+
+// WaitHealthy espera a que los servicios indicados (todos si no se indica
+// ninguno) estén sanos, o en ejecución si no tienen healthcheck, consultando su
+// estado cada segundo hasta que expire el contexto. Devuelve el último estado
+// de cada servicio ("healthy", "running", "starting", "unhealthy", "not
+// created"...), útil por ejemplo para lanzar migraciones tras Save y Up
+func (c *composeConfig) WaitHealthy(ctx context.Context, services ...string) (map[string]string, error) {
+	if len(services) == 0 {
+		c.mu.Lock()
+		for _, s := range c.services {
+			services = append(services, s.name)
+		}
+		c.mu.Unlock()
+	}
+	return c.pollReady(ctx, time.Second, services)
+}
+
+// supportsUpWait indica si docker compose acepta la opción --wait
+func (c *composeConfig) supportsUpWait(ctx context.Context) bool {
+	out, err := c.runCompose(ctx, "up", "--help")
+	return err == nil && strings.Contains(string(out), "--wait")
+}
+
+// pollReady consulta el estado de los servicios hasta que todos estén listos y
+// devuelve el último estado de cada uno
+func (c *composeConfig) pollReady(ctx context.Context, interval time.Duration, services []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(services))
+	pending := make(map[string]string, len(services))
+	for _, name := range services {
+		pending[name] = "unknown"
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name := range pending {
+			status, err := c.containerStatus(ctx, name)
+			if err != nil {
+				pending[name] = err.Error()
+				continue
+			}
+			statuses[name] = status
+			if status == "healthy" || status == "running" {
+				delete(pending, name)
+				continue
+			}
+			pending[name] = status
+		}
+
+		if len(pending) == 0 {
+			return statuses, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			var errs []error
+			for _, name := range services {
+				if status, waiting := pending[name]; waiting {
+					errs = append(errs, fmt.Errorf("service %q not ready: %s", name, status))
+				}
+			}
+			return statuses, errors.Join(append([]error{ctx.Err()}, errs...)...)
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerStatus devuelve el estado de salud del contenedor de un servicio,
+// o su estado de ejecución cuando no tiene healthcheck
+func (c *composeConfig) containerStatus(ctx context.Context, name string) (string, error) {
+	id, err := c.containerID(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "not created", nil
+	}
+
+	out, err := runDocker(ctx, "inspect", "--format",
+		"{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", id)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,101 @@
+package compose_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestUp(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	config, _ := compose.NewCompose("3.8", db)
+
+	t.Run("Usa --wait cuando está disponible", func(t *testing.T) {
+		log := fakeDocker(t, `case "$*" in *--help*) echo "      --wait   Wait for services";; esac`)
+
+		err := config.Up(context.Background(), compose.WithWaitFor("db"), compose.WithWaitTimeout(30*time.Second))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		calls := dockerCalls(t, log)
+		want := "compose -f docker-compose.yml up -d --wait --wait-timeout 30 db"
+		if calls[len(calls)-1] != want {
+			t.Errorf("Comando incorrecto:\nEsperado: %q\nObtenido: %q", want, calls[len(calls)-1])
+		}
+	})
+
+	t.Run("Sondea el estado sin --wait", func(t *testing.T) {
+		log := fakeDocker(t, `case "$*" in
+  *" ps -q "*) echo abc123;;
+  inspect*) echo healthy;;
+esac`)
+
+		err := config.Up(context.Background(), compose.WithWaitFor(), compose.WithPollInterval(10*time.Millisecond))
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		calls := dockerCalls(t, log)
+		if len(calls) != 4 || calls[1] != "compose -f docker-compose.yml up -d" {
+			t.Errorf("Llamadas inesperadas: %q", calls)
+		}
+	})
+
+	t.Run("Agota el tiempo si el servicio no está listo", func(t *testing.T) {
+		fakeDocker(t, `case "$*" in
+  *" ps -q "*) echo abc123;;
+  inspect*) echo starting;;
+esac`)
+
+		err := config.Up(context.Background(), compose.WithWaitFor("db"),
+			compose.WithWaitTimeout(50*time.Millisecond), compose.WithPollInterval(10*time.Millisecond))
+		if err == nil {
+			t.Fatal("Se esperaba un error por tiempo agotado")
+		}
+	})
+}
+
+func TestWaitHealthy(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16")
+	api := *compose.NewService("api").SetImage("api:1.0")
+	config, _ := compose.NewCompose("3.8", db, api)
+
+	t.Run("Devuelve el estado de cada servicio", func(t *testing.T) {
+		fakeDocker(t, `case "$*" in
+  *" ps -q db") echo db1;;
+  *" ps -q api") echo api1;;
+  *db1) echo healthy;;
+  *api1) echo running;;
+esac`)
+
+		statuses, err := config.WaitHealthy(context.Background())
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		if statuses["db"] != "healthy" || statuses["api"] != "running" {
+			t.Errorf("Estados incorrectos: %v", statuses)
+		}
+	})
+
+	t.Run("Informa los servicios pendientes al expirar", func(t *testing.T) {
+		fakeDocker(t, `case "$*" in
+  *" ps -q "*) echo db1;;
+  inspect*) echo starting;;
+esac`)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		statuses, err := config.WaitHealthy(ctx, "db")
+		if err == nil || !strings.Contains(err.Error(), `service "db" not ready`) {
+			t.Fatalf("Se esperaba un error de espera, se obtuvo %v", err)
+		}
+		if statuses["db"] != "starting" {
+			t.Errorf("Estado incorrecto: %v", statuses)
+		}
+	})
+}
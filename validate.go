@@ -0,0 +1,231 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// portPattern acepta [ip:][host:]container[/protocolo] con rangos opcionales
+var portPattern = regexp.MustCompile(`^((\d{1,3}(\.\d{1,3}){3}|\[[0-9a-fA-F:]+\]):)?(\d+(-\d+)?:)?\d+(-\d+)?(/(tcp|udp|sctp))?$`)
+
+// digitsPattern valida el número de reintentos en "on-failure:N"
+var digitsPattern = regexp.MustCompile(`^\d+$`)
+
+// validRestartPolicy indica si la política de reinicio es aceptada por docker compose
+func validRestartPolicy(policy string) bool {
+	switch policy {
+	case "", RestartNo, RestartAlways, RestartUnlessStopped, RestartOnFailure:
+		return true
+	}
+	if retries, ok := strings.CutPrefix(policy, RestartOnFailure+":"); ok {
+		return digitsPattern.MatchString(retries)
+	}
+	return false
+}
+
+// Validate revisa la estructura de la configuración antes de escribir nada a disco.
+// Detecta nombres de servicio o contenedor duplicados, dependencias desconocidas,
+// puertos y políticas de reinicio inválidas y servicios sin imagen ni build.
+// Todos los problemas encontrados se devuelven unidos en un solo error.
+// Con SchemaStrict, si no hay errores estructurales, valida además el YAML
+// generado contra el esquema compose-spec embebido, y con CheckBindMounts que
+// los orígenes de los bind mounts existan.
+// Es seguro llamarlo mientras otras goroutines usan AddService
+func (c *composeConfig) Validate(modes ...ValidationMode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.validate(modes...)
+}
+
+// validate es Validate para quien ya tiene c.mu
+func (c *composeConfig) validate(modes ...ValidationMode) error {
+	var errs []error
+
+	services := make(map[string]bool, len(c.services))
+	containers := make(map[string]string, len(c.services))
+
+	for _, s := range c.services {
+		errs = append(errs, s.errors...)
+
+		if s.name == "" {
+			errs = append(errs, errors.New("service with empty name"))
+		} else if services[s.name] {
+			errs = append(errs, &ValidationError{Service: s.name, Field: "name", Value: s.name, Err: ErrDuplicateService})
+		}
+		services[s.name] = true
+
+		if s.containerName != "" && !s.scaled() {
+			if other, exists := containers[s.containerName]; exists {
+				errs = append(errs, &ValidationError{
+					Service: s.name,
+					Field:   "container_name",
+					Value:   s.containerName,
+					Err:     fmt.Errorf("%w: services %q and %q share container name", ErrDuplicateContainer, other, s.name),
+				})
+			} else {
+				containers[s.containerName] = s.name
+			}
+		}
+
+		if s.image == "" && s.build == "" {
+			errs = append(errs, &ValidationError{Service: s.name, Field: "image", Err: ErrMissingImage})
+		}
+
+		for _, port := range s.ports {
+			if err := validatePort(port); err != nil {
+				errs = append(errs, &ValidationError{Service: s.name, Field: "ports", Value: port, Err: err})
+			}
+		}
+
+		if !validRestartPolicy(s.restartPolicy) {
+			errs = append(errs, &ValidationError{Service: s.name, Field: "restart", Value: s.restartPolicy, Err: ErrInvalidRestartPolicy})
+		}
+
+		errs = append(errs, c.validateNetworkMode(s)...)
+	}
+
+	for _, s := range c.services {
+		for _, dep := range s.serviceDependencies {
+			if !services[dep] {
+				errs = append(errs, &ValidationError{Service: s.name, Field: "depends_on", Value: dep, Err: ErrUnknownDependency})
+			}
+		}
+	}
+
+	errs = append(errs, c.portConflicts()...)
+	errs = append(errs, c.validateFeatures()...)
+	errs = append(errs, c.validateExtensions()...)
+	errs = append(errs, c.validateSwarm()...)
+	errs = append(errs, c.validateVolumes()...)
+	errs = append(errs, c.validateBindMounts(slices.Contains(modes, CheckBindMounts))...)
+
+	if err := c.validateProjectName(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := c.collectConfigs(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.detectCycles(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 && slices.Contains(modes, SchemaStrict) {
+		data, err := c.generateYAML()
+		if err != nil {
+			return err
+		}
+		if err := validateSchema(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		c.log(slog.LevelWarn, "validation failed", "errors", len(errs))
+		return errors.Join(errs...)
+	}
+	for _, s := range c.services {
+		c.log(slog.LevelDebug, "service validated", "service", s.name)
+	}
+	return nil
+}
+
+// detectCycles recorre depends_on en profundidad y devuelve un error con la
+// ruta del primer ciclo encontrado, por ejemplo "a -> b -> a"
+func (c *composeConfig) detectCycles() error {
+	deps := make(map[string][]string, len(c.services))
+	for _, s := range c.services {
+		deps[s.name] = append(deps[s.name], s.serviceDependencies...)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(deps))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			// el ciclo empieza donde aparece name por primera vez en la ruta
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if _, known := deps[dep]; !known {
+				continue
+			}
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, s := range c.services {
+		if cycle := visit(s.name); cycle != nil {
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+	}
+	return nil
+}
+
+// ValidateWithCLI escribe el YAML generado en un archivo temporal junto al
+// compose y ejecuta "docker compose config --quiet" sobre él, para que docker
+// aplique su propio esquema además de la validación interna. El temporal se
+// crea en el mismo directorio para que las rutas relativas y el .env resuelvan igual
+func (c *composeConfig) ValidateWithCLI(ctx context.Context) error {
+	data, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.composeFile()), ".compose-validate-*.yml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+
+	args := []string{"compose"}
+	if c.environment != "" {
+		args = append(args, "--env-file", c.besideComposeFile(c.environment.EnvFile()))
+	}
+	args = append(args, "-f", tmp.Name())
+	if c.projectName != "" {
+		args = append(args, "-p", c.projectName)
+	}
+	if _, err := runDocker(ctx, append(args, "config", "--quiet")...); err != nil {
+		return fmt.Errorf("compose file rejected by docker: %w", err)
+	}
+	return nil
+}
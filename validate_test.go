@@ -0,0 +1,120 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Configuración válida", func(t *testing.T) {
+		db := *compose.NewService("db").SetImage("postgres:16").AddPort("5432", "5432")
+		api := *compose.NewService("api").SetBuild(".").SetRestartPolicy("on-failure:3").DependsOn(db)
+
+		config, _ := compose.NewCompose("3.8", db, api)
+		if err := config.Validate(); err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+	})
+
+	t.Run("Reporta todos los errores juntos", func(t *testing.T) {
+		ghost := *compose.NewService("ghost").SetImage("alpine")
+		a := *compose.NewService("a").SetImage("alpine").AddPort("80a", "80").SetRestartPolicy("allways")
+		b := *compose.NewService("a").SetContainerName("a").DependsOn(ghost)
+
+		config, _ := compose.NewCompose("3.8", a, b)
+		err := config.Validate()
+		if err == nil {
+			t.Fatal("Se esperaba un error de validación")
+		}
+
+		for _, want := range []string{
+			`duplicate service name "a"`,
+			`share container name "a"`,
+			`invalid port "80a:80"`,
+			`invalid restart policy "allways"`,
+			`no image and no build context`,
+			`unknown service "ghost"`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Falta %q en el error:\n%v", want, err)
+			}
+		}
+	})
+}
+
+func TestValidateDependencyCycle(t *testing.T) {
+	a := compose.NewService("a").SetImage("alpine")
+	b := compose.NewService("b").SetImage("alpine")
+	c := compose.NewService("c").SetImage("alpine")
+
+	a.DependsOn(*b)
+	b.DependsOn(*c)
+	c.DependsOn(*a)
+
+	config, _ := compose.NewCompose("3.8", *a, *b, *c)
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Se esperaba un error por dependencia circular")
+	}
+
+	if !strings.Contains(err.Error(), "dependency cycle detected: a -> b -> c -> a") {
+		t.Errorf("Ruta del ciclo incorrecta: %v", err)
+	}
+}
+
+func TestRestartPolicy(t *testing.T) {
+	for _, policy := range []string{
+		compose.RestartNo,
+		compose.RestartAlways,
+		compose.RestartOnFailure,
+		compose.RestartUnlessStopped,
+		compose.RestartOnFailureRetries(5),
+	} {
+		s := *compose.NewService("api").SetImage("api").SetRestartPolicy(policy)
+		config, _ := compose.NewCompose("3.8", s)
+		if err := config.Validate(); err != nil {
+			t.Errorf("Política %q rechazada: %v", policy, err)
+		}
+	}
+
+	for _, policy := range []string{"allways", "on-failure:x", "sometimes"} {
+		s := *compose.NewService("api").SetImage("api").SetRestartPolicy(policy)
+		config, _ := compose.NewCompose("3.8", s)
+		if err := config.Validate(); err == nil || strings.Count(err.Error(), "invalid restart policy") != 1 {
+			t.Errorf("Política %q: se esperaba un único error, obtenido %v", policy, err)
+		}
+	}
+}
+
+func TestValidateWithCLI(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	log := fakeDocker(t, `if grep -q "bad" "$3"; then echo "services.bad additional property" >&2; exit 15; fi`)
+
+	api := *compose.NewService("api").SetImage("nginx")
+	config, _ := compose.NewCompose("3.8", api)
+	if err := config.ValidateWithCLI(context.Background()); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	calls := dockerCalls(t, log)
+	if len(calls) != 1 || !strings.HasPrefix(calls[0], "compose -f ") || !strings.HasSuffix(calls[0], "config --quiet") {
+		t.Errorf("Invocación inesperada: %v", calls)
+	}
+
+	bad := *compose.NewService("bad").SetImage("nginx")
+	config, _ = compose.NewCompose("3.8", bad)
+	err := config.ValidateWithCLI(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "additional property") {
+		t.Errorf("Se esperaba el error de docker, se obtuvo %v", err)
+	}
+
+	entries, _ := os.ReadDir(".")
+	if len(entries) != 0 {
+		t.Errorf("El archivo temporal no se eliminó: %v", entries)
+	}
+}
@@ -0,0 +1,93 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// volumeNamePattern es el formato que docker acepta para volúmenes con nombre
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// errVolumeName aclara que un origen que no es ruta del host se toma como volumen con nombre
+var errVolumeName = fmt.Errorf("%w volume name (host paths must start with /, ./ or ~)", ErrInvalidValue)
+
+// VolumeConfig define un volumen en la sección superior volumes
+type VolumeConfig struct {
+	Driver   string // por ejemplo "local"
+	External bool   // el volumen ya existe y no lo gestiona compose
+	Name     string // nombre real del volumen, sin el prefijo del proyecto
+}
+
+// DefineVolume declara un volumen con su configuración. Los volúmenes con
+// nombre usados con AddVolume que no se definan se declaran con la
+// configuración por defecto, así docker compose no los rechaza como indefinidos
+func (c *composeConfig) DefineVolume(name string, config VolumeConfig) *composeConfig {
+	if c.volumes == nil {
+		c.volumes = make(map[string]VolumeConfig)
+	}
+	c.volumes[name] = config
+	return c
+}
+
+// namedVolume indica si el origen es un volumen con nombre: no es anónimo, ni
+// ruta del host, ni una variable que se resuelve al arrancar
+func namedVolume(source string) bool {
+	return source != "" && !isHostPath(source) && !strings.HasPrefix(source, "$")
+}
+
+// validateVolumes revisa los nombres de los volúmenes declarados con DefineVolume
+func (c *composeConfig) validateVolumes() []error {
+	var errs []error
+	for _, name := range sortedKeys(c.volumes) {
+		if !volumeNamePattern.MatchString(name) {
+			errs = append(errs, &ValidationError{Field: "volumes", Value: name, Err: errVolumeName})
+		}
+	}
+	return errs
+}
+
+// writeVolumes escribe la sección superior volumes con los volúmenes con
+// nombre usados por los servicios, en orden de aparición, y los definidos
+func (c composeConfig) writeVolumes(b *strings.Builder) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, s := range c.services {
+		for _, vol := range s.volumes {
+			if namedVolume(vol.Source) {
+				add(vol.Source)
+			}
+		}
+	}
+	for _, name := range sortedKeys(c.volumes) {
+		add(name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	b.WriteString("volumes:\n")
+	for _, name := range names {
+		config, defined := c.volumes[name]
+		if !defined || config == (VolumeConfig{}) {
+			fmt.Fprintf(b, "  %s: {}\n", yamlPlain(name))
+			continue
+		}
+		fmt.Fprintf(b, "  %s:\n", yamlPlain(name))
+		if config.External {
+			b.WriteString("    external: true\n")
+		}
+		if config.Driver != "" {
+			fmt.Fprintf(b, "    driver: %s\n", yamlQuote(config.Driver))
+		}
+		if config.Name != "" {
+			fmt.Fprintf(b, "    name: %s\n", yamlQuote(config.Name))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package compose_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestVolumesTopLevel(t *testing.T) {
+	db := *compose.NewService("db").SetImage("postgres:16").
+		AddVolume(compose.Volume{Source: "pgdata", Target: "/var/lib/postgresql/data"}).
+		AddVolume(compose.Volume{Source: "./init", Target: "/docker-entrypoint-initdb.d"}).
+		AddVolume(compose.Volume{Source: "${BACKUP_DIR}", Target: "/backup"}).
+		AddVolume(compose.Volume{Target: "/tmp/cache"})
+	app := *compose.NewService("app").SetImage("app:1.0").
+		AddVolume(compose.Volume{Source: "uploads", Target: "/uploads"}).
+		AddVolume(compose.Volume{Source: "pgdata", Target: "/snapshot"})
+
+	config, _ := compose.NewCompose("3.8", db, app)
+	config.DefineVolume("uploads", compose.VolumeConfig{External: true, Name: "shared-uploads"})
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if !strings.Contains(string(data), "volumes:\n  pgdata: {}\n  uploads:\n    external: true\n    name: \"shared-uploads\"\n") {
+		t.Errorf("Sección volumes incorrecta:\n%s", data)
+	}
+
+	var result struct {
+		Volumes map[string]any `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error parseando YAML: %v", err)
+	}
+	if len(result.Volumes) != 2 {
+		t.Errorf("Solo se esperaban los volúmenes con nombre, se obtuvo %v", result.Volumes)
+	}
+
+	if err := config.Validate(compose.SchemaStrict); err != nil {
+		t.Errorf("No se esperaban errores de validación: %v", err)
+	}
+}
+
+func TestVolumesWithoutNamed(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx").
+		AddVolume(compose.Volume{Source: "./html", Target: "/usr/share/nginx/html"})
+	config, _ := compose.NewCompose("3.8", web)
+
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if strings.Contains(string(data), "\nvolumes:") {
+		t.Errorf("No se esperaba la sección volumes:\n%s", data)
+	}
+}
+
+func TestVolumesInvalidName(t *testing.T) {
+	web := *compose.NewService("web").SetImage("nginx").
+		AddVolume(compose.Volume{Source: "my data", Target: "/data"})
+	config, _ := compose.NewCompose("3.8", web)
+	config.DefineVolume("-bad", compose.VolumeConfig{})
+
+	err := config.Validate()
+	if !errors.Is(err, compose.ErrInvalidValue) {
+		t.Fatalf("Se esperaba ErrInvalidValue, se obtuvo %v", err)
+	}
+	for _, want := range []string{`service "web": invalid volume name (host paths must start with /, ./ or ~) "my data"`, `"-bad"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Falta %q en el error: %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WatchConfig es lo que Watch necesita de la configuración regenerada; la
+// cumple el valor devuelto por NewCompose
+type WatchConfig interface {
+	Save(ctx context.Context, opts ...SaveOption) (SaveResult, error)
+	Up(ctx context.Context, opts ...UpOption) error
+}
+
+// WatchOption configura el comportamiento de Watch
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	files    []string
+	interval time.Duration
+	up       bool
+	upOpts   []UpOption
+	saveOpts []SaveOption
+	notify   func(SaveResult, error)
+}
+
+// WatchFiles añade archivos o directorios (recorridos recursivamente) a
+// observar además del .env, por ejemplo plantillas de configuración
+func WatchFiles(paths ...string) WatchOption {
+	return func(o *watchOptions) {
+		o.files = append(o.files, paths...)
+	}
+}
+
+// WatchInterval establece cada cuánto se comprueban los archivos, por defecto un segundo
+func WatchInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.interval = interval
+	}
+}
+
+// WatchUp ejecuta Up con las opciones indicadas cada vez que el archivo
+// generado cambia
+func WatchUp(opts ...UpOption) WatchOption {
+	return func(o *watchOptions) {
+		o.up = true
+		o.upOpts = opts
+	}
+}
+
+// WatchSaveOptions pasa opciones a cada Save, por ejemplo SaveTo
+func WatchSaveOptions(opts ...SaveOption) WatchOption {
+	return func(o *watchOptions) {
+		o.saveOpts = append(o.saveOpts, opts...)
+	}
+}
+
+// WatchNotify llama a fn tras cada regeneración con el resultado de Save y el
+// error de regenerate, Save o Up si lo hubo
+func WatchNotify(fn func(SaveResult, error)) WatchOption {
+	return func(o *watchOptions) {
+		o.notify = fn
+	}
+}
+
+// Watch es un bucle de desarrollo: llama a regenerate, guarda el resultado solo
+// si difiere del archivo actual y, con WatchUp, ejecuta "docker compose up -d"
+// cuando el archivo cambió. Después observa el .env y los archivos de
+// WatchFiles y repite el ciclo cada vez que cambian. Los errores de un ciclo no
+// detienen la observación, se informan con WatchNotify. Termina al cancelar el
+// contexto y devuelve ctx.Err()
+func Watch(ctx context.Context, regenerate func() (WatchConfig, error), opts ...WatchOption) error {
+	o := watchOptions{interval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	files := append([]string{defaultEnvFile}, o.files...)
+
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		o.regenerate(ctx, regenerate)
+		// la instantánea se toma tras regenerar para no reaccionar a los cambios
+		// que el propio Save hace en el .env
+		last := snapshotFiles(files)
+
+		for changed := false; !changed; {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			current := snapshotFiles(files)
+			changed = !sameSnapshot(last, current)
+		}
+	}
+}
+
+// regenerate ejecuta un ciclo de Watch e informa el resultado
+func (o watchOptions) regenerate(ctx context.Context, regenerate func() (WatchConfig, error)) {
+	result, err := func() (SaveResult, error) {
+		config, err := regenerate()
+		if err != nil {
+			return SaveResult{}, err
+		}
+		if config == nil {
+			return SaveResult{}, errors.New("regenerate returned a nil config")
+		}
+
+		result, err := config.Save(ctx, o.saveOpts...)
+		if err != nil || !o.up || result.Status == SaveUnchanged {
+			return result, err
+		}
+		return result, config.Up(ctx, o.upOpts...)
+	}()
+
+	if o.notify != nil && ctx.Err() == nil {
+		o.notify(result, err)
+	}
+}
+
+// fileStamp identifica una versión de un archivo observado
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotFiles registra la versión de cada archivo, recorriendo los
+// directorios. Los archivos inexistentes no aparecen, de modo que crearlos o
+// borrarlos también cuenta como cambio
+func snapshotFiles(paths []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, path := range paths {
+		filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := os.Stat(p); err == nil {
+				stamps[p] = fileStamp{info.ModTime(), info.Size()}
+			}
+			return nil
+		})
+	}
+	return stamps
+}
+
+// sameSnapshot indica si dos instantáneas coinciden
+func sameSnapshot(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if other, ok := b[path]; !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
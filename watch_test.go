@@ -0,0 +1,100 @@
+package compose_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cdvelop/compose"
+)
+
+func TestWatch(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	log := fakeDocker(t, "")
+
+	os.Mkdir("templates", 0755)
+	os.WriteFile("templates/image", []byte("nginx:1.25"), 0644)
+
+	regenerate := func() (compose.WatchConfig, error) {
+		image, err := os.ReadFile("templates/image")
+		if err != nil {
+			return nil, err
+		}
+		web := *compose.NewService("web").SetImage(strings.TrimSpace(string(image)))
+		config, err := compose.NewCompose("3.8", web)
+		return config, err
+	}
+
+	var mu sync.Mutex
+	var statuses []compose.SaveStatus
+	cycles := make(chan struct{}, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- compose.Watch(ctx, regenerate,
+			compose.WatchFiles("templates"),
+			compose.WatchInterval(10*time.Millisecond),
+			compose.WatchUp(),
+			compose.WatchNotify(func(result compose.SaveResult, err error) {
+				if err != nil {
+					t.Errorf("Error inesperado: %v", err)
+				}
+				mu.Lock()
+				statuses = append(statuses, result.Status)
+				mu.Unlock()
+				cycles <- struct{}{}
+			}),
+		)
+	}()
+
+	waitCycle := func() {
+		t.Helper()
+		select {
+		case <-cycles:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Se esperaba una regeneración")
+		}
+	}
+
+	waitCycle()
+	if data := readFile(t, "docker-compose.yml"); !strings.Contains(string(data), "nginx:1.25") {
+		t.Fatalf("Archivo inicial incorrecto:\n%s", data)
+	}
+
+	// cambiar la plantilla regenera el archivo y vuelve a levantar el stack
+	os.WriteFile("templates/image", []byte("nginx:1.27"), 0644)
+	waitCycle()
+	if data := readFile(t, "docker-compose.yml"); !strings.Contains(string(data), "nginx:1.27") {
+		t.Errorf("El archivo no se regeneró:\n%s", data)
+	}
+
+	// un cambio que no altera el modelo no ejecuta up
+	os.WriteFile(filepath.Join("templates", "notes.txt"), []byte("sin efecto"), 0644)
+	waitCycle()
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Se esperaba context.Canceled, se obtuvo %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []compose.SaveStatus{compose.SaveCreated, compose.SaveUpdated, compose.SaveUnchanged}
+	if len(statuses) != len(want) {
+		t.Fatalf("Ciclos incorrectos: %v", statuses)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Errorf("Ciclo %d: se esperaba %v, se obtuvo %v", i, want[i], statuses[i])
+		}
+	}
+	if calls := dockerCalls(t, log); len(calls) != 2 || calls[0] != "compose -f docker-compose.yml up -d" {
+		t.Errorf("Se esperaban dos up -d: %q", calls)
+	}
+}
@@ -0,0 +1,128 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlQuote devuelve s como escalar YAML entre comillas dobles. A diferencia
+// de %q (que usa las reglas de Go) solo emite escapes definidos por YAML 1.2 y
+// deja intactos los caracteres imprimibles, incluido unicode
+func yamlQuote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r == utf8.RuneError && size == 1 {
+			// YAML no puede representar bytes UTF-8 inválidos
+			b.WriteString(`\uFFFD`)
+			continue
+		}
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case 0:
+			b.WriteString(`\0`)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\v':
+			b.WriteString(`\v`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		case 0x1b:
+			b.WriteString(`\e`)
+		case 0x85:
+			b.WriteString(`\N`)
+		case 0xa0:
+			b.WriteString(`\_`)
+		case 0x2028:
+			b.WriteString(`\L`)
+		case 0x2029:
+			b.WriteString(`\P`)
+		default:
+			switch {
+			case yamlPrintable(r):
+				b.WriteRune(r)
+			case r <= 0xff:
+				fmt.Fprintf(&b, `\x%02X`, r)
+			case r <= 0xffff:
+				fmt.Fprintf(&b, `\u%04X`, r)
+			default:
+				fmt.Fprintf(&b, `\U%08X`, r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// yamlPrintable indica si r puede aparecer sin escapar en un documento YAML
+// (se excluye también el BOM, que los parsers descartan)
+func yamlPrintable(r rune) bool {
+	switch {
+	case r >= 0x20 && r <= 0x7e:
+		return true
+	case r >= 0xa0 && r <= 0xd7ff:
+		return true
+	case r >= 0xe000 && r <= 0xfffd:
+		return r != 0xfeff
+	case r >= 0x10000 && r <= 0x10ffff:
+		return true
+	}
+	return false
+}
+
+// plainScalarPattern son los valores que se pueden escribir sin comillas sin
+// que YAML les cambie el significado (sin indicadores, comentarios ni ": ")
+var plainScalarPattern = regexp.MustCompile(`^[A-Za-z0-9_./~$][A-Za-z0-9_./~${}:@+=,-]*$`)
+
+// numberLikePattern detecta valores que YAML leería como números
+var numberLikePattern = regexp.MustCompile(`^[-+]?(\.?[0-9][0-9_.]*([eE][-+]?[0-9]+)?|0[xXoObB][0-9a-fA-F_]+|\.(inf|Inf|INF|nan|NaN|NAN))$`)
+
+// yamlPlain devuelve s sin comillas si es seguro como escalar plano, por
+// ejemplo nombres de servicio o "./data:/var/lib/data", y entre comillas si no
+func yamlPlain(s string) string {
+	if !plainScalarPattern.MatchString(s) || strings.HasSuffix(s, ":") ||
+		numberLikePattern.MatchString(s) || yamlReserved(s) {
+		return yamlQuote(s)
+	}
+	return s
+}
+
+// yamlReserved indica si s se leería como booleano o null en YAML 1.1 o 1.2
+func yamlReserved(s string) bool {
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off", "true", "false", "null", "~":
+		return true
+	}
+	return false
+}
+
+// blockScalarSafe indica si content se puede escribir como bloque literal sin
+// alterarlo: los retornos de carro y los caracteres no imprimibles solo
+// sobreviven entre comillas dobles
+func blockScalarSafe(content string) bool {
+	if !utf8.ValidString(content) {
+		return false
+	}
+	for _, r := range content {
+		if r != '\n' && r != '\t' && !yamlPrintable(r) {
+			return false
+		}
+	}
+	return true
+}
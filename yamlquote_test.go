@@ -0,0 +1,60 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/cdvelop/compose"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLEscaping(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	values := map[string]string{
+		"WIN_PATH":  `C:\Program Files\app\`,
+		"QUOTES":    `say "hi" and 'bye'`,
+		"UNICODE":   "café ñandú 日本 🚀",
+		"CONTROL":   "bell\a tab\t esc\x1b nul\x00 del\x7f",
+		"SEPARATOR": "line\u2028para\u2029nbsp\u00a0",
+		"CRLF":      "first\r\nsecond\r\n",
+		"INDENTED":  "\n  indented\nplain\n",
+		"COMMENT":   "value # not a comment",
+	}
+
+	api := *compose.NewService("api").SetImage("nginx").
+		AddEnvironmentMap(values).
+		AddVolume(compose.Volume{Source: "./my data #1", Target: "/data"}).
+		AddVolume(compose.Volume{Source: "./plain", Target: "/plain"}).
+		SetCommand(`echo "a\b"`, "ünïcode").
+		SetSysctl("net.core.somaxconn", "1024")
+
+	config, _ := compose.NewCompose("3.8", api)
+	data, err := config.Bytes()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	var result struct {
+		Services map[string]struct {
+			Environment map[string]string `yaml:"environment"`
+			Volumes     []string          `yaml:"volumes"`
+			Command     []string          `yaml:"command"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("YAML inválido: %v\n%s", err, data)
+	}
+
+	got := result.Services["api"]
+	for key, want := range values {
+		if got.Environment[key] != want {
+			t.Errorf("%s: se esperaba %q, se obtuvo %q", key, want, got.Environment[key])
+		}
+	}
+	if len(got.Volumes) != 2 || got.Volumes[0] != "./my data #1:/data" || got.Volumes[1] != "./plain:/plain" {
+		t.Errorf("Volúmenes incorrectos: %q", got.Volumes)
+	}
+	if len(got.Command) != 2 || got.Command[0] != `echo "a\b"` || got.Command[1] != "ünïcode" {
+		t.Errorf("Comando incorrecto: %q", got.Command)
+	}
+}